@@ -20,6 +20,10 @@ func main() {
 	cfg := config.LoadServerConfig()
 	echo := app.InitServer(cfg, dbPool)
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	schedulerDone := app.StartScheduler(workersCtx, cfg, dbPool)
+	sweeperDone := app.StartEscrowSweeper(workersCtx, cfg, dbPool)
+
 	go func() {
 		if err := echo.Start(fmt.Sprintf(":%d", cfg.HTTPServer.Port)); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %s", err)
@@ -39,6 +43,11 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %s", err)
 	}
 
+	log.Println("Stopping background workers...")
+	stopWorkers()
+	<-schedulerDone
+	<-sweeperDone
+
 	log.Println("Closing database connection...")
 	dbPool.Close()
 
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/config"
+	"github.com/vit6556/avito-internship-assignment/internal/database/postgres"
+	"github.com/vit6556/avito-internship-assignment/internal/service/transaction"
+)
+
+// StartEscrowSweeper refunds expired pending transfers until ctx is
+// cancelled. The returned channel is closed once the sweeper has stopped.
+func StartEscrowSweeper(ctx context.Context, cfg *config.ServerConfig, dbPool *pgxpool.Pool) <-chan struct{} {
+	escrowService := transactionservice.NewEscrowService(postgres.NewEmployeeRepository(dbPool), postgres.NewEscrowRepository(dbPool), cfg.Escrow.TTL)
+	sweeper := transactionservice.NewEscrowSweeper(escrowService, cfg.Escrow.SweepInterval)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sweeper.Run(ctx)
+	}()
+
+	return done
+}
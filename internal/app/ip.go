@@ -0,0 +1,30 @@
+package app
+
+import (
+	"log"
+	"net"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/config"
+)
+
+// initIPExtractor takes the client address from X-Forwarded-For only when the
+// request comes through one of the trusted proxies. Without trusted proxies
+// the address of the connection is used and forwarding headers are ignored.
+func initIPExtractor(cfg *config.ServerConfig) echo.IPExtractor {
+	if len(cfg.HTTPServer.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range cfg.HTTPServer.TrustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatalf("invalid trusted proxy %q: %s", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
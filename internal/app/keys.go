@@ -0,0 +1,34 @@
+package app
+
+import (
+	"log"
+
+	"github.com/vit6556/avito-internship-assignment/internal/config"
+	"github.com/vit6556/avito-internship-assignment/internal/service/auth"
+)
+
+func initKeySet(cfg *config.ServerConfig) *authservice.KeySet {
+	if len(cfg.JWT.Keys) == 0 {
+		keys, err := authservice.NewKeySet("", authservice.NewHMACKey(cfg.Secret))
+		if err != nil {
+			log.Fatalf("failed to init jwt keys: %s", err)
+		}
+		return keys
+	}
+
+	signingKeys := make([]*authservice.SigningKey, 0, len(cfg.JWT.Keys))
+	for _, keyCfg := range cfg.JWT.Keys {
+		key, err := authservice.LoadSigningKey(keyCfg.ID, keyCfg.PrivateKeyPath, keyCfg.PublicKeyPath)
+		if err != nil {
+			log.Fatalf("failed to load jwt key: %s", err)
+		}
+		signingKeys = append(signingKeys, key)
+	}
+
+	keys, err := authservice.NewKeySet(cfg.JWT.SigningKeyID, signingKeys...)
+	if err != nil {
+		log.Fatalf("failed to init jwt keys: %s", err)
+	}
+
+	return keys
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/vit6556/avito-internship-assignment/internal/config"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/oidc"
+)
+
+func initOIDCService(cfg *config.ServerConfig, authService service.AuthService, stateRepo database.OIDCStateRepository) *oidcservice.OIDCService {
+	if cfg.OIDC.Issuer == "" || cfg.OIDC.ClientID == "" || cfg.OIDC.RedirectURL == "" {
+		log.Fatal("oidc issuer, client_id and redirect_url must be set when oidc is enabled")
+	}
+
+	return oidcservice.NewOIDCService(authService, stateRepo, oidcservice.Config{
+		Issuer:        cfg.OIDC.Issuer,
+		ClientID:      cfg.OIDC.ClientID,
+		ClientSecret:  cfg.OIDC.ClientSecret,
+		RedirectURL:   cfg.OIDC.RedirectURL,
+		Scopes:        cfg.OIDC.Scopes,
+		UsernameClaim: cfg.OIDC.UsernameClaim,
+		StateTTL:      cfg.OIDC.StateTTL,
+	}, &http.Client{Timeout: cfg.OIDC.Timeout})
+}
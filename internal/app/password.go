@@ -0,0 +1,25 @@
+package app
+
+import (
+	"log"
+
+	"github.com/vit6556/avito-internship-assignment/internal/config"
+	"github.com/vit6556/avito-internship-assignment/internal/service/auth"
+)
+
+func initPasswordHasher(cfg *config.ServerConfig) *authservice.PasswordHasher {
+	hasher, err := authservice.NewPasswordHasher(authservice.HashingParams{
+		Algorithm:   cfg.PasswordHashing.Algorithm,
+		Memory:      cfg.PasswordHashing.Memory,
+		Iterations:  cfg.PasswordHashing.Iterations,
+		Parallelism: cfg.PasswordHashing.Parallelism,
+		SaltLength:  cfg.PasswordHashing.SaltLength,
+		KeyLength:   cfg.PasswordHashing.KeyLength,
+		BcryptCost:  cfg.PasswordHashing.BcryptCost,
+	})
+	if err != nil {
+		log.Fatalf("failed to init password hasher: %s", err)
+	}
+
+	return hasher
+}
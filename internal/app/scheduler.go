@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/config"
+	"github.com/vit6556/avito-internship-assignment/internal/database/postgres"
+	"github.com/vit6556/avito-internship-assignment/internal/service/schedule"
+	"github.com/vit6556/avito-internship-assignment/internal/service/transaction"
+)
+
+// schedulerLockKey identifies the advisory lock held by the instance that
+// runs scheduled transfers.
+const schedulerLockKey int64 = 7_300_001
+
+func initScheduleLocation(cfg *config.ServerConfig) *time.Location {
+	location, err := time.LoadLocation(cfg.Scheduler.Timezone)
+	if err != nil {
+		log.Fatalf("failed to load scheduler timezone %q: %s", cfg.Scheduler.Timezone, err)
+	}
+
+	return location
+}
+
+// StartScheduler runs the scheduled transfer worker until ctx is cancelled.
+// The returned channel is closed once the worker has stopped.
+func StartScheduler(ctx context.Context, cfg *config.ServerConfig, dbPool *pgxpool.Pool) <-chan struct{} {
+	done := make(chan struct{})
+	if !cfg.Scheduler.Enabled {
+		close(done)
+		return done
+	}
+
+	employeeRepo := postgres.NewEmployeeRepository(dbPool)
+	transactionService := transactionservice.NewTransactionService(employeeRepo, postgres.NewTransaction(dbPool))
+	scheduleService := scheduleservice.NewScheduleService(employeeRepo, postgres.NewScheduleRepository(dbPool), transactionService, initScheduleLocation(cfg))
+	worker := scheduleservice.NewWorker(scheduleService, postgres.NewAdvisoryLock(dbPool, schedulerLockKey), cfg.Scheduler.PollInterval)
+
+	go func() {
+		defer close(done)
+		worker.Run(ctx)
+	}()
+
+	return done
+}
@@ -9,9 +9,16 @@ import (
 	"github.com/vit6556/avito-internship-assignment/internal/database/postgres"
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
 	httpmiddleware "github.com/vit6556/avito-internship-assignment/internal/delivery/http/middleware"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service/apikey"
 	"github.com/vit6556/avito-internship-assignment/internal/service/auth"
+	"github.com/vit6556/avito-internship-assignment/internal/service/catalog"
+	"github.com/vit6556/avito-internship-assignment/internal/service/dispute"
 	"github.com/vit6556/avito-internship-assignment/internal/service/employee"
+	"github.com/vit6556/avito-internship-assignment/internal/service/idempotency"
 	"github.com/vit6556/avito-internship-assignment/internal/service/merch"
+	"github.com/vit6556/avito-internship-assignment/internal/service/schedule"
+	"github.com/vit6556/avito-internship-assignment/internal/service/throttle"
 	"github.com/vit6556/avito-internship-assignment/internal/service/transaction"
 )
 
@@ -19,27 +26,126 @@ func InitServer(cfg *config.ServerConfig, dbPool *pgxpool.Pool) *echo.Echo {
 	employeeRepo := postgres.NewEmployeeRepository(dbPool)
 	merchRepo := postgres.NewMerchRepository(dbPool)
 	transactionRepo := postgres.NewTransaction(dbPool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(dbPool)
+	tokenRepo := postgres.NewTokenRepository(dbPool)
+	loginThrottleRepo := postgres.NewLoginThrottleRepository(dbPool)
+	apiKeyRepo := postgres.NewAPIKeyRepository(dbPool)
+	scheduleRepo := postgres.NewScheduleRepository(dbPool)
+	escrowRepo := postgres.NewEscrowRepository(dbPool)
+	disputeRepo := postgres.NewDisputeRepository(dbPool)
+	orderRepo := postgres.NewOrderRepository(dbPool)
+	promotionRepo := postgres.NewPromotionRepository(dbPool)
 
-	authService := authservice.NewAuthService(employeeRepo, cfg.Secret, cfg.TokenTTL, cfg.User.DefaultBalance)
+	registrationPolicy := authservice.RegistrationPolicy{
+		AutoProvision:    cfg.Registration.AutoProvision,
+		InviteCode:       cfg.Registration.InviteCode,
+		AllowedUsernames: cfg.Registration.AllowedUsernames,
+	}
+
+	throttleService := throttleservice.NewLoginThrottleService(employeeRepo, loginThrottleRepo, throttleservice.Policy{
+		MaxFailures:      cfg.LoginThrottle.MaxFailures,
+		MaxFailuresPerIP: cfg.LoginThrottle.MaxFailuresPerIP,
+		Window:           cfg.LoginThrottle.Window,
+		BaseLockout:      cfg.LoginThrottle.BaseLockout,
+		MaxLockout:       cfg.LoginThrottle.MaxLockout,
+	})
+	authService := authservice.NewAuthService(employeeRepo, tokenRepo, throttleService, initKeySet(cfg), initPasswordHasher(cfg), cfg.TokenTTL, cfg.RefreshTokenTTL, cfg.PasswordResetTTL, cfg.User.DefaultBalance, registrationPolicy)
 	employeeService := employeeservice.NewEmployeeService(employeeRepo, merchRepo, transactionRepo)
 	transactionService := transactionservice.NewTransactionService(employeeRepo, transactionRepo)
-	merchService := merchservice.NewMerchService(employeeRepo, merchRepo)
+	escrowService := transactionservice.NewEscrowService(employeeRepo, escrowRepo, cfg.Escrow.TTL)
+	merchService := merchservice.NewMerchService(employeeRepo, merchRepo, promotionRepo)
+	orderService := merchservice.NewOrderService(orderRepo, cfg.Orders.CancelWindow)
+	promotionService := merchservice.NewPromotionService(merchRepo, promotionRepo)
+	catalogService := catalogservice.NewMerchCatalogService(merchRepo, promotionRepo)
+	idempotencyService := idempotencyservice.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.TTL)
+	apiKeyService := apikeyservice.NewAPIKeyService(employeeRepo, apiKeyRepo)
+	scheduleService := scheduleservice.NewScheduleService(employeeRepo, scheduleRepo, transactionService, initScheduleLocation(cfg))
+	disputeService := disputeservice.NewDisputeService(disputeRepo)
 
-	jwtMiddleware := httpmiddleware.JWTMiddleware(authService)
+	jwtMiddleware := httpmiddleware.JWTMiddleware(authService, apiKeyService)
+	coinsReadAuth := httpmiddleware.JWTMiddleware(authService, apiKeyService, entity.ScopeCoinsRead)
+	coinsSendAuth := httpmiddleware.JWTMiddleware(authService, apiKeyService, entity.ScopeCoinsSend)
+	merchReadAuth := httpmiddleware.JWTMiddleware(authService, apiKeyService, entity.ScopeMerchRead)
+	merchBuyAuth := httpmiddleware.JWTMiddleware(authService, apiKeyService, entity.ScopeMerchBuy)
+	idempotencyMiddleware := httpmiddleware.IdempotencyMiddleware(idempotencyService)
+	adminOnly := httpmiddleware.RequireRole(entity.RoleAdmin)
+	hrOnly := httpmiddleware.RequireRole(entity.RoleAdmin, entity.RoleHR)
+	fulfilmentOnly := httpmiddleware.RequireRole(entity.RoleAdmin, entity.RoleOfficeManager)
 
-	authHandler := httphandler.NewAuthHandler(authService, cfg.TokenTTL, cfg.HTTPServer.Secure)
+	authHandler := httphandler.NewAuthHandler(authService, throttleService, cfg.TokenTTL, cfg.HTTPServer.Secure)
 	employeeHandler := httphandler.NewEmployeeHandler(employeeService)
 	transactionHandler := httphandler.NewTransactionHandler(transactionService)
+	escrowHandler := httphandler.NewEscrowHandler(escrowService)
 	merchHandler := httphandler.NewMerchHandler(merchService)
+	orderHandler := httphandler.NewOrderHandler(orderService)
+	catalogHandler := httphandler.NewMerchCatalogHandler(catalogService)
+	promotionHandler := httphandler.NewPromotionHandler(promotionService)
+	apiKeyHandler := httphandler.NewAPIKeyHandler(apiKeyService)
+	scheduleHandler := httphandler.NewScheduleHandler(scheduleService)
+	disputeHandler := httphandler.NewDisputeHandler(disputeService)
 
 	e := echo.New()
+	e.IPExtractor = initIPExtractor(cfg)
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
+	e.GET("/.well-known/jwks.json", authHandler.GetJWKS)
 	e.POST("/api/auth", authHandler.GetToken)
-	e.GET("/api/info", employeeHandler.GetEmployeeInfo, jwtMiddleware)
-	e.POST("/api/sendCoin", transactionHandler.SendCoin, jwtMiddleware)
-	e.GET("/api/buy/:item", merchHandler.BuyItem, jwtMiddleware)
+	e.POST("/api/register", authHandler.Register)
+	e.POST("/api/auth/refresh", authHandler.RefreshToken)
+	e.POST("/api/auth/logout", authHandler.Logout, jwtMiddleware)
+	if cfg.OIDC.Enabled {
+		oidcHandler := httphandler.NewOIDCHandler(initOIDCService(cfg, authService, postgres.NewOIDCStateRepository(dbPool)))
+		e.GET("/api/auth/oidc/login", oidcHandler.Login)
+		e.GET("/api/auth/oidc/callback", oidcHandler.Callback)
+	}
+	e.POST("/api/password", authHandler.ChangePassword, jwtMiddleware)
+	e.POST("/api/password/reset", authHandler.ResetPassword)
+	e.GET("/api/info", employeeHandler.GetEmployeeInfo, coinsReadAuth)
+	e.POST("/api/sendCoin", transactionHandler.SendCoin, coinsSendAuth, idempotencyMiddleware)
+	e.GET("/api/history", transactionHandler.GetHistory, coinsReadAuth)
+	e.POST("/api/transfers/pending", escrowHandler.HoldCoins, coinsSendAuth, idempotencyMiddleware)
+	e.GET("/api/transfers/pending", escrowHandler.ListPending, coinsReadAuth)
+	e.POST("/api/transfers/pending/:id/accept", escrowHandler.AcceptTransfer, jwtMiddleware)
+	e.POST("/api/transfers/pending/:id/decline", escrowHandler.DeclineTransfer, jwtMiddleware)
+	e.GET("/api/buy/:item", merchHandler.BuyItem, merchBuyAuth, idempotencyMiddleware)
+	e.POST("/api/buy", merchHandler.Checkout, merchBuyAuth, idempotencyMiddleware)
+	e.GET("/api/orders", orderHandler.ListOrders, merchReadAuth)
+	e.POST("/api/orders/:id/cancel", orderHandler.CancelOrder, jwtMiddleware)
+	e.GET("/api/merch", catalogHandler.ListItems, merchReadAuth)
+	e.GET("/api/merch/:name", catalogHandler.GetItem, merchReadAuth)
+	e.POST("/api/schedules", scheduleHandler.CreateSchedule, jwtMiddleware)
+	e.GET("/api/schedules", scheduleHandler.ListSchedules, jwtMiddleware)
+	e.GET("/api/schedules/:id", scheduleHandler.GetSchedule, jwtMiddleware)
+	e.PUT("/api/schedules/:id", scheduleHandler.UpdateSchedule, jwtMiddleware)
+	e.DELETE("/api/schedules/:id", scheduleHandler.DeleteSchedule, jwtMiddleware)
+	e.GET("/api/schedules/:id/runs", scheduleHandler.ListRuns, jwtMiddleware)
+	e.POST("/api/transactions/:id/disputes", disputeHandler.OpenDispute, jwtMiddleware)
+	e.GET("/api/disputes", disputeHandler.ListDisputes, jwtMiddleware)
+
+	admin := e.Group("/api/admin", jwtMiddleware)
+	admin.POST("/merch", catalogHandler.CreateItem, adminOnly)
+	admin.PATCH("/merch/:name", catalogHandler.UpdatePrice, adminOnly)
+	admin.DELETE("/merch/:name", catalogHandler.RetireItem, adminOnly)
+	admin.PUT("/merch/:name/category", catalogHandler.SetCategory, adminOnly)
+	admin.POST("/merch/:name/restock", catalogHandler.Restock, adminOnly)
+	admin.POST("/promotions", promotionHandler.CreatePromotion, adminOnly)
+	admin.GET("/promotions", promotionHandler.ListPromotions, adminOnly)
+	admin.DELETE("/promotions/:id", promotionHandler.DisablePromotion, adminOnly)
+	admin.GET("/orders", orderHandler.ListAllOrders, fulfilmentOnly)
+	admin.POST("/orders/:id/advance", orderHandler.AdvanceOrder, fulfilmentOnly)
+	admin.POST("/orders/:id/refund", orderHandler.RefundOrder, adminOnly)
+	admin.PUT("/employees/:username/role", employeeHandler.SetRole, adminOnly)
+	admin.PUT("/employees/:username/office", employeeHandler.SetOffice, adminOnly)
+	admin.POST("/employees/:username/unlock", authHandler.UnlockEmployee, adminOnly)
+	admin.POST("/employees/:username/password-reset", authHandler.IssuePasswordReset, adminOnly)
+	admin.POST("/grants", transactionHandler.IssueCoins, hrOnly)
+	admin.POST("/api-keys", apiKeyHandler.CreateKey, adminOnly)
+	admin.GET("/api-keys", apiKeyHandler.ListKeys, adminOnly)
+	admin.DELETE("/api-keys/:id", apiKeyHandler.RevokeKey, adminOnly)
+	admin.GET("/disputes", disputeHandler.ListAllDisputes, adminOnly)
+	admin.POST("/disputes/:id/approve", disputeHandler.ApproveDispute, adminOnly)
+	admin.POST("/disputes/:id/reject", disputeHandler.RejectDispute, adminOnly)
 
 	return e
 }
@@ -9,11 +9,22 @@ import (
 )
 
 type ServerConfig struct {
-	Env        string        `yaml:"env" env-required:"true"`
-	Secret     string        `yaml:"secret" env-required:"true"`
-	User       User          `yaml:"user" env-required:"true"`
-	TokenTTL   time.Duration `yaml:"token_ttl" env-required:"true"`
-	HTTPServer HTTPServer    `yaml:"http_server" env-required:"true"`
+	Env              string          `yaml:"env" env-required:"true"`
+	Secret           string          `yaml:"secret" env-required:"true"`
+	User             User            `yaml:"user" env-required:"true"`
+	TokenTTL         time.Duration   `yaml:"token_ttl" env-required:"true"`
+	RefreshTokenTTL  time.Duration   `yaml:"refresh_token_ttl" env-default:"720h"`
+	PasswordResetTTL time.Duration   `yaml:"password_reset_ttl" env-default:"1h"`
+	JWT              JWT             `yaml:"jwt"`
+	Registration     Registration    `yaml:"registration"`
+	LoginThrottle    LoginThrottle   `yaml:"login_throttle"`
+	PasswordHashing  PasswordHashing `yaml:"password_hashing"`
+	OIDC             OIDC            `yaml:"oidc"`
+	HTTPServer       HTTPServer      `yaml:"http_server" env-required:"true"`
+	Idempotency      Idempotency     `yaml:"idempotency"`
+	Scheduler        Scheduler       `yaml:"scheduler"`
+	Escrow           Escrow          `yaml:"escrow"`
+	Orders           Orders          `yaml:"orders"`
 }
 
 type HTTPServer struct {
@@ -21,6 +32,9 @@ type HTTPServer struct {
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 	Secure      bool          `yaml:"secure" env-default:"true"`
+	// TrustedProxies are the CIDR ranges of the reverse proxies whose
+	// X-Forwarded-For header is trusted.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -31,6 +45,88 @@ type DatabaseConfig struct {
 	Password string `env:"DATABASE_PASSWORD" env-required:"true"`
 }
 
+// JWT configures asymmetric token signing. When no keys are listed, tokens
+// are signed with Secret using HS256.
+type JWT struct {
+	SigningKeyID string   `yaml:"signing_key_id"`
+	Keys         []JWTKey `yaml:"keys"`
+}
+
+type JWTKey struct {
+	ID             string `yaml:"id"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+	PublicKeyPath  string `yaml:"public_key_path"`
+}
+
+type Registration struct {
+	// AutoProvision keeps the old behaviour of creating unknown employees on login.
+	AutoProvision    bool     `yaml:"auto_provision" env-default:"false"`
+	InviteCode       string   `yaml:"invite_code" env:"REGISTRATION_INVITE_CODE"`
+	AllowedUsernames []string `yaml:"allowed_usernames"`
+}
+
+type LoginThrottle struct {
+	MaxFailures      int           `yaml:"max_failures" env-default:"5"`
+	MaxFailuresPerIP int           `yaml:"max_failures_per_ip" env-default:"20"`
+	Window           time.Duration `yaml:"window" env-default:"15m"`
+	BaseLockout      time.Duration `yaml:"base_lockout" env-default:"1m"`
+	MaxLockout       time.Duration `yaml:"max_lockout" env-default:"1h"`
+}
+
+// OIDC enables login through a corporate OpenID Connect provider alongside
+// password login.
+type OIDC struct {
+	Enabled      bool     `yaml:"enabled" env-default:"false"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret" env:"OIDC_CLIENT_SECRET"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes" env-default:"openid,profile"`
+	// UsernameClaim names the ID token claim mapped to the employee username.
+	UsernameClaim string        `yaml:"username_claim" env-default:"preferred_username"`
+	StateTTL      time.Duration `yaml:"state_ttl" env-default:"10m"`
+	Timeout       time.Duration `yaml:"timeout" env-default:"5s"`
+}
+
+// PasswordHashing configures hashes of new and changed passwords. Stored
+// hashes made with another algorithm or cost are upgraded on the next login.
+// The argon2id defaults are the OWASP baseline; every login allocates Memory
+// KiB, so raising it limits how many logins a container can serve at once.
+type PasswordHashing struct {
+	Algorithm   string `yaml:"algorithm" env-default:"argon2id"`
+	Memory      uint32 `yaml:"memory" env-default:"19456"`
+	Iterations  uint32 `yaml:"iterations" env-default:"2"`
+	Parallelism uint8  `yaml:"parallelism" env-default:"1"`
+	SaltLength  uint32 `yaml:"salt_length" env-default:"16"`
+	KeyLength   uint32 `yaml:"key_length" env-default:"32"`
+	BcryptCost  int    `yaml:"bcrypt_cost" env-default:"10"`
+}
+
+type Idempotency struct {
+	TTL time.Duration `yaml:"ttl" env-default:"24h"`
+}
+
+// Scheduler runs scheduled coin transfers in the background. Every instance
+// may enable it: a Postgres advisory lock makes sure only one runs transfers.
+type Scheduler struct {
+	Enabled      bool          `yaml:"enabled" env-default:"true"`
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"30s"`
+	// Timezone is the IANA time zone cron expressions are evaluated in.
+	Timezone string `yaml:"timezone" env-default:"UTC"`
+}
+
+// Escrow configures pending transfers, which the recipient has to accept.
+type Escrow struct {
+	// TTL is how long a pending transfer waits before it is refunded.
+	TTL           time.Duration `yaml:"ttl" env-default:"72h"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"1m"`
+}
+
+type Orders struct {
+	// CancelWindow is how long after placing an order the employee can cancel it.
+	CancelWindow time.Duration `yaml:"cancel_window" env-default:"1h"`
+}
+
 type User struct {
 	DefaultBalance int `yaml:"default_balance" env-required:"true"`
 }
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/vit6556/avito-internship-assignment/internal/entity"
 )
@@ -10,31 +11,167 @@ import (
 var (
 	ErrEmployeeNotFound       = errors.New("employee not found")
 	ErrEmployeeCreationFailed = errors.New("failed to create Employee")
+	ErrEmployeeAlreadyExists  = errors.New("employee already exists")
 
-	ErrMerchNotFound     = errors.New("merch not found")
-	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrMerchNotFound      = errors.New("merch not found")
+	ErrMerchAlreadyExists = errors.New("merch already exists")
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+	ErrOutOfStock         = errors.New("merch out of stock")
+
+	ErrPromotionNotFound      = errors.New("promotion not found")
+	ErrPromoCodeAlreadyExists = errors.New("promo code already exists")
+	ErrPromoCodeNotFound      = errors.New("promo code not found")
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to the order")
+	ErrPromoCodeExhausted     = errors.New("promo code usage limit reached")
+
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrOrderTransition   = errors.New("order status does not allow this change")
+	ErrCancelWindowEnded = errors.New("order cancellation window has ended")
+
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token reused")
+
+	ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
+
+	ErrOIDCStateNotFound = errors.New("oidc login state not found")
+
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	ErrScheduleNotFound = errors.New("scheduled transfer not found")
+
+	ErrPendingTransferNotFound = errors.New("pending transfer not found")
+	ErrPendingTransferResolved = errors.New("pending transfer already resolved")
+
+	ErrTransferNotFound       = errors.New("transfer not found")
+	ErrTransferNotDisputable  = errors.New("transfer cannot be disputed")
+	ErrDisputeNotFound        = errors.New("dispute not found")
+	ErrDisputeAlreadyOpen     = errors.New("transfer already has an open or approved dispute")
+	ErrDisputeAlreadyResolved = errors.New("dispute already resolved")
 
 	ErrDatabaseQueryFailed  = errors.New("database query failed")
 	ErrDatabaseScanFailed   = errors.New("failed to scan database row")
 	ErrDatabaseTransaction  = errors.New("database transaction failed")
 	ErrDatabaseInsertFailed = errors.New("failed to insert data into database")
 	ErrDatabaseUpdateFailed = errors.New("failed to update database record")
+	ErrTransactionConflict  = errors.New("transaction conflicted with a concurrent one")
 )
 
 type EmployeeRepository interface {
 	CreateEmployee(ctx context.Context, employee entity.Employee) (int, error)
 	GetEmployeeByID(ctx context.Context, userID int) (*entity.Employee, error)
 	GetEmployeeByUsername(ctx context.Context, username string) (*entity.Employee, error)
+	UpdateEmployeeRole(ctx context.Context, username, role string) error
+	UpdateEmployeeOffice(ctx context.Context, username, office string) error
+	UpdatePassword(ctx context.Context, employeeID int, passwordHash string) error
+	UpdatePasswordHash(ctx context.Context, employeeID int, oldHash, newHash string) error
 }
 
 type MerchRepository interface {
 	BuyItem(ctx context.Context, userID int, itemID int) error
+	PlaceOrder(ctx context.Context, userID int, lines []entity.OrderLine, promoCode string) (*entity.Order, error)
 	GetItemByID(ctx context.Context, itemID int) (*entity.MerchItem, error)
 	GetItemByName(ctx context.Context, name string) (*entity.MerchItem, error)
 	GetUserPurchases(ctx context.Context, userID int) ([]*entity.InventoryItem, error)
+	ListItems(ctx context.Context) ([]*entity.MerchItem, error)
+	CreateItem(ctx context.Context, item entity.MerchItem) (int, error)
+	UpdateItemPrice(ctx context.Context, name string, price int) (*entity.MerchItem, error)
+	UpdateItemCategory(ctx context.Context, name, category string) error
+	RetireItem(ctx context.Context, name string) error
+	RestockItem(ctx context.Context, name string, quantity int) (*entity.MerchItem, error)
+}
+
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, promotion entity.Promotion, createdBy int) (int, error)
+	GetPromotion(ctx context.Context, promotionID int) (*entity.Promotion, error)
+	ListPromotions(ctx context.Context) ([]*entity.Promotion, error)
+	ListActivePromotions(ctx context.Context, code string) ([]*entity.Promotion, error)
+	DisablePromotion(ctx context.Context, promotionID int) error
 }
 
 type TransactionRepository interface {
 	GetCoinHistory(ctx context.Context, userID int) (*entity.CoinHistory, error)
-	SendCoins(ctx context.Context, senderID, receiverID, amount int) error
+	GetTransfers(ctx context.Context, userID int, filter entity.TransferFilter) ([]entity.Transfer, error)
+	SendCoins(ctx context.Context, senderID, receiverID, amount int, note entity.TransferNote) error
+	IssueCoins(ctx context.Context, issuerID int, reason string, grants []entity.CoinGrant) (int, error)
+}
+
+type IdempotencyRepository interface {
+	ReserveKey(ctx context.Context, userID int, key, fingerprint string, ttl time.Duration) (bool, error)
+	GetKey(ctx context.Context, userID int, key string) (*entity.IdempotencyRecord, error)
+	SaveResponse(ctx context.Context, userID int, key string, status int, body []byte) error
+	DeleteKey(ctx context.Context, userID int, key string) error
+}
+
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, employeeID int, familyID, tokenHash string, ttl time.Duration) error
+	RotateRefreshToken(ctx context.Context, tokenHash, newTokenHash string, ttl time.Duration) (int, error)
+	RevokeRefreshFamily(ctx context.Context, employeeID int, tokenHash string) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string, employeeID, tokenVersion int) (bool, error)
+	CreatePasswordResetToken(ctx context.Context, employeeID, issuerID int, tokenHash string, ttl time.Duration) error
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) error
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key entity.APIKey, keyHash string, createdBy int) (int, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error)
+	TouchAPIKey(ctx context.Context, keyID int) error
+	RevokeAPIKey(ctx context.Context, keyID int) error
+}
+
+type OIDCStateRepository interface {
+	SaveState(ctx context.Context, state entity.OIDCLoginState, ttl time.Duration) error
+	ConsumeState(ctx context.Context, state string) (*entity.OIDCLoginState, error)
+}
+
+type LoginThrottleRepository interface {
+	GetLockout(ctx context.Context, scope, key string) (time.Duration, error)
+	RecordFailure(ctx context.Context, scope, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, scope, key string, duration time.Duration) error
+	Reset(ctx context.Context, scope, key string) error
+}
+
+type ScheduleRepository interface {
+	CreateSchedule(ctx context.Context, schedule entity.ScheduledTransfer) (int, error)
+	GetSchedule(ctx context.Context, scheduleID int) (*entity.ScheduledTransfer, error)
+	ListSchedules(ctx context.Context, ownerID int) ([]*entity.ScheduledTransfer, error)
+	UpdateSchedule(ctx context.Context, schedule entity.ScheduledTransfer) error
+	DeleteSchedule(ctx context.Context, scheduleID int) error
+	ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*entity.ScheduledTransfer, error)
+	AdvanceSchedule(ctx context.Context, scheduleID int, scheduledFor, nextRunAt time.Time) (bool, error)
+	CreateScheduleRun(ctx context.Context, run entity.ScheduleRun) error
+	ListScheduleRuns(ctx context.Context, scheduleID, limit int) ([]entity.ScheduleRun, error)
+}
+
+type EscrowRepository interface {
+	HoldCoins(ctx context.Context, senderID, receiverID, amount int, note entity.TransferNote, expiresAt time.Time) (int, error)
+	GetPendingTransfer(ctx context.Context, transferID int) (*entity.PendingTransfer, error)
+	ListPendingTransfers(ctx context.Context, employeeID int) ([]*entity.PendingTransfer, error)
+	ListExpiredTransfers(ctx context.Context, now time.Time, limit int) ([]int, error)
+	ResolvePendingTransfer(ctx context.Context, transferID int, status string) error
+}
+
+type DisputeRepository interface {
+	CreateDispute(ctx context.Context, transactionID, openedBy int, reason string) (int, error)
+	GetDispute(ctx context.Context, disputeID int) (*entity.Dispute, error)
+	ListDisputes(ctx context.Context, filter entity.DisputeFilter) ([]*entity.Dispute, error)
+	ApproveDispute(ctx context.Context, disputeID, adminID int, note string, force bool) error
+	RejectDispute(ctx context.Context, disputeID, adminID int, note string) error
+}
+
+type OrderRepository interface {
+	GetOrder(ctx context.Context, orderID int) (*entity.Order, error)
+	ListOrders(ctx context.Context, filter entity.OrderFilter) ([]*entity.Order, error)
+	SetOrderStatus(ctx context.Context, orderID int, status string) error
+	CancelOrder(ctx context.Context, orderID int, cancelWindow time.Duration) error
+	AdvanceFulfilment(ctx context.Context, orderID int, status string) error
+}
+
+// LeaderLock elects a single instance of the service to run background jobs.
+type LeaderLock interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
 }
@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockDisputeRepository struct {
+	mock.Mock
+}
+
+func (m *MockDisputeRepository) CreateDispute(ctx context.Context, transactionID, openedBy int, reason string) (int, error) {
+	args := m.Called(ctx, transactionID, openedBy, reason)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDisputeRepository) GetDispute(ctx context.Context, disputeID int) (*entity.Dispute, error) {
+	args := m.Called(ctx, disputeID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.Dispute), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDisputeRepository) ListDisputes(ctx context.Context, filter entity.DisputeFilter) ([]*entity.Dispute, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*entity.Dispute), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDisputeRepository) ApproveDispute(ctx context.Context, disputeID, adminID int, note string, force bool) error {
+	args := m.Called(ctx, disputeID, adminID, note, force)
+	return args.Error(0)
+}
+
+func (m *MockDisputeRepository) RejectDispute(ctx context.Context, disputeID, adminID int, note string) error {
+	args := m.Called(ctx, disputeID, adminID, note)
+	return args.Error(0)
+}
@@ -31,3 +31,23 @@ func (m *MockEmployeeRepository) GetEmployeeByUsername(ctx context.Context, user
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockEmployeeRepository) UpdateEmployeeRole(ctx context.Context, username, role string) error {
+	args := m.Called(ctx, username, role)
+	return args.Error(0)
+}
+
+func (m *MockEmployeeRepository) UpdateEmployeeOffice(ctx context.Context, username, office string) error {
+	args := m.Called(ctx, username, office)
+	return args.Error(0)
+}
+
+func (m *MockEmployeeRepository) UpdatePassword(ctx context.Context, employeeID int, passwordHash string) error {
+	args := m.Called(ctx, employeeID, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockEmployeeRepository) UpdatePasswordHash(ctx context.Context, employeeID int, oldHash, newHash string) error {
+	args := m.Called(ctx, employeeID, oldHash, newHash)
+	return args.Error(0)
+}
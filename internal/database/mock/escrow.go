@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockEscrowRepository struct {
+	mock.Mock
+}
+
+func (m *MockEscrowRepository) HoldCoins(ctx context.Context, senderID, receiverID, amount int, note entity.TransferNote, expiresAt time.Time) (int, error) {
+	args := m.Called(ctx, senderID, receiverID, amount, note, expiresAt)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockEscrowRepository) GetPendingTransfer(ctx context.Context, transferID int) (*entity.PendingTransfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEscrowRepository) ListPendingTransfers(ctx context.Context, employeeID int) ([]*entity.PendingTransfer, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*entity.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEscrowRepository) ListExpiredTransfers(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]int), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEscrowRepository) ResolvePendingTransfer(ctx context.Context, transferID int, status string) error {
+	args := m.Called(ctx, transferID, status)
+	return args.Error(0)
+}
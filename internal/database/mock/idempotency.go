@@ -0,0 +1,36 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) ReserveKey(ctx context.Context, userID int, key, fingerprint string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, userID, key, fingerprint, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) GetKey(ctx context.Context, userID int, key string) (*entity.IdempotencyRecord, error) {
+	args := m.Called(ctx, userID, key)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.IdempotencyRecord), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) SaveResponse(ctx context.Context, userID int, key string, status int, body []byte) error {
+	args := m.Called(ctx, userID, key, status, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteKey(ctx context.Context, userID int, key string) error {
+	args := m.Called(ctx, userID, key)
+	return args.Error(0)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginThrottleRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleRepository) GetLockout(ctx context.Context, scope, key string) (time.Duration, error) {
+	args := m.Called(ctx, scope, key)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) RecordFailure(ctx context.Context, scope, key string, window time.Duration) (int, error) {
+	args := m.Called(ctx, scope, key, window)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) Lock(ctx context.Context, scope, key string, duration time.Duration) error {
+	args := m.Called(ctx, scope, key, duration)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) Reset(ctx context.Context, scope, key string) error {
+	args := m.Called(ctx, scope, key)
+	return args.Error(0)
+}
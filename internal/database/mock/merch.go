@@ -16,6 +16,14 @@ func (m *MockMerchRepository) BuyItem(ctx context.Context, userID int, itemID in
 	return args.Error(0)
 }
 
+func (m *MockMerchRepository) PlaceOrder(ctx context.Context, userID int, lines []entity.OrderLine, promoCode string) (*entity.Order, error) {
+	args := m.Called(ctx, userID, lines, promoCode)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockMerchRepository) GetItemByID(ctx context.Context, itemID int) (*entity.MerchItem, error) {
 	args := m.Called(ctx, itemID)
 	if args.Get(0) != nil {
@@ -39,3 +47,42 @@ func (m *MockMerchRepository) GetUserPurchases(ctx context.Context, userID int)
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockMerchRepository) ListItems(ctx context.Context) ([]*entity.MerchItem, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*entity.MerchItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMerchRepository) CreateItem(ctx context.Context, item entity.MerchItem) (int, error) {
+	args := m.Called(ctx, item)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMerchRepository) UpdateItemPrice(ctx context.Context, name string, price int) (*entity.MerchItem, error) {
+	args := m.Called(ctx, name, price)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.MerchItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMerchRepository) UpdateItemCategory(ctx context.Context, name, category string) error {
+	args := m.Called(ctx, name, category)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepository) RetireItem(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepository) RestockItem(ctx context.Context, name string, quantity int) (*entity.MerchItem, error) {
+	args := m.Called(ctx, name, quantity)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.MerchItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockOIDCStateRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCStateRepository) SaveState(ctx context.Context, state entity.OIDCLoginState, ttl time.Duration) error {
+	args := m.Called(ctx, state, ttl)
+	return args.Error(0)
+}
+
+func (m *MockOIDCStateRepository) ConsumeState(ctx context.Context, state string) (*entity.OIDCLoginState, error) {
+	args := m.Called(ctx, state)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.OIDCLoginState), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
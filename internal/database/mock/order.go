@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) GetOrder(ctx context.Context, orderID int) (*entity.Order, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderRepository) ListOrders(ctx context.Context, filter entity.OrderFilter) ([]*entity.Order, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*entity.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderRepository) SetOrderStatus(ctx context.Context, orderID int, status string) error {
+	args := m.Called(ctx, orderID, status)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) CancelOrder(ctx context.Context, orderID int, cancelWindow time.Duration) error {
+	args := m.Called(ctx, orderID, cancelWindow)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) AdvanceFulfilment(ctx context.Context, orderID int, status string) error {
+	args := m.Called(ctx, orderID, status)
+	return args.Error(0)
+}
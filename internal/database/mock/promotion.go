@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromotionRepository) CreatePromotion(ctx context.Context, promotion entity.Promotion, createdBy int) (int, error) {
+	args := m.Called(ctx, promotion, createdBy)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetPromotion(ctx context.Context, promotionID int) (*entity.Promotion, error) {
+	args := m.Called(ctx, promotionID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.Promotion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPromotionRepository) ListPromotions(ctx context.Context) ([]*entity.Promotion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*entity.Promotion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPromotionRepository) ListActivePromotions(ctx context.Context, code string) ([]*entity.Promotion, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*entity.Promotion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPromotionRepository) DisablePromotion(ctx context.Context, promotionID int) error {
+	args := m.Called(ctx, promotionID)
+	return args.Error(0)
+}
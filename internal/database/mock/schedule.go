@@ -0,0 +1,84 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepository) CreateSchedule(ctx context.Context, schedule entity.ScheduledTransfer) (int, error) {
+	args := m.Called(ctx, schedule)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockScheduleRepository) GetSchedule(ctx context.Context, scheduleID int) (*entity.ScheduledTransfer, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockScheduleRepository) ListSchedules(ctx context.Context, ownerID int) ([]*entity.ScheduledTransfer, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*entity.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockScheduleRepository) UpdateSchedule(ctx context.Context, schedule entity.ScheduledTransfer) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) DeleteSchedule(ctx context.Context, scheduleID int) error {
+	args := m.Called(ctx, scheduleID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*entity.ScheduledTransfer, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*entity.ScheduledTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockScheduleRepository) AdvanceSchedule(ctx context.Context, scheduleID int, scheduledFor, nextRunAt time.Time) (bool, error) {
+	args := m.Called(ctx, scheduleID, scheduledFor, nextRunAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScheduleRepository) CreateScheduleRun(ctx context.Context, run entity.ScheduleRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) ListScheduleRuns(ctx context.Context, scheduleID, limit int) ([]entity.ScheduleRun, error) {
+	args := m.Called(ctx, scheduleID, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]entity.ScheduleRun), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockLeaderLock struct {
+	mock.Mock
+}
+
+func (m *MockLeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLeaderLock) Release(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
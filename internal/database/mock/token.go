@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRepository) CreateRefreshToken(ctx context.Context, employeeID int, familyID, tokenHash string, ttl time.Duration) error {
+	args := m.Called(ctx, employeeID, familyID, tokenHash, ttl)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) RotateRefreshToken(ctx context.Context, tokenHash, newTokenHash string, ttl time.Duration) (int, error) {
+	args := m.Called(ctx, tokenHash, newTokenHash, ttl)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeRefreshFamily(ctx context.Context, employeeID int, tokenHash string) error {
+	args := m.Called(ctx, employeeID, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	args := m.Called(ctx, jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string, employeeID, tokenVersion int) (bool, error) {
+	args := m.Called(ctx, jti, employeeID, tokenVersion)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) CreatePasswordResetToken(ctx context.Context, employeeID, issuerID int, tokenHash string, ttl time.Duration) error {
+	args := m.Called(ctx, employeeID, issuerID, tokenHash, ttl)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) error {
+	args := m.Called(ctx, tokenHash, passwordHash)
+	return args.Error(0)
+}
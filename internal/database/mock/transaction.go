@@ -19,7 +19,20 @@ func (m *MockTransactionRepository) GetCoinHistory(ctx context.Context, userID i
 	return nil, args.Error(1)
 }
 
-func (m *MockTransactionRepository) SendCoins(ctx context.Context, senderID, receiverID, amount int) error {
-	args := m.Called(ctx, senderID, receiverID, amount)
+func (m *MockTransactionRepository) GetTransfers(ctx context.Context, userID int, filter entity.TransferFilter) ([]entity.Transfer, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]entity.Transfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTransactionRepository) SendCoins(ctx context.Context, senderID, receiverID, amount int, note entity.TransferNote) error {
+	args := m.Called(ctx, senderID, receiverID, amount, note)
 	return args.Error(0)
 }
+
+func (m *MockTransactionRepository) IssueCoins(ctx context.Context, issuerID int, reason string, grants []entity.CoinGrant) (int, error) {
+	args := m.Called(ctx, issuerID, reason, grants)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+const apiKeyColumns = `
+	k.id, k.employee_id, e.username, k.name, k.prefix, k.scopes,
+	k.expires_at, k.last_used_at, k.revoked_at, k.timestamp
+`
+
+type APIKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(db *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+func scanAPIKey(row pgx.Row) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := row.Scan(&key.ID, &key.EmployeeID, &key.Username, &key.Name, &key.Prefix, &key.Scopes,
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key entity.APIKey, keyHash string, createdBy int) (int, error) {
+	var keyID int
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO api_keys (employee_id, name, prefix, key_hash, scopes, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, key.EmployeeID, key.Name, key.Prefix, keyHash, key.Scopes, key.ExpiresAt, createdBy).Scan(&keyID)
+	if err != nil {
+		log.Printf("failed to create api key %q for employee %d: %v", key.Name, key.EmployeeID, err)
+		return 0, database.ErrDatabaseInsertFailed
+	}
+
+	return keyID, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys k
+		JOIN employees e ON e.id = k.employee_id
+		WHERE k.key_hash = $1
+	`, keyHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrAPIKeyNotFound
+		}
+		log.Printf("failed to get api key: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys k
+		JOIN employees e ON e.id = k.employee_id
+		ORDER BY k.id
+	`)
+	if err != nil {
+		log.Printf("failed to list api keys: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	keys := make([]*entity.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			log.Printf("failed to scan api key: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to iterate api keys: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return keys, nil
+}
+
+// TouchAPIKey records that the key was used. To keep hot keys from turning
+// every request into a write, the timestamp is updated at most once a minute.
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, keyID int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute')
+	`, keyID)
+	if err != nil {
+		log.Printf("failed to update last use of api key %d: %v", keyID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, keyID int) error {
+	tag, err := r.db.Exec(ctx, "UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL", keyID)
+	if err != nil {
+		log.Printf("failed to revoke api key %d: %v", keyID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+const disputeColumns = `
+	d.id, d.transaction_id, t.sender_id, sender.username, t.receiver_id, receiver.username, t.amount,
+	d.reason, d.status, COALESCE(admin.username, ''), COALESCE(d.resolution_note, ''), COALESCE(d.reversal_id, 0),
+	d.clawback, d.timestamp, d.resolved_at
+	FROM transfer_disputes d
+	JOIN transactions t ON t.id = d.transaction_id
+	JOIN employees sender ON sender.id = t.sender_id
+	JOIN employees receiver ON receiver.id = t.receiver_id
+	LEFT JOIN employees admin ON admin.id = d.resolved_by
+`
+
+type DisputeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDisputeRepository(db *pgxpool.Pool) *DisputeRepository {
+	return &DisputeRepository{
+		db: db,
+	}
+}
+
+func scanDispute(row pgx.Row) (*entity.Dispute, error) {
+	var dispute entity.Dispute
+	err := row.Scan(&dispute.ID, &dispute.TransactionID, &dispute.SenderID, &dispute.Sender, &dispute.ReceiverID, &dispute.Receiver, &dispute.Amount,
+		&dispute.Reason, &dispute.Status, &dispute.ResolvedBy, &dispute.Note, &dispute.ReversalID,
+		&dispute.Clawback, &dispute.CreatedAt, &dispute.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dispute, nil
+}
+
+func (r *DisputeRepository) CreateDispute(ctx context.Context, transactionID, openedBy int, reason string) (int, error) {
+	var senderID *int
+	var kind string
+	var reversed bool
+	err := r.db.QueryRow(ctx, `
+		SELECT t.sender_id, t.kind, EXISTS (SELECT 1 FROM transactions r WHERE r.reverses_id = t.id)
+		FROM transactions t WHERE t.id = $1
+	`, transactionID).Scan(&senderID, &kind, &reversed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, database.ErrTransferNotFound
+		}
+		log.Printf("failed to get transaction %d: %v", transactionID, err)
+		return 0, database.ErrDatabaseQueryFailed
+	}
+
+	if senderID == nil || *senderID != openedBy {
+		return 0, database.ErrTransferNotFound
+	}
+	if kind != entity.TransferKindTransfer || reversed {
+		return 0, database.ErrTransferNotDisputable
+	}
+
+	var disputeID int
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO transfer_disputes (transaction_id, opened_by, reason)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, transactionID, openedBy, reason).Scan(&disputeID)
+	if err != nil {
+		if pgErrorCode(err) == uniqueViolationCode {
+			return 0, database.ErrDisputeAlreadyOpen
+		}
+		log.Printf("failed to open dispute on transaction %d: %v", transactionID, err)
+		return 0, database.ErrDatabaseInsertFailed
+	}
+
+	return disputeID, nil
+}
+
+func (r *DisputeRepository) GetDispute(ctx context.Context, disputeID int) (*entity.Dispute, error) {
+	dispute, err := scanDispute(r.db.QueryRow(ctx, "SELECT "+disputeColumns+" WHERE d.id = $1", disputeID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrDisputeNotFound
+		}
+		log.Printf("failed to get dispute %d: %v", disputeID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return dispute, nil
+}
+
+func (r *DisputeRepository) ListDisputes(ctx context.Context, filter entity.DisputeFilter) ([]*entity.Dispute, error) {
+	conditions := []string{"TRUE"}
+	args := []any{}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("d.status = $%d", len(args)))
+	}
+	if filter.EmployeeID != 0 {
+		args = append(args, filter.EmployeeID)
+		conditions = append(conditions, fmt.Sprintf("$%d IN (t.sender_id, t.receiver_id)", len(args)))
+	}
+
+	query := "SELECT " + disputeColumns + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY d.timestamp DESC, d.id DESC"
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to list disputes: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	disputes := make([]*entity.Dispute, 0)
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			log.Printf("failed to scan dispute: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		disputes = append(disputes, dispute)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to read disputes: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return disputes, nil
+}
+
+// ApproveDispute returns the coins of the disputed transfer to its sender with
+// a compensating reversal transaction. If the receiver no longer has the coins
+// it fails with database.ErrInsufficientFunds, unless force is set, in which
+// case the receiver's balance goes below zero.
+func (r *DisputeRepository) ApproveDispute(ctx context.Context, disputeID, adminID int, note string, force bool) error {
+	return retryOnConflict(ctx, func() error {
+		return r.approveDispute(ctx, disputeID, adminID, note, force)
+	})
+}
+
+func (r *DisputeRepository) approveDispute(ctx context.Context, disputeID, adminID int, note string, force bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for approving dispute %d: %v", disputeID, err)
+		return database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	var transactionID, senderID, receiverID, amount int
+	err = tx.QueryRow(ctx, `
+		SELECT d.status, t.id, t.sender_id, t.receiver_id, t.amount
+		FROM transfer_disputes d
+		JOIN transactions t ON t.id = d.transaction_id
+		WHERE d.id = $1
+		FOR UPDATE OF d
+	`, disputeID).Scan(&status, &transactionID, &senderID, &receiverID, &amount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.ErrDisputeNotFound
+		}
+		log.Printf("failed to lock dispute %d: %v", disputeID, err)
+		return txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	if status != entity.DisputeOpen {
+		return database.ErrDisputeAlreadyResolved
+	}
+
+	balances, err := lockEmployees(ctx, tx, senderID, receiverID)
+	if err != nil {
+		return err
+	}
+
+	receiverBalance, ok := balances[receiverID]
+	if !ok {
+		log.Printf("receiver %d not found", receiverID)
+		return database.ErrEmployeeNotFound
+	}
+
+	clawback := receiverBalance < amount
+	if clawback {
+		if !force {
+			return database.ErrInsufficientFunds
+		}
+
+		_, err = tx.Exec(ctx, "UPDATE employees SET min_balance = LEAST(min_balance, balance - $2) WHERE id = $1", receiverID, amount)
+		if err != nil {
+			log.Printf("failed to allow clawback from employee %d: %v", receiverID, err)
+			return txError(err, database.ErrDatabaseUpdateFailed)
+		}
+	}
+
+	senderAccountID, err := employeeAccountID(ctx, tx, senderID)
+	if err != nil {
+		return err
+	}
+
+	receiverAccountID, err := employeeAccountID(ctx, tx, receiverID)
+	if err != nil {
+		return err
+	}
+
+	entryID, err := postEntry(ctx, tx, ledgerEntryReversal,
+		posting{accountID: receiverAccountID, amount: -amount},
+		posting{accountID: senderAccountID, amount: amount},
+	)
+	if err != nil {
+		return err
+	}
+
+	var reversalID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO transactions (sender_id, receiver_id, amount, kind, entry_id, reverses_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, receiverID, senderID, amount, entity.TransferKindReversal, entryID, transactionID).Scan(&reversalID)
+	if err != nil {
+		log.Printf("failed to insert reversal of transaction %d: %v", transactionID, err)
+		return txError(err, database.ErrDatabaseInsertFailed)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE transfer_disputes
+		SET status = $2, resolved_by = $3, resolution_note = NULLIF($4, ''), reversal_id = $5, clawback = $6, resolved_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, disputeID, entity.DisputeApproved, adminID, note, reversalID, clawback)
+	if err != nil {
+		log.Printf("failed to approve dispute %d: %v", disputeID, err)
+		return txError(err, database.ErrDatabaseUpdateFailed)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit approval of dispute %d: %v", disputeID, err)
+		return txError(err, database.ErrDatabaseTransaction)
+	}
+
+	return nil
+}
+
+func (r *DisputeRepository) RejectDispute(ctx context.Context, disputeID, adminID int, note string) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE transfer_disputes
+		SET status = $2, resolved_by = $3, resolution_note = NULLIF($4, ''), resolved_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $5
+	`, disputeID, entity.DisputeRejected, adminID, note, entity.DisputeOpen)
+	if err != nil {
+		log.Printf("failed to reject dispute %d: %v", disputeID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if result.RowsAffected() == 0 {
+		var exists bool
+		err = r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM transfer_disputes WHERE id = $1)", disputeID).Scan(&exists)
+		if err != nil {
+			log.Printf("failed to check dispute %d: %v", disputeID, err)
+			return database.ErrDatabaseQueryFailed
+		}
+		if !exists {
+			return database.ErrDisputeNotFound
+		}
+		return database.ErrDisputeAlreadyResolved
+	}
+
+	return nil
+}
@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"log"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/vit6556/avito-internship-assignment/internal/database"
@@ -22,11 +25,14 @@ func NewEmployeeRepository(db *pgxpool.Pool) *EmployeeRepository {
 
 func (r *EmployeeRepository) GetEmployeeByUsername(ctx context.Context, username string) (*entity.Employee, error) {
 	var employee entity.Employee
-	err := r.db.QueryRow(ctx, "SELECT id, username, password_hash, balance FROM employees WHERE username = $1", username).
-		Scan(&employee.ID, &employee.Username, &employee.PasswordHash, &employee.Balance)
+	err := r.db.QueryRow(ctx, "SELECT id, username, password_hash, balance, role, token_version FROM employees WHERE username = $1", username).
+		Scan(&employee.ID, &employee.Username, &employee.PasswordHash, &employee.Balance, &employee.Role, &employee.TokenVersion)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrEmployeeNotFound
+		}
 		log.Printf("failed to get employee by username %q: %v", username, err)
-		return nil, database.ErrEmployeeNotFound
+		return nil, database.ErrDatabaseQueryFailed
 	}
 
 	return &employee, nil
@@ -34,25 +40,157 @@ func (r *EmployeeRepository) GetEmployeeByUsername(ctx context.Context, username
 
 func (r *EmployeeRepository) GetEmployeeByID(ctx context.Context, userID int) (*entity.Employee, error) {
 	var employee entity.Employee
-	err := r.db.QueryRow(ctx, "SELECT id, username, password_hash, balance FROM employees WHERE id = $1", userID).
-		Scan(&employee.ID, &employee.Username, &employee.PasswordHash, &employee.Balance)
+	err := r.db.QueryRow(ctx, "SELECT id, username, password_hash, balance, role, token_version FROM employees WHERE id = $1", userID).
+		Scan(&employee.ID, &employee.Username, &employee.PasswordHash, &employee.Balance, &employee.Role, &employee.TokenVersion)
 	if err != nil {
-		log.Printf("failed to get employee by ID %q: %v", userID, err)
-		return nil, database.ErrEmployeeNotFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrEmployeeNotFound
+		}
+		log.Printf("failed to get employee by ID %d: %v", userID, err)
+		return nil, database.ErrDatabaseQueryFailed
 	}
 
 	return &employee, nil
 }
 
 func (r *EmployeeRepository) CreateEmployee(ctx context.Context, employee entity.Employee) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for creating employee %q: %v", employee.Username, err)
+		return 0, database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	role := employee.Role
+	if role == "" {
+		role = entity.RoleEmployee
+	}
+
 	var userID int
-	err := r.db.QueryRow(ctx, "INSERT INTO employees (username, password_hash, balance) VALUES ($1, $2, $3) RETURNING id",
-		employee.Username, employee.PasswordHash, employee.Balance).Scan(&userID)
+	err = tx.QueryRow(ctx, "INSERT INTO employees (username, password_hash, balance, role) VALUES ($1, $2, 0, $3) RETURNING id",
+		employee.Username, employee.PasswordHash, role).Scan(&userID)
 
 	if err != nil {
 		log.Printf("failed to create employee %q: %v", employee.Username, err)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return 0, database.ErrEmployeeAlreadyExists
+		}
 		return 0, database.ErrEmployeeCreationFailed
 	}
 
+	accountID, err := openEmployeeAccount(ctx, tx, userID)
+	if err != nil {
+		return 0, database.ErrEmployeeCreationFailed
+	}
+
+	if employee.Balance > 0 {
+		issuanceAccountID, err := systemAccountID(ctx, tx, ledgerAccountIssuance)
+		if err != nil {
+			return 0, database.ErrEmployeeCreationFailed
+		}
+
+		_, err = postEntry(ctx, tx, ledgerEntryIssuance,
+			posting{accountID: issuanceAccountID, amount: -employee.Balance},
+			posting{accountID: accountID, amount: employee.Balance},
+		)
+		if err != nil {
+			return 0, database.ErrEmployeeCreationFailed
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit transaction for creating employee %q: %v", employee.Username, err)
+		return 0, database.ErrDatabaseTransaction
+	}
+
 	return userID, nil
 }
+
+func (r *EmployeeRepository) UpdateEmployeeRole(ctx context.Context, username, role string) error {
+	tag, err := r.db.Exec(ctx, "UPDATE employees SET role = $1 WHERE username = $2", role, username)
+	if err != nil {
+		log.Printf("failed to update role of employee %q: %v", username, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrEmployeeNotFound
+	}
+
+	return nil
+}
+
+// UpdateEmployeeOffice sets the office the employee picks merch up in. An
+// empty office clears it.
+func (r *EmployeeRepository) UpdateEmployeeOffice(ctx context.Context, username, office string) error {
+	tag, err := r.db.Exec(ctx, "UPDATE employees SET office = NULLIF($1, '') WHERE username = $2", office, username)
+	if err != nil {
+		log.Printf("failed to update office of employee %q: %v", username, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrEmployeeNotFound
+	}
+
+	return nil
+}
+
+// UpdatePassword stores the new password hash and signs the employee out
+// everywhere: the token version is bumped, which invalidates issued access
+// tokens, and all refresh tokens are revoked.
+func (r *EmployeeRepository) UpdatePassword(ctx context.Context, employeeID int, passwordHash string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for updating password of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	if err := updatePassword(ctx, tx, employeeID, passwordHash); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit password update of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseTransaction
+	}
+
+	return nil
+}
+
+// updatePassword stores a new password hash and revokes all tokens of the
+// employee. It must run inside the caller's transaction.
+func updatePassword(ctx context.Context, tx pgx.Tx, employeeID int, passwordHash string) error {
+	tag, err := tx.Exec(ctx, "UPDATE employees SET password_hash = $1, token_version = token_version + 1 WHERE id = $2", passwordHash, employeeID)
+	if err != nil {
+		log.Printf("failed to update password of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrEmployeeNotFound
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE employee_id = $1 AND revoked_at IS NULL", employeeID)
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	return nil
+}
+
+// UpdatePasswordHash replaces the stored hash of an unchanged password, for
+// example to upgrade its algorithm. Unlike UpdatePassword it keeps issued
+// tokens valid. Nothing is updated if the hash changed in the meantime.
+func (r *EmployeeRepository) UpdatePasswordHash(ctx context.Context, employeeID int, oldHash, newHash string) error {
+	_, err := r.db.Exec(ctx, "UPDATE employees SET password_hash = $1 WHERE id = $2 AND password_hash = $3", newHash, employeeID, oldHash)
+	if err != nil {
+		log.Printf("failed to update password hash of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	return nil
+}
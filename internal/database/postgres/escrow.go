@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+const pendingTransferColumns = `
+	p.id, p.sender_id, sender.username, p.receiver_id, receiver.username, p.amount,
+	COALESCE(p.message, ''), COALESCE(p.category, ''), p.status, p.expires_at, p.timestamp, p.resolved_at
+	FROM pending_transfers p
+	JOIN employees sender ON sender.id = p.sender_id
+	JOIN employees receiver ON receiver.id = p.receiver_id
+`
+
+type EscrowRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEscrowRepository(db *pgxpool.Pool) *EscrowRepository {
+	return &EscrowRepository{
+		db: db,
+	}
+}
+
+func scanPendingTransfer(row pgx.Row) (*entity.PendingTransfer, error) {
+	var transfer entity.PendingTransfer
+	err := row.Scan(&transfer.ID, &transfer.SenderID, &transfer.Sender, &transfer.ReceiverID, &transfer.Receiver, &transfer.Amount,
+		&transfer.Note.Message, &transfer.Note.Category, &transfer.Status, &transfer.ExpiresAt, &transfer.CreatedAt, &transfer.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// HoldCoins moves coins from the sender into the escrow account and records a
+// pending transfer to the receiver. A transaction that loses a conflict with a
+// concurrent one is run again.
+func (r *EscrowRepository) HoldCoins(ctx context.Context, senderID, receiverID, amount int, note entity.TransferNote, expiresAt time.Time) (int, error) {
+	var transferID int
+	err := retryOnConflict(ctx, func() error {
+		var err error
+		transferID, err = r.holdCoins(ctx, senderID, receiverID, amount, note, expiresAt)
+		return err
+	})
+
+	return transferID, err
+}
+
+func (r *EscrowRepository) holdCoins(ctx context.Context, senderID, receiverID, amount int, note entity.TransferNote, expiresAt time.Time) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for holding coins from user %d to user %d: %v", senderID, receiverID, err)
+		return 0, database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	balances, err := lockEmployees(ctx, tx, senderID, receiverID)
+	if err != nil {
+		return 0, err
+	}
+
+	senderBalance, ok := balances[senderID]
+	if !ok {
+		log.Printf("sender %d not found", senderID)
+		return 0, database.ErrEmployeeNotFound
+	}
+	if _, ok := balances[receiverID]; !ok {
+		log.Printf("receiver %d not found", receiverID)
+		return 0, database.ErrEmployeeNotFound
+	}
+
+	if senderBalance < amount {
+		return 0, database.ErrInsufficientFunds
+	}
+
+	senderAccountID, err := employeeAccountID(ctx, tx, senderID)
+	if err != nil {
+		return 0, err
+	}
+
+	escrowAccountID, err := systemAccountID(ctx, tx, ledgerAccountEscrow)
+	if err != nil {
+		return 0, err
+	}
+
+	entryID, err := postEntry(ctx, tx, ledgerEntryEscrowHold,
+		posting{accountID: senderAccountID, amount: -amount},
+		posting{accountID: escrowAccountID, amount: amount},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var transferID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO pending_transfers (sender_id, receiver_id, amount, message, category, hold_entry_id, expires_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)
+		RETURNING id
+	`, senderID, receiverID, amount, note.Message, note.Category, entryID, expiresAt).Scan(&transferID)
+	if err != nil {
+		log.Printf("failed to insert pending transfer for sender %d -> receiver %d: %v", senderID, receiverID, err)
+		return 0, txError(err, database.ErrDatabaseInsertFailed)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit pending transfer for sender %d -> receiver %d: %v", senderID, receiverID, err)
+		return 0, txError(err, database.ErrDatabaseTransaction)
+	}
+
+	return transferID, nil
+}
+
+func (r *EscrowRepository) GetPendingTransfer(ctx context.Context, transferID int) (*entity.PendingTransfer, error) {
+	transfer, err := scanPendingTransfer(r.db.QueryRow(ctx, "SELECT "+pendingTransferColumns+" WHERE p.id = $1", transferID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrPendingTransferNotFound
+		}
+		log.Printf("failed to get pending transfer %d: %v", transferID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return transfer, nil
+}
+
+func (r *EscrowRepository) ListPendingTransfers(ctx context.Context, employeeID int) ([]*entity.PendingTransfer, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+pendingTransferColumns+`
+		WHERE p.status = 'pending' AND (p.sender_id = $1 OR p.receiver_id = $1)
+		ORDER BY p.expires_at, p.id
+	`, employeeID)
+	if err != nil {
+		log.Printf("failed to list pending transfers of user %d: %v", employeeID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	transfers := make([]*entity.PendingTransfer, 0)
+	for rows.Next() {
+		transfer, err := scanPendingTransfer(rows)
+		if err != nil {
+			log.Printf("failed to scan pending transfer of user %d: %v", employeeID, err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to read pending transfers of user %d: %v", employeeID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return transfers, nil
+}
+
+func (r *EscrowRepository) ListExpiredTransfers(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id FROM pending_transfers
+		WHERE status = 'pending' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		log.Printf("failed to list expired pending transfers: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	transferIDs := make([]int, 0)
+	for rows.Next() {
+		var transferID int
+		if err := rows.Scan(&transferID); err != nil {
+			log.Printf("failed to scan expired pending transfer: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		transferIDs = append(transferIDs, transferID)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to read expired pending transfers: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return transferIDs, nil
+}
+
+// ResolvePendingTransfer releases the held coins of a pending transfer: to the
+// receiver when status is accepted, back to the sender otherwise. A transfer
+// that is no longer pending is left untouched.
+func (r *EscrowRepository) ResolvePendingTransfer(ctx context.Context, transferID int, status string) error {
+	return retryOnConflict(ctx, func() error {
+		return r.resolvePendingTransfer(ctx, transferID, status)
+	})
+}
+
+func (r *EscrowRepository) resolvePendingTransfer(ctx context.Context, transferID int, status string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for resolving pending transfer %d: %v", transferID, err)
+		return database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	// The pending transfer is locked before any employee row, so an accept
+	// racing the sweeper resolves the transfer only once.
+	var senderID, receiverID, amount int
+	var note entity.TransferNote
+	var currentStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT sender_id, receiver_id, amount, COALESCE(message, ''), COALESCE(category, ''), status
+		FROM pending_transfers WHERE id = $1
+		FOR UPDATE
+	`, transferID).Scan(&senderID, &receiverID, &amount, &note.Message, &note.Category, &currentStatus)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.ErrPendingTransferNotFound
+		}
+		log.Printf("failed to lock pending transfer %d: %v", transferID, err)
+		return txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	if currentStatus != entity.PendingTransferPending {
+		return database.ErrPendingTransferResolved
+	}
+
+	beneficiaryID, kind := senderID, ledgerEntryEscrowRefund
+	if status == entity.PendingTransferAccepted {
+		beneficiaryID, kind = receiverID, ledgerEntryEscrowRelease
+	}
+
+	if _, err := lockEmployees(ctx, tx, beneficiaryID); err != nil {
+		return err
+	}
+
+	beneficiaryAccountID, err := employeeAccountID(ctx, tx, beneficiaryID)
+	if err != nil {
+		return err
+	}
+
+	escrowAccountID, err := systemAccountID(ctx, tx, ledgerAccountEscrow)
+	if err != nil {
+		return err
+	}
+
+	entryID, err := postEntry(ctx, tx, kind,
+		posting{accountID: escrowAccountID, amount: -amount},
+		posting{accountID: beneficiaryAccountID, amount: amount},
+	)
+	if err != nil {
+		return err
+	}
+
+	if status == entity.PendingTransferAccepted {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO transactions (sender_id, receiver_id, amount, entry_id, message, category)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+		`, senderID, receiverID, amount, entryID, note.Message, note.Category)
+		if err != nil {
+			log.Printf("failed to insert transaction record for pending transfer %d: %v", transferID, err)
+			return txError(err, database.ErrDatabaseInsertFailed)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE pending_transfers
+		SET status = $2, release_entry_id = $3, resolved_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, transferID, status, entryID)
+	if err != nil {
+		log.Printf("failed to resolve pending transfer %d as %s: %v", transferID, status, err)
+		return txError(err, database.ErrDatabaseUpdateFailed)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit resolution of pending transfer %d: %v", transferID, err)
+		return txError(err, database.ErrDatabaseTransaction)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type IdempotencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(db *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db: db,
+	}
+}
+
+// ReserveKey claims the key for a new request. A key that is older than ttl
+// is considered forgotten and is claimed again. It reports false if the key
+// is already held by a previous request.
+func (r *IdempotencyRepository) ReserveKey(ctx context.Context, userID int, key, fingerprint string, ttl time.Duration) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO idempotency_keys (employee_id, key, fingerprint)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (employee_id, key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+			response_status = NULL,
+			response_body = NULL,
+			timestamp = CURRENT_TIMESTAMP
+		WHERE idempotency_keys.timestamp < CURRENT_TIMESTAMP - $4 * INTERVAL '1 second'
+	`, userID, key, fingerprint, int(ttl.Seconds()))
+	if err != nil {
+		log.Printf("failed to reserve idempotency key %q for user %d: %v", key, userID, err)
+		return false, database.ErrDatabaseInsertFailed
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *IdempotencyRepository) GetKey(ctx context.Context, userID int, key string) (*entity.IdempotencyRecord, error) {
+	var status *int
+	record := entity.IdempotencyRecord{UserID: userID, Key: key}
+	err := r.db.QueryRow(ctx, "SELECT fingerprint, response_status, response_body FROM idempotency_keys WHERE employee_id = $1 AND key = $2", userID, key).
+		Scan(&record.Fingerprint, &status, &record.ResponseBody)
+	if err != nil {
+		log.Printf("failed to get idempotency key %q for user %d: %v", key, userID, err)
+		return nil, database.ErrIdempotencyKeyNotFound
+	}
+
+	if status != nil {
+		record.Completed = true
+		record.ResponseStatus = *status
+	}
+
+	return &record, nil
+}
+
+func (r *IdempotencyRepository) SaveResponse(ctx context.Context, userID int, key string, status int, body []byte) error {
+	_, err := r.db.Exec(ctx, "UPDATE idempotency_keys SET response_status = $1, response_body = $2 WHERE employee_id = $3 AND key = $4", status, body, userID, key)
+	if err != nil {
+		log.Printf("failed to save response for idempotency key %q of user %d: %v", key, userID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteKey(ctx context.Context, userID int, key string) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE employee_id = $1 AND key = $2", userID, key)
+	if err != nil {
+		log.Printf("failed to delete idempotency key %q of user %d: %v", key, userID, err)
+		return database.ErrDatabaseQueryFailed
+	}
+
+	return nil
+}
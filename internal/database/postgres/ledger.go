@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+)
+
+const (
+	ledgerEntryTransfer = "transfer"
+	ledgerEntryPurchase = "purchase"
+	ledgerEntryIssuance = "issuance"
+
+	ledgerEntryEscrowHold    = "escrow_hold"
+	ledgerEntryEscrowRelease = "escrow_release"
+	ledgerEntryEscrowRefund  = "escrow_refund"
+
+	ledgerEntryReversal    = "reversal"
+	ledgerEntryOrderRefund = "order_refund"
+
+	ledgerAccountShopRevenue = "shop_revenue"
+	ledgerAccountIssuance    = "issuance"
+	ledgerAccountEscrow      = "escrow"
+)
+
+// posting moves amount coins into (positive) or out of (negative) a ledger account.
+// The postings of a single entry must sum up to zero.
+type posting struct {
+	accountID int
+	amount    int
+}
+
+func openEmployeeAccount(ctx context.Context, tx pgx.Tx, employeeID int) (int, error) {
+	var accountID int
+	err := tx.QueryRow(ctx, "INSERT INTO ledger_accounts (employee_id) VALUES ($1) RETURNING id", employeeID).Scan(&accountID)
+	if err != nil {
+		log.Printf("failed to open ledger account for employee %d: %v", employeeID, err)
+		return 0, database.ErrDatabaseInsertFailed
+	}
+
+	return accountID, nil
+}
+
+func employeeAccountID(ctx context.Context, tx pgx.Tx, employeeID int) (int, error) {
+	var accountID int
+	err := tx.QueryRow(ctx, "SELECT id FROM ledger_accounts WHERE employee_id = $1", employeeID).Scan(&accountID)
+	if err != nil {
+		log.Printf("failed to get ledger account for employee %d: %v", employeeID, err)
+		return 0, database.ErrEmployeeNotFound
+	}
+
+	return accountID, nil
+}
+
+func systemAccountID(ctx context.Context, tx pgx.Tx, code string) (int, error) {
+	var accountID int
+	err := tx.QueryRow(ctx, "SELECT id FROM ledger_accounts WHERE code = $1", code).Scan(&accountID)
+	if err != nil {
+		log.Printf("failed to get ledger account %q: %v", code, err)
+		return 0, database.ErrDatabaseQueryFailed
+	}
+
+	return accountID, nil
+}
+
+// lockEmployees locks the rows of the given employees and returns their
+// balances by id. Rows are locked in id order, so transactions that touch the
+// same employees wait for each other instead of deadlocking.
+func lockEmployees(ctx context.Context, tx pgx.Tx, employeeIDs ...int) (map[int]int, error) {
+	rows, err := tx.Query(ctx, "SELECT id, balance FROM employees WHERE id = ANY($1) ORDER BY id FOR UPDATE", employeeIDs)
+	if err != nil {
+		log.Printf("failed to lock employees %v: %v", employeeIDs, err)
+		return nil, txError(err, database.ErrDatabaseQueryFailed)
+	}
+	defer rows.Close()
+
+	balances := make(map[int]int, len(employeeIDs))
+	for rows.Next() {
+		var employeeID, balance int
+		if err := rows.Scan(&employeeID, &balance); err != nil {
+			log.Printf("failed to scan locked employee row: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		balances[employeeID] = balance
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to lock employees %v: %v", employeeIDs, err)
+		return nil, txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	return balances, nil
+}
+
+// postEntry records a balanced ledger entry and refreshes the cached balances
+// of the employee accounts it touches. It must run inside the caller's transaction.
+func postEntry(ctx context.Context, tx pgx.Tx, kind string, postings ...posting) (int, error) {
+	var entryID int
+	err := tx.QueryRow(ctx, "INSERT INTO ledger_entries (kind) VALUES ($1) RETURNING id", kind).Scan(&entryID)
+	if err != nil {
+		log.Printf("failed to insert %s ledger entry: %v", kind, err)
+		return 0, txError(err, database.ErrDatabaseInsertFailed)
+	}
+
+	for _, p := range postings {
+		_, err = tx.Exec(ctx, "INSERT INTO ledger_postings (entry_id, account_id, amount) VALUES ($1, $2, $3)", entryID, p.accountID, p.amount)
+		if err != nil {
+			log.Printf("failed to insert posting for ledger entry %d: %v", entryID, err)
+			return 0, txError(err, database.ErrDatabaseInsertFailed)
+		}
+
+		// Credits raise min_balance along with a negative balance, so a debt
+		// left by a clawback is paid off by incoming coins.
+		_, err = tx.Exec(ctx, `
+			UPDATE employees
+			SET balance = balance + $1,
+				min_balance = CASE WHEN $1 > 0 THEN LEAST(0, GREATEST(min_balance, balance + $1)) ELSE min_balance END
+			WHERE id = (SELECT employee_id FROM ledger_accounts WHERE id = $2)
+		`, p.amount, p.accountID)
+		if err != nil {
+			log.Printf("failed to update cached balance for ledger account %d: %v", p.accountID, err)
+			// The balance >= min_balance constraint is the last line of defence against overdrafts.
+			if pgErrorCode(err) == checkViolationCode {
+				return 0, database.ErrInsufficientFunds
+			}
+			return 0, txError(err, database.ErrDatabaseUpdateFailed)
+		}
+	}
+
+	return entryID, nil
+}
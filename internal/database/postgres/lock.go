@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+)
+
+// AdvisoryLock is a session-level Postgres advisory lock. It is held on a
+// connection taken out of the pool, so if the instance holding it dies the
+// connection drops and another instance can take over.
+type AdvisoryLock struct {
+	db  *pgxpool.Pool
+	key int64
+
+	mu   sync.Mutex
+	conn *pgxpool.Conn
+}
+
+func NewAdvisoryLock(db *pgxpool.Pool, key int64) *AdvisoryLock {
+	return &AdvisoryLock{
+		db:  db,
+		key: key,
+	}
+}
+
+// TryAcquire takes the lock without waiting and reports whether this instance
+// holds it. An instance that already holds the lock checks that its
+// connection is still alive.
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.Ping(ctx); err == nil {
+			return true, nil
+		}
+		log.Printf("lost connection holding advisory lock %d", l.key)
+		l.dropConn(ctx)
+	}
+
+	conn, err := l.db.Acquire(ctx)
+	if err != nil {
+		log.Printf("failed to acquire connection for advisory lock %d: %v", l.key, err)
+		return false, database.ErrDatabaseQueryFailed
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		conn.Release()
+		log.Printf("failed to take advisory lock %d: %v", l.key, err)
+		return false, database.ErrDatabaseQueryFailed
+	}
+
+	if !acquired {
+		conn.Release()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	_, err := l.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	if err != nil {
+		log.Printf("failed to release advisory lock %d: %v", l.key, err)
+		l.dropConn(ctx)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	l.conn.Release()
+	l.conn = nil
+	return nil
+}
+
+// dropConn closes the lock connection instead of returning it to the pool,
+// so a lock that may still be held is never handed to another caller.
+func (l *AdvisoryLock) dropConn(ctx context.Context) {
+	conn := l.conn.Hijack()
+	l.conn = nil
+	conn.Close(ctx)
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+)
+
+type LoginThrottleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginThrottleRepository(db *pgxpool.Pool) *LoginThrottleRepository {
+	return &LoginThrottleRepository{
+		db: db,
+	}
+}
+
+// GetLockout returns how long the key stays locked, or zero if it is not locked.
+func (r *LoginThrottleRepository) GetLockout(ctx context.Context, scope, key string) (time.Duration, error) {
+	var seconds float64
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(MAX(EXTRACT(EPOCH FROM locked_until - CURRENT_TIMESTAMP)), 0)
+		FROM login_throttles
+		WHERE scope = $1 AND key = $2 AND locked_until > CURRENT_TIMESTAMP
+	`, scope, key).Scan(&seconds)
+	if err != nil {
+		log.Printf("failed to get lockout for %s %q: %v", scope, key, err)
+		return 0, database.ErrDatabaseQueryFailed
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// RecordFailure counts a failed attempt and returns the number of failures in
+// the current window. A failure after a quiet period longer than window starts
+// a new count; the quiet period starts when the last lockout ends, so that
+// repeated lockouts keep growing.
+func (r *LoginThrottleRepository) RecordFailure(ctx context.Context, scope, key string, window time.Duration) (int, error) {
+	var failures int
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO login_throttles (scope, key, failures, last_failure_at)
+		VALUES ($1, $2, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET failures = CASE
+				WHEN GREATEST(login_throttles.last_failure_at, login_throttles.locked_until)
+					< CURRENT_TIMESTAMP - $3 * INTERVAL '1 second' THEN 1
+				ELSE login_throttles.failures + 1
+			END,
+			last_failure_at = CURRENT_TIMESTAMP
+		RETURNING failures
+	`, scope, key, int(window.Seconds())).Scan(&failures)
+	if err != nil {
+		log.Printf("failed to record login failure for %s %q: %v", scope, key, err)
+		return 0, database.ErrDatabaseInsertFailed
+	}
+
+	return failures, nil
+}
+
+func (r *LoginThrottleRepository) Lock(ctx context.Context, scope, key string, duration time.Duration) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE login_throttles SET locked_until = CURRENT_TIMESTAMP + $3 * INTERVAL '1 second'
+		WHERE scope = $1 AND key = $2
+	`, scope, key, int(duration.Seconds()))
+	if err != nil {
+		log.Printf("failed to lock %s %q: %v", scope, key, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	return nil
+}
+
+func (r *LoginThrottleRepository) Reset(ctx context.Context, scope, key string) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM login_throttles WHERE scope = $1 AND key = $2", scope, key)
+	if err != nil {
+		log.Printf("failed to reset login failures for %s %q: %v", scope, key, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	return nil
+}
@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"log"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/vit6556/avito-internship-assignment/internal/database"
@@ -22,8 +25,8 @@ func NewMerchRepository(db *pgxpool.Pool) *MerchRepository {
 
 func (r *MerchRepository) GetItemByID(ctx context.Context, itemID int) (*entity.MerchItem, error) {
 	var item entity.MerchItem
-	err := r.db.QueryRow(ctx, "SELECT id, name, price FROM merch_items WHERE id = $1", itemID).
-		Scan(&item.ID, &item.Name, &item.Price)
+	err := r.db.QueryRow(ctx, "SELECT id, name, price, COALESCE(category, ''), stock FROM merch_items WHERE id = $1", itemID).
+		Scan(&item.ID, &item.Name, &item.Price, &item.Category, &item.Stock)
 
 	if err != nil {
 		log.Printf("failed to get merch by ID %q: %v", itemID, err)
@@ -35,8 +38,8 @@ func (r *MerchRepository) GetItemByID(ctx context.Context, itemID int) (*entity.
 
 func (r *MerchRepository) GetItemByName(ctx context.Context, name string) (*entity.MerchItem, error) {
 	var item entity.MerchItem
-	err := r.db.QueryRow(ctx, "SELECT id, name, price FROM merch_items WHERE name = $1", name).
-		Scan(&item.ID, &item.Name, &item.Price)
+	err := r.db.QueryRow(ctx, "SELECT id, name, price, COALESCE(category, ''), stock FROM merch_items WHERE name = $1 AND retired_at IS NULL", name).
+		Scan(&item.ID, &item.Name, &item.Price, &item.Category, &item.Stock)
 
 	if err != nil {
 		log.Printf("failed to get merch by name %q: %v", name, err)
@@ -46,14 +49,99 @@ func (r *MerchRepository) GetItemByName(ctx context.Context, name string) (*enti
 	return &item, nil
 }
 
+func (r *MerchRepository) ListItems(ctx context.Context) ([]*entity.MerchItem, error) {
+	rows, err := r.db.Query(ctx, "SELECT id, name, price, COALESCE(category, ''), stock FROM merch_items WHERE retired_at IS NULL ORDER BY name")
+	if err != nil {
+		log.Printf("failed to list merch: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	items := make([]*entity.MerchItem, 0)
+	for rows.Next() {
+		var item entity.MerchItem
+		err := rows.Scan(&item.ID, &item.Name, &item.Price, &item.Category, &item.Stock)
+		if err != nil {
+			log.Printf("failed to scan merch row: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
+
+func (r *MerchRepository) CreateItem(ctx context.Context, item entity.MerchItem) (int, error) {
+	var itemID int
+	err := r.db.QueryRow(ctx, "INSERT INTO merch_items (name, price, category, stock) VALUES ($1, $2, NULLIF($3, ''), $4) RETURNING id",
+		item.Name, item.Price, item.Category, item.Stock).
+		Scan(&itemID)
+	if err != nil {
+		log.Printf("failed to create merch %q: %v", item.Name, err)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return 0, database.ErrMerchAlreadyExists
+		}
+		return 0, database.ErrDatabaseInsertFailed
+	}
+
+	return itemID, nil
+}
+
+func (r *MerchRepository) UpdateItemPrice(ctx context.Context, name string, price int) (*entity.MerchItem, error) {
+	var item entity.MerchItem
+	err := r.db.QueryRow(ctx, `
+		UPDATE merch_items SET price = $1 WHERE name = $2 AND retired_at IS NULL
+		RETURNING id, name, price, COALESCE(category, ''), stock
+	`, price, name).Scan(&item.ID, &item.Name, &item.Price, &item.Category, &item.Stock)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrMerchNotFound
+		}
+		log.Printf("failed to update price of merch %q: %v", name, err)
+		return nil, database.ErrDatabaseUpdateFailed
+	}
+
+	return &item, nil
+}
+
+func (r *MerchRepository) UpdateItemCategory(ctx context.Context, name, category string) error {
+	tag, err := r.db.Exec(ctx, "UPDATE merch_items SET category = NULLIF($1, '') WHERE name = $2 AND retired_at IS NULL", category, name)
+	if err != nil {
+		log.Printf("failed to update category of merch %q: %v", name, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrMerchNotFound
+	}
+
+	return nil
+}
+
+func (r *MerchRepository) RetireItem(ctx context.Context, name string) error {
+	tag, err := r.db.Exec(ctx, "UPDATE merch_items SET retired_at = CURRENT_TIMESTAMP WHERE name = $1 AND retired_at IS NULL", name)
+	if err != nil {
+		log.Printf("failed to retire merch %q: %v", name, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrMerchNotFound
+	}
+
+	return nil
+}
+
 func (r *MerchRepository) GetUserPurchases(ctx context.Context, userID int) ([]*entity.InventoryItem, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT m.name, SUM(p.amount) as total_quantity
+		SELECT m.name, SUM(p.amount) as total_quantity, SUM(p.total) as total_spent
 		FROM purchases p
 		JOIN merch_items m ON p.item_id = m.id
-		WHERE p.employee_id = $1
+		JOIN orders o ON p.order_id = o.id
+		WHERE p.employee_id = $1 AND o.status NOT IN ($2, $3)
 		GROUP BY m.name
-	`, userID)
+	`, userID, entity.OrderCancelled, entity.OrderRefunded)
 
 	if err != nil {
 		log.Printf("failed to get purchases for user %d: %v", userID, err)
@@ -64,7 +152,7 @@ func (r *MerchRepository) GetUserPurchases(ctx context.Context, userID int) ([]*
 	inventory := make([]*entity.InventoryItem, 0)
 	for rows.Next() {
 		var item entity.InventoryItem
-		err := rows.Scan(&item.Type, &item.Quantity)
+		err := rows.Scan(&item.Type, &item.Quantity, &item.Spent)
 		if err != nil {
 			log.Printf("failed to scan purchase row for user %d: %v", userID, err)
 			return nil, database.ErrDatabaseScanFailed
@@ -75,44 +163,203 @@ func (r *MerchRepository) GetUserPurchases(ctx context.Context, userID int) ([]*
 	return inventory, nil
 }
 
+// RestockItem adds quantity units to the item's stock. Untracked items start
+// being tracked from the given quantity.
+func (r *MerchRepository) RestockItem(ctx context.Context, name string, quantity int) (*entity.MerchItem, error) {
+	var item entity.MerchItem
+	err := r.db.QueryRow(ctx, `
+		UPDATE merch_items SET stock = COALESCE(stock, 0) + $1
+		WHERE name = $2 AND retired_at IS NULL
+		RETURNING id, name, price, COALESCE(category, ''), stock
+	`, quantity, name).Scan(&item.ID, &item.Name, &item.Price, &item.Category, &item.Stock)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrMerchNotFound
+		}
+		log.Printf("failed to restock merch %q: %v", name, err)
+		return nil, database.ErrDatabaseUpdateFailed
+	}
+
+	return &item, nil
+}
+
 func (r *MerchRepository) BuyItem(ctx context.Context, userID int, itemID int) error {
+	_, err := r.PlaceOrder(ctx, userID, []entity.OrderLine{{ItemID: itemID, Quantity: 1}}, "")
+	return err
+}
+
+// PlaceOrder charges the employee for all lines with a single ledger entry.
+// Prices are taken from the catalog and the running promotions inside the
+// transaction; promoCode, if set, must be the code of one of them.
+func (r *MerchRepository) PlaceOrder(ctx context.Context, userID int, lines []entity.OrderLine, promoCode string) (*entity.Order, error) {
+	var order *entity.Order
+	err := retryOnConflict(ctx, func() error {
+		var err error
+		order, err = r.placeOrder(ctx, userID, lines, promoCode)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *MerchRepository) placeOrder(ctx context.Context, userID int, lines []entity.OrderLine, promoCode string) (*entity.Order, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		log.Printf("failed to begin transaction for user %d: %v", userID, err)
-		return database.ErrDatabaseTransaction
+		return nil, database.ErrDatabaseTransaction
 	}
 	defer tx.Rollback(ctx)
 
-	var item entity.MerchItem
-	err = tx.QueryRow(ctx, "SELECT id, name, price FROM merch_items WHERE id = $1", itemID).
-		Scan(&item.ID, &item.Name, &item.Price)
+	requested := make(map[int]int, len(lines))
+	itemIDs := make([]int, 0, len(lines))
+	for _, line := range lines {
+		if _, ok := requested[line.ItemID]; !ok {
+			itemIDs = append(itemIDs, line.ItemID)
+		}
+		requested[line.ItemID] += line.Quantity
+	}
+
+	// Rows are locked in id order so that concurrent orders for the same items
+	// cannot deadlock and stock is checked against committed values.
+	rows, err := tx.Query(ctx, `
+		SELECT id, name, price, COALESCE(category, ''), stock FROM merch_items
+		WHERE id = ANY($1) AND retired_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`, itemIDs)
 	if err != nil {
-		log.Printf("failed to get merch by ID %q: %v", itemID, err)
-		return database.ErrMerchNotFound
+		log.Printf("failed to lock merch for user %d: %v", userID, err)
+		return nil, txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	items := make(map[int]entity.MerchItem, len(itemIDs))
+	for rows.Next() {
+		var item entity.MerchItem
+		if err := rows.Scan(&item.ID, &item.Name, &item.Price, &item.Category, &item.Stock); err != nil {
+			rows.Close()
+			log.Printf("failed to scan merch row: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		items[item.ID] = item
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to lock merch for user %d: %v", userID, err)
+		return nil, txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	for _, itemID := range itemIDs {
+		item, ok := items[itemID]
+		if !ok {
+			return nil, database.ErrMerchNotFound
+		}
+
+		if item.Stock != nil && *item.Stock < requested[itemID] {
+			return nil, database.ErrOutOfStock
+		}
+	}
+
+	order := entity.Order{
+		EmployeeID: userID,
+		Lines:      make([]entity.OrderLine, len(lines)),
+	}
+	for i, line := range lines {
+		item := items[line.ItemID]
+		order.Lines[i] = entity.OrderLine{
+			ItemID:   item.ID,
+			Item:     item.Name,
+			Quantity: line.Quantity,
+		}
+	}
+
+	if err := priceOrder(ctx, tx, &order, items, promoCode); err != nil {
+		return nil, err
 	}
 
-	var employeeBalance int
-	err = tx.QueryRow(ctx, "SELECT balance FROM employees WHERE id = $1", userID).Scan(&employeeBalance)
+	// The employee is locked after the merch rows, in the same order as
+	// refunds take them, and before the balance check.
+	balances, err := lockEmployees(ctx, tx, userID)
 	if err != nil {
-		log.Printf("failed to get balance for user %d: %v", userID, err)
-		return database.ErrEmployeeNotFound
+		return nil, err
 	}
 
-	if employeeBalance < item.Price {
-		return database.ErrInsufficientFunds
+	employeeBalance, ok := balances[userID]
+	if !ok {
+		log.Printf("employee %d not found", userID)
+		return nil, database.ErrEmployeeNotFound
 	}
 
-	_, err = tx.Exec(ctx, "UPDATE employees SET balance = balance - $1 WHERE id = $2", item.Price, userID)
+	if employeeBalance < order.Total {
+		return nil, database.ErrInsufficientFunds
+	}
+
+	accountID, err := employeeAccountID(ctx, tx, userID)
 	if err != nil {
-		log.Printf("failed to insert purchase record for user %d: %v", userID, err)
-		return database.ErrDatabaseInsertFailed
+		return nil, err
 	}
 
-	_, err = tx.Exec(ctx, "INSERT INTO purchases (employee_id, item_id, amount) VALUES ($1, $2, $3)", userID, item.ID, 1)
+	revenueAccountID, err := systemAccountID(ctx, tx, ledgerAccountShopRevenue)
 	if err != nil {
-		log.Printf("failed to commit transaction for user %d: %v", userID, err)
-		return database.ErrDatabaseTransaction
+		return nil, err
+	}
+
+	var entryID *int
+	if order.Total > 0 {
+		id, err := postEntry(ctx, tx, ledgerEntryPurchase,
+			posting{accountID: accountID, amount: -order.Total},
+			posting{accountID: revenueAccountID, amount: order.Total},
+		)
+		if err != nil {
+			return nil, err
+		}
+		entryID = &id
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO orders (employee_id, total, entry_id, office)
+		VALUES ($1, $2, $3, (SELECT office FROM employees WHERE id = $1))
+		RETURNING id, status, timestamp
+	`, userID, order.Total, entryID).Scan(&order.ID, &order.Status, &order.CreatedAt)
+	if err != nil {
+		log.Printf("failed to insert order for user %d: %v", userID, err)
+		return nil, txError(err, database.ErrDatabaseInsertFailed)
+	}
+
+	for _, line := range order.Lines {
+		var promotionID *int
+		if line.Promotion != nil {
+			promotionID = &line.Promotion.ID
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO purchases (employee_id, item_id, amount, entry_id, order_id, unit_price, total, promotion_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, userID, line.ItemID, line.Quantity, entryID, order.ID, line.UnitPrice, line.Total, promotionID)
+		if err != nil {
+			log.Printf("failed to insert purchase record for user %d: %v", userID, err)
+			return nil, txError(err, database.ErrDatabaseInsertFailed)
+		}
+	}
+
+	for _, itemID := range itemIDs {
+		if items[itemID].Stock == nil {
+			continue
+		}
+
+		_, err = tx.Exec(ctx, "UPDATE merch_items SET stock = stock - $1 WHERE id = $2", requested[itemID], itemID)
+		if err != nil {
+			log.Printf("failed to decrement stock of merch %d: %v", itemID, err)
+			return nil, txError(err, database.ErrDatabaseUpdateFailed)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit order for user %d: %v", userID, err)
+		return nil, txError(err, database.ErrDatabaseTransaction)
 	}
 
-	return tx.Commit(ctx)
+	return &order, nil
 }
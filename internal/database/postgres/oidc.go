@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type OIDCStateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOIDCStateRepository(db *pgxpool.Pool) *OIDCStateRepository {
+	return &OIDCStateRepository{
+		db: db,
+	}
+}
+
+// SaveState stores a pending login. States of logins that were never
+// completed are purged on the way.
+func (r *OIDCStateRepository) SaveState(ctx context.Context, state entity.OIDCLoginState, ttl time.Duration) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO oidc_login_states (state, code_verifier, nonce, expires_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP + $4 * INTERVAL '1 second')
+	`, state.State, state.CodeVerifier, state.Nonce, int(ttl.Seconds()))
+	if err != nil {
+		log.Printf("failed to store oidc login state: %v", err)
+		return database.ErrDatabaseInsertFailed
+	}
+
+	_, err = r.db.Exec(ctx, "DELETE FROM oidc_login_states WHERE expires_at < CURRENT_TIMESTAMP")
+	if err != nil {
+		log.Printf("failed to purge expired oidc login states: %v", err)
+	}
+
+	return nil
+}
+
+// ConsumeState deletes the pending login and returns it if it has not expired.
+func (r *OIDCStateRepository) ConsumeState(ctx context.Context, state string) (*entity.OIDCLoginState, error) {
+	var (
+		loginState entity.OIDCLoginState
+		valid      bool
+	)
+	err := r.db.QueryRow(ctx, `
+		DELETE FROM oidc_login_states
+		WHERE state = $1
+		RETURNING state, code_verifier, nonce, expires_at > CURRENT_TIMESTAMP
+	`, state).Scan(&loginState.State, &loginState.CodeVerifier, &loginState.Nonce, &valid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrOIDCStateNotFound
+		}
+		log.Printf("failed to consume oidc login state: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	if !valid {
+		return nil, database.ErrOIDCStateNotFound
+	}
+
+	return &loginState, nil
+}
@@ -0,0 +1,348 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+const orderColumns = `
+	o.id, o.employee_id, e.username, COALESCE(o.office, ''), o.total, o.status, o.timestamp, o.status_changed_at,
+	o.fulfilment_status, o.fulfilment_changed_at
+	FROM orders o
+	JOIN employees e ON e.id = o.employee_id
+`
+
+type OrderRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
+	return &OrderRepository{
+		db: db,
+	}
+}
+
+func scanOrder(row pgx.Row) (*entity.Order, error) {
+	var order entity.Order
+	err := row.Scan(&order.ID, &order.EmployeeID, &order.Employee, &order.Office, &order.Total, &order.Status, &order.CreatedAt, &order.StatusChangedAt,
+		&order.FulfilmentStatus, &order.FulfilmentChangedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *OrderRepository) GetOrder(ctx context.Context, orderID int) (*entity.Order, error) {
+	order, err := scanOrder(r.db.QueryRow(ctx, "SELECT "+orderColumns+" WHERE o.id = $1", orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrOrderNotFound
+		}
+		log.Printf("failed to get order %d: %v", orderID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	if err := r.loadOrderLines(ctx, []*entity.Order{order}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) ListOrders(ctx context.Context, filter entity.OrderFilter) ([]*entity.Order, error) {
+	conditions := []string{"TRUE"}
+	args := []any{}
+
+	if filter.EmployeeID != 0 {
+		args = append(args, filter.EmployeeID)
+		conditions = append(conditions, fmt.Sprintf("o.employee_id = $%d", len(args)))
+	}
+	if filter.Office != "" {
+		args = append(args, filter.Office)
+		conditions = append(conditions, fmt.Sprintf("o.office = $%d", len(args)))
+	}
+	if filter.Item != "" {
+		args = append(args, filter.Item)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM purchases p JOIN merch_items m ON m.id = p.item_id WHERE p.order_id = o.id AND m.name = $%d)", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("o.status = $%d", len(args)))
+	}
+	if filter.FulfilmentStatus != "" {
+		args = append(args, filter.FulfilmentStatus)
+		conditions = append(conditions, fmt.Sprintf("o.fulfilment_status = $%d", len(args)))
+	}
+
+	query := "SELECT " + orderColumns + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY o.timestamp DESC, o.id DESC"
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to list orders: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	orders := make([]*entity.Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			log.Printf("failed to scan order row: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to read orders: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	if err := r.loadOrderLines(ctx, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *OrderRepository) loadOrderLines(ctx context.Context, orders []*entity.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*entity.Order, len(orders))
+	orderIDs := make([]int, len(orders))
+	for i, order := range orders {
+		byID[order.ID] = order
+		orderIDs[i] = order.ID
+	}
+
+	// Purchases from before promotions have no recorded unit price; it is
+	// derived from their total.
+	rows, err := r.db.Query(ctx, `
+		SELECT p.order_id, p.item_id, m.name, p.amount, COALESCE(p.unit_price, p.total / NULLIF(p.amount, 0), 0), p.total
+		FROM purchases p
+		JOIN merch_items m ON m.id = p.item_id
+		WHERE p.order_id = ANY($1)
+		ORDER BY p.id
+	`, orderIDs)
+	if err != nil {
+		log.Printf("failed to get order lines: %v", err)
+		return database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID int
+		var line entity.OrderLine
+		if err := rows.Scan(&orderID, &line.ItemID, &line.Item, &line.Quantity, &line.UnitPrice, &line.Total); err != nil {
+			log.Printf("failed to scan order line: %v", err)
+			return database.ErrDatabaseScanFailed
+		}
+		byID[orderID].Lines = append(byID[orderID].Lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to read order lines: %v", err)
+		return database.ErrDatabaseQueryFailed
+	}
+
+	return nil
+}
+
+// SetOrderStatus moves an order to status if entity.OrderTransitions allows
+// it. Cancelling or refunding an order credits its total back to the employee
+// and returns the items to stock.
+func (r *OrderRepository) SetOrderStatus(ctx context.Context, orderID int, status string) error {
+	return retryOnConflict(ctx, func() error {
+		return r.setOrderStatus(ctx, orderID, status, 0)
+	})
+}
+
+// CancelOrder cancels a placed order that has not been packed yet and was
+// placed less than cancelWindow ago.
+func (r *OrderRepository) CancelOrder(ctx context.Context, orderID int, cancelWindow time.Duration) error {
+	return retryOnConflict(ctx, func() error {
+		return r.setOrderStatus(ctx, orderID, entity.OrderCancelled, cancelWindow)
+	})
+}
+
+func (r *OrderRepository) setOrderStatus(ctx context.Context, orderID int, status string, cancelWindow time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for order %d: %v", orderID, err)
+		return database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	var employeeID, total int
+	var current, fulfilment string
+	var cancellable bool
+	err = tx.QueryRow(ctx, `
+		SELECT employee_id, total, status, fulfilment_status, timestamp > CURRENT_TIMESTAMP - $2 * INTERVAL '1 second'
+		FROM orders WHERE id = $1 FOR UPDATE
+	`, orderID, int(cancelWindow.Seconds())).Scan(&employeeID, &total, &current, &fulfilment, &cancellable)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.ErrOrderNotFound
+		}
+		log.Printf("failed to lock order %d: %v", orderID, err)
+		return txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	if !slices.Contains(entity.OrderTransitions[current], status) {
+		return database.ErrOrderTransition
+	}
+
+	if status == entity.OrderCancelled {
+		if fulfilment != entity.FulfilmentNew {
+			return database.ErrOrderTransition
+		}
+		if !cancellable {
+			return database.ErrCancelWindowEnded
+		}
+	}
+
+	var refundEntryID *int
+	if status == entity.OrderCancelled || status == entity.OrderRefunded {
+		if err := restockOrder(ctx, tx, orderID); err != nil {
+			return err
+		}
+
+		if total > 0 {
+			id, err := refundOrder(ctx, tx, employeeID, total)
+			if err != nil {
+				return err
+			}
+			refundEntryID = &id
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE orders SET status = $2, refund_entry_id = $3, status_changed_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, orderID, status, refundEntryID)
+	if err != nil {
+		log.Printf("failed to set status of order %d to %q: %v", orderID, status, err)
+		return txError(err, database.ErrDatabaseUpdateFailed)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit status change of order %d: %v", orderID, err)
+		return txError(err, database.ErrDatabaseTransaction)
+	}
+
+	return nil
+}
+
+// AdvanceFulfilment moves a placed order to the fulfilment step status, which
+// must directly follow its current step. Handing the order over fulfils it.
+func (r *OrderRepository) AdvanceFulfilment(ctx context.Context, orderID int, status string) error {
+	return retryOnConflict(ctx, func() error {
+		return r.advanceFulfilment(ctx, orderID, status)
+	})
+}
+
+func (r *OrderRepository) advanceFulfilment(ctx context.Context, orderID int, status string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for order %d: %v", orderID, err)
+		return database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	var orderStatus, current string
+	err = tx.QueryRow(ctx, "SELECT status, fulfilment_status FROM orders WHERE id = $1 FOR UPDATE", orderID).
+		Scan(&orderStatus, &current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.ErrOrderNotFound
+		}
+		log.Printf("failed to lock order %d: %v", orderID, err)
+		return txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	step := slices.Index(entity.FulfilmentSteps, current)
+	if orderStatus != entity.OrderPlaced || step < 0 || step+1 >= len(entity.FulfilmentSteps) || entity.FulfilmentSteps[step+1] != status {
+		return database.ErrOrderTransition
+	}
+
+	orderStatus = entity.OrderPlaced
+	if status == entity.FulfilmentHandedOver {
+		orderStatus = entity.OrderFulfilled
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE orders SET
+			fulfilment_status = $2, fulfilment_changed_at = CURRENT_TIMESTAMP,
+			status = $3, status_changed_at = CASE WHEN status <> $3 THEN CURRENT_TIMESTAMP ELSE status_changed_at END
+		WHERE id = $1
+	`, orderID, status, orderStatus)
+	if err != nil {
+		log.Printf("failed to advance fulfilment of order %d to %q: %v", orderID, status, err)
+		return txError(err, database.ErrDatabaseUpdateFailed)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit fulfilment of order %d: %v", orderID, err)
+		return txError(err, database.ErrDatabaseTransaction)
+	}
+
+	return nil
+}
+
+// restockOrder returns the items of the order to the stock of tracked items.
+// The merch rows are locked in id order, like in PlaceOrder.
+func restockOrder(ctx context.Context, tx pgx.Tx, orderID int) error {
+	_, err := tx.Exec(ctx, `
+		SELECT id FROM merch_items
+		WHERE id IN (SELECT item_id FROM purchases WHERE order_id = $1) AND stock IS NOT NULL
+		ORDER BY id
+		FOR UPDATE
+	`, orderID)
+	if err != nil {
+		log.Printf("failed to lock merch of order %d: %v", orderID, err)
+		return txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE merch_items m SET stock = m.stock + p.quantity
+		FROM (SELECT item_id, SUM(amount) AS quantity FROM purchases WHERE order_id = $1 GROUP BY item_id) p
+		WHERE m.id = p.item_id AND m.stock IS NOT NULL
+	`, orderID)
+	if err != nil {
+		log.Printf("failed to restock merch of order %d: %v", orderID, err)
+		return txError(err, database.ErrDatabaseUpdateFailed)
+	}
+
+	return nil
+}
+
+func refundOrder(ctx context.Context, tx pgx.Tx, employeeID, total int) (int, error) {
+	accountID, err := employeeAccountID(ctx, tx, employeeID)
+	if err != nil {
+		return 0, err
+	}
+
+	revenueAccountID, err := systemAccountID(ctx, tx, ledgerAccountShopRevenue)
+	if err != nil {
+		return 0, err
+	}
+
+	return postEntry(ctx, tx, ledgerEntryOrderRefund,
+		posting{accountID: revenueAccountID, amount: -total},
+		posting{accountID: accountID, amount: total},
+	)
+}
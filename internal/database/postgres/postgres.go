@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+)
+
+const (
+	uniqueViolationCode      = "23505"
+	checkViolationCode       = "23514"
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+)
+
+const (
+	// maxTxAttempts bounds how many times a transaction that lost a conflict
+	// with a concurrent one is run.
+	maxTxAttempts = 3
+	txRetryDelay  = 10 * time.Millisecond
+)
+
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// txError maps a serialization failure or a detected deadlock, which say
+// nothing about the request itself, to database.ErrTransactionConflict and
+// any other error to fallback.
+func txError(err, fallback error) error {
+	switch pgErrorCode(err) {
+	case serializationFailureCode, deadlockDetectedCode:
+		return database.ErrTransactionConflict
+	default:
+		return fallback
+	}
+}
+
+// retryOnConflict runs op until it no longer fails with
+// database.ErrTransactionConflict, at most maxTxAttempts times. op must run
+// its own transaction, so every attempt starts from a clean state.
+func retryOnConflict(ctx context.Context, op func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = op()
+		if !errors.Is(err, database.ErrTransactionConflict) || attempt == maxTxAttempts {
+			return err
+		}
+
+		// Jitter keeps the transactions that collided from colliding again.
+		delay := time.Duration(attempt)*txRetryDelay + rand.N(txRetryDelay)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+	return err
+}
@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+// Uses are counted per order; cancelled and refunded orders do not count.
+const promotionColumns = `
+	pr.id, pr.name, pr.discount_type, pr.discount, pr.item_id, COALESCE(m.name, ''), COALESCE(pr.category, ''),
+	COALESCE(pr.code, ''), pr.starts_at, pr.ends_at, pr.max_uses, pr.max_uses_per_employee,
+	(SELECT COUNT(DISTINCT pu.order_id) FROM purchases pu JOIN orders o ON o.id = pu.order_id
+	 WHERE pu.promotion_id = pr.id AND o.status NOT IN ('cancelled', 'refunded')),
+	pr.timestamp, pr.disabled_at
+	FROM promotions pr
+	LEFT JOIN merch_items m ON m.id = pr.item_id
+`
+
+// activePromotionsQuery selects the running promotions without a code and
+// the one with code $1. Promotions with a code come first, so that the code
+// wins a tie in entity.BestPrice.
+const activePromotionsQuery = "SELECT " + promotionColumns + `
+	WHERE pr.disabled_at IS NULL AND pr.starts_at <= CURRENT_TIMESTAMP AND pr.ends_at > CURRENT_TIMESTAMP
+		AND (pr.code IS NULL OR pr.code = $1)
+	ORDER BY pr.code IS NULL, pr.id
+`
+
+type PromotionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPromotionRepository(db *pgxpool.Pool) *PromotionRepository {
+	return &PromotionRepository{
+		db: db,
+	}
+}
+
+func scanPromotion(row pgx.Row) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	err := row.Scan(&promotion.ID, &promotion.Name, &promotion.DiscountType, &promotion.Discount, &promotion.ItemID, &promotion.Item, &promotion.Category,
+		&promotion.Code, &promotion.StartsAt, &promotion.EndsAt, &promotion.MaxUses, &promotion.MaxUsesPerEmployee,
+		&promotion.Uses, &promotion.CreatedAt, &promotion.DisabledAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &promotion, nil
+}
+
+func scanPromotions(rows pgx.Rows) ([]*entity.Promotion, error) {
+	defer rows.Close()
+
+	promotions := make([]*entity.Promotion, 0)
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			log.Printf("failed to scan promotion row: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to read promotions: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return promotions, nil
+}
+
+func (r *PromotionRepository) CreatePromotion(ctx context.Context, promotion entity.Promotion, createdBy int) (int, error) {
+	var promotionID int
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO promotions (
+			name, discount_type, discount, item_id, category, code,
+			starts_at, ends_at, max_uses, max_uses_per_employee, created_by
+		)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10, $11)
+		RETURNING id
+	`, promotion.Name, promotion.DiscountType, promotion.Discount, promotion.ItemID, promotion.Category, promotion.Code,
+		promotion.StartsAt, promotion.EndsAt, promotion.MaxUses, promotion.MaxUsesPerEmployee, createdBy).Scan(&promotionID)
+	if err != nil {
+		if pgErrorCode(err) == uniqueViolationCode {
+			return 0, database.ErrPromoCodeAlreadyExists
+		}
+		log.Printf("failed to create promotion %q: %v", promotion.Name, err)
+		return 0, database.ErrDatabaseInsertFailed
+	}
+
+	return promotionID, nil
+}
+
+func (r *PromotionRepository) GetPromotion(ctx context.Context, promotionID int) (*entity.Promotion, error) {
+	promotion, err := scanPromotion(r.db.QueryRow(ctx, "SELECT "+promotionColumns+" WHERE pr.id = $1", promotionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrPromotionNotFound
+		}
+		log.Printf("failed to get promotion %d: %v", promotionID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return promotion, nil
+}
+
+func (r *PromotionRepository) ListPromotions(ctx context.Context) ([]*entity.Promotion, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+promotionColumns+" ORDER BY pr.starts_at DESC, pr.id DESC")
+	if err != nil {
+		log.Printf("failed to list promotions: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return scanPromotions(rows)
+}
+
+// ListActivePromotions returns the running promotions without a code and,
+// if code is set, the running promotion with that code.
+func (r *PromotionRepository) ListActivePromotions(ctx context.Context, code string) ([]*entity.Promotion, error) {
+	rows, err := r.db.Query(ctx, activePromotionsQuery, code)
+	if err != nil {
+		log.Printf("failed to list active promotions: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return scanPromotions(rows)
+}
+
+func (r *PromotionRepository) DisablePromotion(ctx context.Context, promotionID int) error {
+	tag, err := r.db.Exec(ctx, "UPDATE promotions SET disabled_at = CURRENT_TIMESTAMP WHERE id = $1 AND disabled_at IS NULL", promotionID)
+	if err != nil {
+		log.Printf("failed to disable promotion %d: %v", promotionID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrPromotionNotFound
+	}
+
+	return nil
+}
+
+// priceOrder sets the unit price of every line of the order to the best
+// price under the active promotions. Lines must already carry their catalog
+// item. With a promo code, the code must lower the price of at least one line
+// and still have uses left for the employee.
+func priceOrder(ctx context.Context, tx pgx.Tx, order *entity.Order, items map[int]entity.MerchItem, promoCode string) error {
+	rows, err := tx.Query(ctx, activePromotionsQuery, promoCode)
+	if err != nil {
+		log.Printf("failed to get active promotions: %v", err)
+		return txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	promotions, err := scanPromotions(rows)
+	if err != nil {
+		return err
+	}
+
+	var codePromotion *entity.Promotion
+	if promoCode != "" {
+		for _, promotion := range promotions {
+			if promotion.Code == promoCode {
+				codePromotion = promotion
+			}
+		}
+		if codePromotion == nil {
+			return database.ErrPromoCodeNotFound
+		}
+	}
+
+	codeUsed := false
+	order.Total = 0
+	for i, line := range order.Lines {
+		item := items[line.ItemID]
+		price, promotion := entity.BestPrice(item, promotions)
+
+		order.Lines[i].UnitPrice = price
+		order.Lines[i].Total = price * line.Quantity
+		order.Lines[i].Promotion = promotion
+		if promotion != nil {
+			order.Lines[i].ListPrice = item.Price
+		}
+		if codePromotion != nil && promotion == codePromotion {
+			codeUsed = true
+		}
+		order.Total += order.Lines[i].Total
+	}
+
+	if codePromotion == nil {
+		return nil
+	}
+	if !codeUsed {
+		return database.ErrPromoCodeNotApplicable
+	}
+
+	return checkPromotionUses(ctx, tx, codePromotion, order.EmployeeID)
+}
+
+// checkPromotionUses locks the promotion, so that concurrent orders with the
+// same code cannot both take its last use, and checks its limits.
+func checkPromotionUses(ctx context.Context, tx pgx.Tx, promotion *entity.Promotion, employeeID int) error {
+	if promotion.MaxUses == nil && promotion.MaxUsesPerEmployee == nil {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, "SELECT id FROM promotions WHERE id = $1 FOR UPDATE", promotion.ID)
+	if err != nil {
+		log.Printf("failed to lock promotion %d: %v", promotion.ID, err)
+		return txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	var uses, employeeUses int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT p.order_id), COUNT(DISTINCT p.order_id) FILTER (WHERE p.employee_id = $2)
+		FROM purchases p
+		JOIN orders o ON o.id = p.order_id
+		WHERE p.promotion_id = $1 AND o.status NOT IN ($3, $4)
+	`, promotion.ID, employeeID, entity.OrderCancelled, entity.OrderRefunded).Scan(&uses, &employeeUses)
+	if err != nil {
+		log.Printf("failed to count uses of promotion %d: %v", promotion.ID, err)
+		return txError(err, database.ErrDatabaseQueryFailed)
+	}
+
+	if promotion.MaxUses != nil && uses >= *promotion.MaxUses {
+		return database.ErrPromoCodeExhausted
+	}
+	if promotion.MaxUsesPerEmployee != nil && employeeUses >= *promotion.MaxUsesPerEmployee {
+		return database.ErrPromoCodeExhausted
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+const scheduleColumns = `
+	s.id, s.owner_id, s.name, s.amount, COALESCE(s.message, ''), COALESCE(s.category, ''),
+	COALESCE(s.cron, ''), COALESCE(s.interval_seconds, 0), s.active, s.next_run_at, s.timestamp,
+	ARRAY(
+		SELECT e.id FROM scheduled_transfer_recipients r JOIN employees e ON e.id = r.employee_id
+		WHERE r.schedule_id = s.id ORDER BY e.username
+	),
+	ARRAY(
+		SELECT e.username FROM scheduled_transfer_recipients r JOIN employees e ON e.id = r.employee_id
+		WHERE r.schedule_id = s.id ORDER BY e.username
+	)
+`
+
+type ScheduleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewScheduleRepository(db *pgxpool.Pool) *ScheduleRepository {
+	return &ScheduleRepository{
+		db: db,
+	}
+}
+
+func scanSchedule(row pgx.Row) (*entity.ScheduledTransfer, error) {
+	var schedule entity.ScheduledTransfer
+	var intervalSeconds int
+	var recipientIDs []int
+	var recipientNames []string
+	err := row.Scan(&schedule.ID, &schedule.OwnerID, &schedule.Name, &schedule.Amount, &schedule.Note.Message, &schedule.Note.Category,
+		&schedule.Cron, &intervalSeconds, &schedule.Active, &schedule.NextRunAt, &schedule.CreatedAt, &recipientIDs, &recipientNames)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Interval = time.Duration(intervalSeconds) * time.Second
+	schedule.Recipients = make([]entity.ScheduleRecipient, len(recipientIDs))
+	for i := range recipientIDs {
+		schedule.Recipients[i] = entity.ScheduleRecipient{EmployeeID: recipientIDs[i], Username: recipientNames[i]}
+	}
+
+	return &schedule, nil
+}
+
+func (r *ScheduleRepository) querySchedules(ctx context.Context, query string, args ...any) ([]*entity.ScheduledTransfer, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to list scheduled transfers: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	schedules := make([]*entity.ScheduledTransfer, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			log.Printf("failed to scan scheduled transfer: %v", err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to iterate scheduled transfers: %v", err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return schedules, nil
+}
+
+// nullableInterval stores schedules without an interval as NULL, as the
+// table requires exactly one of cron and interval_seconds.
+func nullableInterval(interval time.Duration) *int {
+	if interval <= 0 {
+		return nil
+	}
+	seconds := int(interval / time.Second)
+	return &seconds
+}
+
+func replaceRecipients(ctx context.Context, tx pgx.Tx, scheduleID int, recipients []entity.ScheduleRecipient) error {
+	_, err := tx.Exec(ctx, "DELETE FROM scheduled_transfer_recipients WHERE schedule_id = $1", scheduleID)
+	if err != nil {
+		log.Printf("failed to clear recipients of scheduled transfer %d: %v", scheduleID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	for _, recipient := range recipients {
+		_, err = tx.Exec(ctx, "INSERT INTO scheduled_transfer_recipients (schedule_id, employee_id) VALUES ($1, $2)", scheduleID, recipient.EmployeeID)
+		if err != nil {
+			log.Printf("failed to add recipient %d to scheduled transfer %d: %v", recipient.EmployeeID, scheduleID, err)
+			return database.ErrDatabaseInsertFailed
+		}
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) CreateSchedule(ctx context.Context, schedule entity.ScheduledTransfer) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for scheduled transfer of user %d: %v", schedule.OwnerID, err)
+		return 0, database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	var scheduleID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO scheduled_transfers (owner_id, name, amount, message, category, cron, interval_seconds, active, next_run_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9)
+		RETURNING id
+	`, schedule.OwnerID, schedule.Name, schedule.Amount, schedule.Note.Message, schedule.Note.Category,
+		schedule.Cron, nullableInterval(schedule.Interval), schedule.Active, schedule.NextRunAt).Scan(&scheduleID)
+	if err != nil {
+		log.Printf("failed to create scheduled transfer for user %d: %v", schedule.OwnerID, err)
+		return 0, database.ErrDatabaseInsertFailed
+	}
+
+	if err := replaceRecipients(ctx, tx, scheduleID, schedule.Recipients); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit scheduled transfer %d: %v", scheduleID, err)
+		return 0, database.ErrDatabaseTransaction
+	}
+
+	return scheduleID, nil
+}
+
+func (r *ScheduleRepository) GetSchedule(ctx context.Context, scheduleID int) (*entity.ScheduledTransfer, error) {
+	schedule, err := scanSchedule(r.db.QueryRow(ctx, "SELECT "+scheduleColumns+" FROM scheduled_transfers s WHERE s.id = $1", scheduleID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, database.ErrScheduleNotFound
+		}
+		log.Printf("failed to get scheduled transfer %d: %v", scheduleID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return schedule, nil
+}
+
+func (r *ScheduleRepository) ListSchedules(ctx context.Context, ownerID int) ([]*entity.ScheduledTransfer, error) {
+	return r.querySchedules(ctx, "SELECT "+scheduleColumns+" FROM scheduled_transfers s WHERE s.owner_id = $1 ORDER BY s.id", ownerID)
+}
+
+func (r *ScheduleRepository) UpdateSchedule(ctx context.Context, schedule entity.ScheduledTransfer) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for scheduled transfer %d: %v", schedule.ID, err)
+		return database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE scheduled_transfers
+		SET name = $2, amount = $3, message = NULLIF($4, ''), category = NULLIF($5, ''),
+			cron = NULLIF($6, ''), interval_seconds = $7, active = $8, next_run_at = $9
+		WHERE id = $1
+	`, schedule.ID, schedule.Name, schedule.Amount, schedule.Note.Message, schedule.Note.Category,
+		schedule.Cron, nullableInterval(schedule.Interval), schedule.Active, schedule.NextRunAt)
+	if err != nil {
+		log.Printf("failed to update scheduled transfer %d: %v", schedule.ID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrScheduleNotFound
+	}
+
+	if err := replaceRecipients(ctx, tx, schedule.ID, schedule.Recipients); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit scheduled transfer %d: %v", schedule.ID, err)
+		return database.ErrDatabaseTransaction
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) DeleteSchedule(ctx context.Context, scheduleID int) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM scheduled_transfers WHERE id = $1", scheduleID)
+	if err != nil {
+		log.Printf("failed to delete scheduled transfer %d: %v", scheduleID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrScheduleNotFound
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*entity.ScheduledTransfer, error) {
+	return r.querySchedules(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM scheduled_transfers s
+		WHERE s.active AND s.next_run_at <= $1
+		ORDER BY s.next_run_at, s.id
+		LIMIT $2
+	`, now, limit)
+}
+
+// AdvanceSchedule moves a due schedule to its next run. It reports false if
+// the schedule was changed or claimed by someone else since it was read, in
+// which case the caller must not run it.
+func (r *ScheduleRepository) AdvanceSchedule(ctx context.Context, scheduleID int, scheduledFor, nextRunAt time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE scheduled_transfers SET next_run_at = $3
+		WHERE id = $1 AND next_run_at = $2 AND active
+	`, scheduleID, scheduledFor, nextRunAt)
+	if err != nil {
+		log.Printf("failed to advance scheduled transfer %d: %v", scheduleID, err)
+		return false, database.ErrDatabaseUpdateFailed
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *ScheduleRepository) CreateScheduleRun(ctx context.Context, run entity.ScheduleRun) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO scheduled_transfer_runs (schedule_id, recipient_id, amount, status, error, scheduled_for)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+	`, run.ScheduleID, run.RecipientID, run.Amount, run.Status, run.Error, run.ScheduledFor)
+	if err != nil {
+		log.Printf("failed to record run of scheduled transfer %d: %v", run.ScheduleID, err)
+		return database.ErrDatabaseInsertFailed
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) ListScheduleRuns(ctx context.Context, scheduleID, limit int) ([]entity.ScheduleRun, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT run.id, run.schedule_id, COALESCE(run.recipient_id, 0), COALESCE(e.username, ''), run.amount,
+			run.status, COALESCE(run.error, ''), run.scheduled_for, run.timestamp
+		FROM scheduled_transfer_runs run
+		LEFT JOIN employees e ON e.id = run.recipient_id
+		WHERE run.schedule_id = $1
+		ORDER BY run.id DESC
+		LIMIT $2
+	`, scheduleID, limit)
+	if err != nil {
+		log.Printf("failed to list runs of scheduled transfer %d: %v", scheduleID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	runs := make([]entity.ScheduleRun, 0)
+	for rows.Next() {
+		var run entity.ScheduleRun
+		err := rows.Scan(&run.ID, &run.ScheduleID, &run.RecipientID, &run.Recipient, &run.Amount,
+			&run.Status, &run.Error, &run.ScheduledFor, &run.Timestamp)
+		if err != nil {
+			log.Printf("failed to scan run of scheduled transfer %d: %v", scheduleID, err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to iterate runs of scheduled transfer %d: %v", scheduleID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return runs, nil
+}
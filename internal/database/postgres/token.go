@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+)
+
+type TokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTokenRepository(db *pgxpool.Pool) *TokenRepository {
+	return &TokenRepository{
+		db: db,
+	}
+}
+
+func (r *TokenRepository) CreateRefreshToken(ctx context.Context, employeeID int, familyID, tokenHash string, ttl time.Duration) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO refresh_tokens (employee_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP + $4 * INTERVAL '1 second')
+	`, employeeID, familyID, tokenHash, int(ttl.Seconds()))
+	if err != nil {
+		log.Printf("failed to store refresh token for employee %d: %v", employeeID, err)
+		return database.ErrDatabaseInsertFailed
+	}
+
+	return nil
+}
+
+// RotateRefreshToken marks the presented token as used and stores its successor
+// in the same family. A token that was already used is treated as stolen: the
+// whole family is revoked and ErrRefreshTokenReused is returned.
+func (r *TokenRepository) RotateRefreshToken(ctx context.Context, tokenHash, newTokenHash string, ttl time.Duration) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for refresh token rotation: %v", err)
+		return 0, database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		tokenID    int
+		employeeID int
+		familyID   string
+		expired    bool
+		used       bool
+		revoked    bool
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT id, employee_id, family_id, expires_at <= CURRENT_TIMESTAMP, used_at IS NOT NULL, revoked_at IS NOT NULL
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`, tokenHash).Scan(&tokenID, &employeeID, &familyID, &expired, &used, &revoked)
+	if err != nil {
+		log.Printf("failed to get refresh token: %v", err)
+		return 0, database.ErrRefreshTokenNotFound
+	}
+
+	if revoked || expired {
+		return 0, database.ErrRefreshTokenNotFound
+	}
+
+	if used {
+		_, err = tx.Exec(ctx, "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = $1 AND revoked_at IS NULL", familyID)
+		if err != nil {
+			log.Printf("failed to revoke refresh token family of employee %d: %v", employeeID, err)
+			return 0, database.ErrDatabaseUpdateFailed
+		}
+
+		if err = tx.Commit(ctx); err != nil {
+			log.Printf("failed to commit refresh token family revocation: %v", err)
+			return 0, database.ErrDatabaseTransaction
+		}
+
+		return 0, database.ErrRefreshTokenReused
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1", tokenID)
+	if err != nil {
+		log.Printf("failed to mark refresh token %d as used: %v", tokenID, err)
+		return 0, database.ErrDatabaseUpdateFailed
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO refresh_tokens (employee_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP + $4 * INTERVAL '1 second')
+	`, employeeID, familyID, newTokenHash, int(ttl.Seconds()))
+	if err != nil {
+		log.Printf("failed to store refresh token for employee %d: %v", employeeID, err)
+		return 0, database.ErrDatabaseInsertFailed
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit refresh token rotation: %v", err)
+		return 0, database.ErrDatabaseTransaction
+	}
+
+	return employeeID, nil
+}
+
+func (r *TokenRepository) RevokeRefreshFamily(ctx context.Context, employeeID int, tokenHash string) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND employee_id = $2)
+		AND revoked_at IS NULL
+	`, tokenHash, employeeID)
+	if err != nil {
+		log.Printf("failed to revoke refresh token family of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if tag.RowsAffected() == 0 {
+		return database.ErrRefreshTokenNotFound
+	}
+
+	return nil
+}
+
+// RevokeAccessToken puts the token on the denylist until it expires.
+// Entries of tokens that have already expired are purged on the way.
+func (r *TokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO revoked_access_tokens (jti, expires_at)
+		VALUES ($1, to_timestamp($2))
+		ON CONFLICT (jti) DO NOTHING
+	`, jti, expiresAt.Unix())
+	if err != nil {
+		log.Printf("failed to revoke access token %q: %v", jti, err)
+		return database.ErrDatabaseInsertFailed
+	}
+
+	_, err = r.db.Exec(ctx, "DELETE FROM revoked_access_tokens WHERE expires_at < CURRENT_TIMESTAMP")
+	if err != nil {
+		log.Printf("failed to purge expired access tokens: %v", err)
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether the token is on the denylist or was
+// issued before the employee's token version last changed.
+func (r *TokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string, employeeID, tokenVersion int) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE jti = $1)
+		OR NOT EXISTS (SELECT 1 FROM employees WHERE id = $2 AND token_version = $3)
+	`, jti, employeeID, tokenVersion).Scan(&revoked)
+	if err != nil {
+		log.Printf("failed to check access token %q: %v", jti, err)
+		return false, database.ErrDatabaseQueryFailed
+	}
+
+	return revoked, nil
+}
+
+// CreatePasswordResetToken stores a new reset token for the employee. Reset
+// tokens issued to the employee earlier and not used yet stop being valid.
+func (r *TokenRepository) CreatePasswordResetToken(ctx context.Context, employeeID, issuerID int, tokenHash string, ttl time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for password reset of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE password_reset_tokens SET expires_at = CURRENT_TIMESTAMP
+		WHERE employee_id = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	`, employeeID)
+	if err != nil {
+		log.Printf("failed to expire password reset tokens of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO password_reset_tokens (employee_id, issued_by, token_hash, expires_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP + $4 * INTERVAL '1 second')
+	`, employeeID, issuerID, tokenHash, int(ttl.Seconds()))
+	if err != nil {
+		log.Printf("failed to store password reset token for employee %d: %v", employeeID, err)
+		return database.ErrDatabaseInsertFailed
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit password reset token of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseTransaction
+	}
+
+	return nil
+}
+
+// ResetPassword uses up a valid reset token and sets the password of the
+// employee it was issued to. The token is kept if the password is not changed.
+func (r *TokenRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for password reset: %v", err)
+		return database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	var employeeID int
+	err = tx.QueryRow(ctx, `
+		UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING employee_id
+	`, tokenHash).Scan(&employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return database.ErrPasswordResetTokenNotFound
+		}
+		log.Printf("failed to consume password reset token: %v", err)
+		return database.ErrDatabaseUpdateFailed
+	}
+
+	if err := updatePassword(ctx, tx, employeeID, passwordHash); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit password reset of employee %d: %v", employeeID, err)
+		return database.ErrDatabaseTransaction
+	}
+
+	return nil
+}
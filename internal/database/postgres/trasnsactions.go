@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -29,7 +31,7 @@ func (r *TransactionRepository) GetCoinHistory(ctx context.Context, userID int)
 		FROM transactions t
 		LEFT JOIN employees sender ON t.sender_id = sender.id
 		LEFT JOIN employees receiver ON t.receiver_id = receiver.id
-		WHERE t.sender_id = $1 OR t.receiver_id = $1
+		WHERE t.kind IN ('transfer', 'reversal') AND (t.sender_id = $1 OR t.receiver_id = $1)
 	`, userID)
 
 	if err != nil {
@@ -76,7 +78,105 @@ func (r *TransactionRepository) GetCoinHistory(ctx context.Context, userID int)
 	}, nil
 }
 
-func (r *TransactionRepository) SendCoins(ctx context.Context, senderID, receiverID, amount int) error {
+func (r *TransactionRepository) GetTransfers(ctx context.Context, userID int, filter entity.TransferFilter) ([]entity.Transfer, error) {
+	conditions := []string{"(t.sender_id = $1 OR t.receiver_id = $1)"}
+	args := []any{userID}
+
+	addCondition := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	switch filter.Direction {
+	case entity.TransferDirectionSent:
+		conditions = append(conditions, "t.sender_id = $1")
+	case entity.TransferDirectionReceived:
+		conditions = append(conditions, "t.receiver_id = $1")
+	}
+
+	if filter.Counterparty != "" {
+		addCondition("counterparty.username = $%d", filter.Counterparty)
+	}
+	if filter.Category != "" {
+		addCondition("t.category = $%d", filter.Category)
+	}
+	if filter.Search != "" {
+		addCondition(`t.message ILIKE '%%' || $%d || '%%' ESCAPE '\'`, escapeLikePattern(filter.Search))
+	}
+	if filter.From != nil {
+		addCondition("t.timestamp >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("t.timestamp < $%d", *filter.To)
+	}
+	if filter.After != nil {
+		addCondition("(t.timestamp, t.id) < ($%d, $%d)", filter.After.Timestamp, filter.After.ID)
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT
+			t.id,
+			t.kind,
+			CASE WHEN t.sender_id = $1 THEN 'sent' ELSE 'received' END AS direction,
+			COALESCE(counterparty.username, ''),
+			t.amount,
+			COALESCE(g.reason, ''),
+			COALESCE(t.message, ''),
+			COALESCE(t.category, ''),
+			COALESCE(t.reverses_id, 0),
+			COALESCE(reversal.id, 0),
+			t.timestamp
+		FROM transactions t
+		LEFT JOIN employees counterparty
+			ON counterparty.id = CASE WHEN t.sender_id = $1 THEN t.receiver_id ELSE t.sender_id END
+		LEFT JOIN grants g ON g.id = t.grant_id
+		LEFT JOIN transactions reversal ON reversal.reverses_id = t.id
+		WHERE %s
+		ORDER BY t.timestamp DESC, t.id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to get transfers for user %d: %v", userID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+	defer rows.Close()
+
+	transfers := make([]entity.Transfer, 0)
+	for rows.Next() {
+		var transfer entity.Transfer
+		err := rows.Scan(&transfer.ID, &transfer.Kind, &transfer.Direction, &transfer.Counterparty, &transfer.Amount, &transfer.Reason,
+			&transfer.Message, &transfer.Category, &transfer.ReversalOf, &transfer.ReversedBy, &transfer.Timestamp)
+		if err != nil {
+			log.Printf("failed to scan transfer row for user %d: %v", userID, err)
+			return nil, database.ErrDatabaseScanFailed
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("failed to read transfers for user %d: %v", userID, err)
+		return nil, database.ErrDatabaseQueryFailed
+	}
+
+	return transfers, nil
+}
+
+// SendCoins moves coins between two employees. A transaction that loses a
+// conflict with a concurrent one is run again.
+func (r *TransactionRepository) SendCoins(ctx context.Context, senderID, receiverID, amount int, note entity.TransferNote) error {
+	return retryOnConflict(ctx, func() error {
+		return r.sendCoins(ctx, senderID, receiverID, amount, note)
+	})
+}
+
+func (r *TransactionRepository) sendCoins(ctx context.Context, senderID, receiverID, amount int, note entity.TransferNote) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		log.Printf("failed to begin transaction for sending coins from user %d to user %d: %v", senderID, receiverID, err)
@@ -84,10 +184,20 @@ func (r *TransactionRepository) SendCoins(ctx context.Context, senderID, receive
 	}
 	defer tx.Rollback(ctx)
 
-	var senderBalance int
-	err = tx.QueryRow(ctx, "SELECT balance FROM employees WHERE id = $1", senderID).Scan(&senderBalance)
+	// Both rows are locked before the balance check, so the check still holds
+	// when the balances are updated.
+	balances, err := lockEmployees(ctx, tx, senderID, receiverID)
 	if err != nil {
-		log.Printf("failed to get balance for sender %d: %v", senderID, err)
+		return err
+	}
+
+	senderBalance, ok := balances[senderID]
+	if !ok {
+		log.Printf("sender %d not found", senderID)
+		return database.ErrEmployeeNotFound
+	}
+	if _, ok := balances[receiverID]; !ok {
+		log.Printf("receiver %d not found", receiverID)
 		return database.ErrEmployeeNotFound
 	}
 
@@ -95,28 +205,132 @@ func (r *TransactionRepository) SendCoins(ctx context.Context, senderID, receive
 		return database.ErrInsufficientFunds
 	}
 
-	_, err = tx.Exec(ctx, "UPDATE employees SET balance = balance - $1 WHERE id = $2", amount, senderID)
+	senderAccountID, err := employeeAccountID(ctx, tx, senderID)
 	if err != nil {
-		log.Printf("failed to update sender %d balance: %v", senderID, err)
-		return database.ErrDatabaseUpdateFailed
+		return err
 	}
 
-	_, err = tx.Exec(ctx, "UPDATE employees SET balance = balance + $1 WHERE id = $2", amount, receiverID)
+	receiverAccountID, err := employeeAccountID(ctx, tx, receiverID)
 	if err != nil {
-		log.Printf("failed to update receiver %d balance: %v", receiverID, err)
-		return database.ErrDatabaseUpdateFailed
+		return err
 	}
 
-	_, err = tx.Exec(ctx, "INSERT INTO transactions (sender_id, receiver_id, amount) VALUES ($1, $2, $3)", senderID, receiverID, amount)
+	entryID, err := postEntry(ctx, tx, ledgerEntryTransfer,
+		posting{accountID: senderAccountID, amount: -amount},
+		posting{accountID: receiverAccountID, amount: amount},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO transactions (sender_id, receiver_id, amount, entry_id, message, category)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+	`, senderID, receiverID, amount, entryID, note.Message, note.Category)
 	if err != nil {
 		log.Printf("failed to insert transaction record for sender %d -> receiver %d: %v", senderID, receiverID, err)
-		return database.ErrDatabaseInsertFailed
+		return txError(err, database.ErrDatabaseInsertFailed)
 	}
 
 	if err = tx.Commit(ctx); err != nil {
 		log.Printf("failed to commit transaction for sender %d -> receiver %d: %v", senderID, receiverID, err)
-		return database.ErrDatabaseTransaction
+		return txError(err, database.ErrDatabaseTransaction)
 	}
 
 	return nil
 }
+
+// IssueCoins credits every grant from the issuance account as a single ledger
+// entry. A transaction that loses a conflict with a concurrent one is run again.
+func (r *TransactionRepository) IssueCoins(ctx context.Context, issuerID int, reason string, grants []entity.CoinGrant) (int, error) {
+	var grantID int
+	err := retryOnConflict(ctx, func() error {
+		var err error
+		grantID, err = r.issueCoins(ctx, issuerID, reason, grants)
+		return err
+	})
+	return grantID, err
+}
+
+func (r *TransactionRepository) issueCoins(ctx context.Context, issuerID int, reason string, grants []entity.CoinGrant) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Printf("failed to begin transaction for grant by user %d: %v", issuerID, err)
+		return 0, database.ErrDatabaseTransaction
+	}
+	defer tx.Rollback(ctx)
+
+	// The recipients are locked in id order up front, so a grant does not
+	// deadlock with transfers between the same employees.
+	recipientIDs := make([]int, len(grants))
+	for i, grant := range grants {
+		recipientIDs[i] = grant.EmployeeID
+	}
+	balances, err := lockEmployees(ctx, tx, recipientIDs...)
+	if err != nil {
+		return 0, err
+	}
+	for _, grant := range grants {
+		if _, ok := balances[grant.EmployeeID]; !ok {
+			log.Printf("grant recipient %d not found", grant.EmployeeID)
+			return 0, database.ErrEmployeeNotFound
+		}
+	}
+
+	var grantID int
+	err = tx.QueryRow(ctx, "INSERT INTO grants (issued_by, reason) VALUES ($1, $2) RETURNING id", issuerID, reason).Scan(&grantID)
+	if err != nil {
+		log.Printf("failed to insert grant by user %d: %v", issuerID, err)
+		return 0, txError(err, database.ErrDatabaseInsertFailed)
+	}
+
+	issuanceAccountID, err := systemAccountID(ctx, tx, ledgerAccountIssuance)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	postings := make([]posting, 0, len(grants)+1)
+	for _, grant := range grants {
+		accountID, err := employeeAccountID(ctx, tx, grant.EmployeeID)
+		if err != nil {
+			return 0, err
+		}
+
+		total += grant.Amount
+		postings = append(postings, posting{accountID: accountID, amount: grant.Amount})
+	}
+	postings = append(postings, posting{accountID: issuanceAccountID, amount: -total})
+
+	entryID, err := postEntry(ctx, tx, ledgerEntryIssuance, postings...)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE grants SET entry_id = $1 WHERE id = $2", entryID, grantID)
+	if err != nil {
+		log.Printf("failed to link grant %d to ledger entry %d: %v", grantID, entryID, err)
+		return 0, txError(err, database.ErrDatabaseUpdateFailed)
+	}
+
+	for _, grant := range grants {
+		_, err = tx.Exec(ctx, "INSERT INTO transactions (receiver_id, amount, kind, grant_id, entry_id) VALUES ($1, $2, $3, $4, $5)",
+			grant.EmployeeID, grant.Amount, entity.TransferKindIssuance, grantID, entryID)
+		if err != nil {
+			log.Printf("failed to insert issuance record for grant %d to user %d: %v", grantID, grant.EmployeeID, err)
+			return 0, txError(err, database.ErrDatabaseInsertFailed)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("failed to commit grant %d: %v", grantID, err)
+		return 0, txError(err, database.ErrDatabaseTransaction)
+	}
+
+	return grantID, nil
+}
+
+// escapeLikePattern makes the LIKE wildcards in a search text match literally.
+func escapeLikePattern(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
+}
@@ -4,3 +4,49 @@ type AuthRequest struct {
 	Username string `form:"username" validate:"required,min=3,max=20,alphanum"`
 	Password string `form:"password" validate:"required,min=6"`
 }
+
+type RegisterRequest struct {
+	Username   string `json:"username" validate:"required,min=3,max=20,alphanum"`
+	Password   string `json:"password" validate:"required,min=6"`
+	InviteCode string `json:"inviteCode"`
+}
+
+type AuthResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=6"`
+}
+
+type PasswordResetResponse struct {
+	ResetToken string `json:"resetToken"`
+	ExpiresAt  string `json:"expiresAt"`
+}
+
+type ResetPasswordRequest struct {
+	ResetToken  string `json:"resetToken" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=6"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
@@ -1,8 +1,12 @@
 package dto
 
+import "time"
+
 type SendCoinRequest struct {
-	ToUser string `json:"toUser" validate:"required"`
-	Amount int    `json:"amount" validate:"required,min=1"`
+	ToUser   string `json:"toUser" validate:"required"`
+	Amount   int    `json:"amount" validate:"required,min=1"`
+	Message  string `json:"message"`
+	Category string `json:"category"`
 }
 
 type CoinHistory struct {
@@ -14,3 +18,49 @@ type CoinTransaction struct {
 	User   string `json:"user"`
 	Amount int    `json:"amount"`
 }
+
+type HistoryRequest struct {
+	Direction    string `query:"direction" validate:"omitempty,oneof=sent received"`
+	Counterparty string `query:"counterparty"`
+	Category     string `query:"category"`
+	Search       string `query:"q" validate:"max=100"`
+	From         string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To           string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Cursor       string `query:"cursor"`
+	Limit        int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type HistoryResponse struct {
+	Transactions []HistoryTransaction `json:"transactions"`
+	NextCursor   string               `json:"nextCursor,omitempty"`
+}
+
+type HistoryTransaction struct {
+	ID           int       `json:"id"`
+	Kind         string    `json:"kind"`
+	Counterparty string    `json:"counterparty,omitempty"`
+	Amount       int       `json:"amount"`
+	Direction    string    `json:"direction"`
+	Reason       string    `json:"reason,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	Category     string    `json:"category,omitempty"`
+	ReversalOf   int       `json:"reversalOf,omitempty"`
+	ReversedBy   int       `json:"reversedBy,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+type GrantRequest struct {
+	Reason string      `json:"reason" validate:"required,max=255"`
+	Grants []GrantLine `json:"grants" validate:"required,min=1,max=1000,dive"`
+}
+
+type GrantLine struct {
+	User   string `json:"user" validate:"required"`
+	Amount int    `json:"amount" validate:"required,min=1,max=1000000"`
+}
+
+type GrantResponse struct {
+	GrantID    int `json:"grantId"`
+	Total      int `json:"total"`
+	Recipients int `json:"recipients"`
+}
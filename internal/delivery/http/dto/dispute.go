@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type OpenDisputeRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type ListDisputesRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=open approved rejected"`
+}
+
+type ResolveDisputeRequest struct {
+	Note string `json:"note" validate:"max=500"`
+	// Force approves the reversal even if the recipient has already spent
+	// the coins, taking their balance below zero.
+	Force bool `json:"force"`
+}
+
+type Dispute struct {
+	ID            int        `json:"id"`
+	TransactionID int        `json:"transactionId"`
+	FromUser      string     `json:"fromUser"`
+	ToUser        string     `json:"toUser"`
+	Amount        int        `json:"amount"`
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status"`
+	ResolvedBy    string     `json:"resolvedBy,omitempty"`
+	Note          string     `json:"note,omitempty"`
+	ReversalID    int        `json:"reversalId,omitempty"`
+	Clawback      bool       `json:"clawback,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	ResolvedAt    *time.Time `json:"resolvedAt,omitempty"`
+}
@@ -9,4 +9,13 @@ type EmployeeInfoResponse struct {
 type InventoryItem struct {
 	Type     string `json:"type"`
 	Quantity int    `json:"quantity"`
+	Spent    int    `json:"spent"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+type SetOfficeRequest struct {
+	Office string `json:"office" validate:"max=64"`
 }
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type PendingTransfer struct {
+	ID         int        `json:"id"`
+	FromUser   string     `json:"fromUser"`
+	ToUser     string     `json:"toUser"`
+	Amount     int        `json:"amount"`
+	Message    string     `json:"message,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+}
+
+type PendingTransfersResponse struct {
+	Incoming []PendingTransfer `json:"incoming"`
+	Outgoing []PendingTransfer `json:"outgoing"`
+}
@@ -0,0 +1,93 @@
+package dto
+
+import "time"
+
+type MerchItem struct {
+	Name     string `json:"name"`
+	Price    int    `json:"price"`
+	Category string `json:"category,omitempty"`
+	Stock    *int   `json:"stock,omitempty"`
+	// PromoPrice is set while a promotion without a code lowers the price.
+	PromoPrice  *int       `json:"promoPrice,omitempty"`
+	PromoEndsAt *time.Time `json:"promoEndsAt,omitempty"`
+}
+
+type CreateMerchRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Price    *int   `json:"price" validate:"required,min=0"`
+	Stock    *int   `json:"stock" validate:"omitempty,min=0"`
+	Category string `json:"category" validate:"max=64"`
+}
+
+type SetMerchCategoryRequest struct {
+	Category string `json:"category" validate:"max=64"`
+}
+
+type UpdateMerchPriceRequest struct {
+	Price *int `json:"price" validate:"required,min=0"`
+}
+
+type RestockMerchRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+type CheckoutRequest struct {
+	Items     []CartLine `json:"items" validate:"required,min=1,max=50,dive"`
+	PromoCode string     `json:"promoCode" validate:"max=32"`
+}
+
+type CartLine struct {
+	Item     string `json:"item" validate:"required"`
+	Quantity int    `json:"quantity" validate:"required,min=1,max=100"`
+}
+
+type OrderResponse struct {
+	OrderID int         `json:"orderId"`
+	Total   int         `json:"total"`
+	Lines   []OrderLine `json:"lines"`
+}
+
+type OrderLine struct {
+	Item      string `json:"item"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int    `json:"unitPrice"`
+	Total     int    `json:"total"`
+	// ListPrice and Promotion are set when a promotion lowered the price.
+	ListPrice int    `json:"listPrice,omitempty"`
+	Promotion string `json:"promotion,omitempty"`
+}
+
+type ListOrdersRequest struct {
+	Office           string `query:"office" validate:"max=64"`
+	Item             string `query:"item" validate:"max=100"`
+	Status           string `query:"status" validate:"omitempty,oneof=placed fulfilled cancelled refunded"`
+	FulfilmentStatus string `query:"fulfilment" validate:"omitempty,oneof=new packed ready_for_pickup handed_over"`
+}
+
+type AdvanceOrderRequest struct {
+	// Status is the fulfilment step to move to. It guards against advancing
+	// an order twice; when empty the order moves to its next step.
+	Status string `json:"status" validate:"omitempty,oneof=packed ready_for_pickup handed_over"`
+}
+
+type Order struct {
+	ID                  int         `json:"id"`
+	Employee            string      `json:"employee"`
+	Office              string      `json:"office,omitempty"`
+	Status              string      `json:"status"`
+	FulfilmentStatus    string      `json:"fulfilmentStatus"`
+	Total               int         `json:"total"`
+	Items               []OrderItem `json:"items"`
+	CreatedAt           time.Time   `json:"createdAt"`
+	StatusChangedAt     *time.Time  `json:"statusChangedAt,omitempty"`
+	FulfilmentChangedAt *time.Time  `json:"fulfilmentChangedAt,omitempty"`
+	// CancellableUntil is set while the employee can still cancel the order.
+	CancellableUntil *time.Time `json:"cancellableUntil,omitempty"`
+}
+
+type OrderItem struct {
+	Item      string `json:"item"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int    `json:"unitPrice"`
+	Total     int    `json:"total"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type CreatePromotionRequest struct {
+	Name         string `json:"name" validate:"required,max=100"`
+	DiscountType string `json:"discountType" validate:"required,oneof=percent fixed"`
+	Discount     int    `json:"discount" validate:"required,min=1"`
+	// Item and Category are mutually exclusive; without both the promotion
+	// covers the whole catalog.
+	Item     string `json:"item" validate:"max=100"`
+	Category string `json:"category" validate:"max=64"`
+	Code     string `json:"code" validate:"omitempty,min=3,max=32,alphanum"`
+	// StartsAt defaults to the time the promotion is created.
+	StartsAt           *time.Time `json:"startsAt"`
+	EndsAt             time.Time  `json:"endsAt" validate:"required"`
+	MaxUses            *int       `json:"maxUses" validate:"omitempty,min=1"`
+	MaxUsesPerEmployee *int       `json:"maxUsesPerEmployee" validate:"omitempty,min=1"`
+}
+
+type Promotion struct {
+	ID                 int        `json:"id"`
+	Name               string     `json:"name"`
+	DiscountType       string     `json:"discountType"`
+	Discount           int        `json:"discount"`
+	Item               string     `json:"item,omitempty"`
+	Category           string     `json:"category,omitempty"`
+	Code               string     `json:"code,omitempty"`
+	Status             string     `json:"status"`
+	StartsAt           time.Time  `json:"startsAt"`
+	EndsAt             time.Time  `json:"endsAt"`
+	MaxUses            *int       `json:"maxUses,omitempty"`
+	MaxUsesPerEmployee *int       `json:"maxUsesPerEmployee,omitempty"`
+	Uses               int        `json:"uses"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	DisabledAt         *time.Time `json:"disabledAt,omitempty"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type ScheduleRequest struct {
+	Name       string   `json:"name" validate:"max=100"`
+	Recipients []string `json:"recipients" validate:"required,min=1,max=100,dive,required"`
+	Amount     int      `json:"amount" validate:"required,min=1"`
+	Message    string   `json:"message"`
+	Category   string   `json:"category"`
+	// Exactly one of Cron and Interval must be set.
+	Cron     string `json:"cron"`
+	Interval string `json:"interval"`
+	Active   *bool  `json:"active"`
+}
+
+type Schedule struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	Recipients []string  `json:"recipients"`
+	Amount     int       `json:"amount"`
+	Message    string    `json:"message,omitempty"`
+	Category   string    `json:"category,omitempty"`
+	Cron       string    `json:"cron,omitempty"`
+	Interval   string    `json:"interval,omitempty"`
+	Active     bool      `json:"active"`
+	NextRunAt  time.Time `json:"nextRunAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ScheduleRun struct {
+	ID           int       `json:"id"`
+	Recipient    string    `json:"recipient"`
+	Amount       int       `json:"amount"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	ScheduledFor time.Time `json:"scheduledFor"`
+	Timestamp    time.Time `json:"timestamp"`
+}
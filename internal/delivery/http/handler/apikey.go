@@ -0,0 +1,88 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+	validate      *validator.Validate
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validate:      validator.New(),
+	}
+}
+
+func (h *APIKeyHandler) CreateKey(c echo.Context) error {
+	creatorID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.CreateAPIKeyRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	key, err := h.apiKeyService.Create(c.Request().Context(), creatorID, request.Username, request.Name, request.Scopes, request.ExpiresAt)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidScope:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid api key scope"})
+		case service.ErrInvalidExpiry:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "expiresAt must be in the future"})
+		case service.ErrEmployeeNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create api key"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, key)
+}
+
+func (h *APIKeyHandler) ListKeys(c echo.Context) error {
+	keys, err := h.apiKeyService.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list api keys"})
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) RevokeKey(c echo.Context) error {
+	keyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid api key id"})
+	}
+
+	err = h.apiKeyService.Revoke(c.Request().Context(), keyID)
+	if err != nil {
+		switch err {
+		case service.ErrAPIKeyNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "api key not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to revoke api key"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "api key revoked successfully"})
+}
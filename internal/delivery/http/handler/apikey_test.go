@@ -0,0 +1,237 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestCreateKey(t *testing.T) {
+	e := echo.New()
+	mockAPIKeyService := new(mock.MockAPIKeyService)
+	apiKeyHandler := httphandler.NewAPIKeyHandler(mockAPIKeyService)
+
+	createdAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Key created",
+			requestBody: `{"username":"thanksbot","name":"slack","scopes":["coins:send"]}`,
+			mockSetup: func() {
+				mockAPIKeyService.On("Create", testifyMock.Anything, 1, "thanksbot", "slack", []string{"coins:send"}, (*time.Time)(nil)).
+					Return(&dto.CreatedAPIKey{
+						APIKey: dto.APIKey{ID: 7, Username: "thanksbot", Name: "slack", Prefix: "shop_abcdefg", Scopes: []string{"coins:send"}, CreatedAt: createdAt},
+						Key:    "shop_abcdefgsecret",
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":7,"username":"thanksbot","name":"slack","prefix":"shop_abcdefg","scopes":["coins:send"],` +
+				`"createdAt":"2024-06-01T12:00:00Z","key":"shop_abcdefgsecret"}`,
+		},
+		{
+			name:           "Error - No scopes",
+			requestBody:    `{"username":"thanksbot","name":"slack","scopes":[]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Unknown scope",
+			requestBody: `{"username":"thanksbot","name":"slack","scopes":["admin"]}`,
+			mockSetup: func() {
+				mockAPIKeyService.On("Create", testifyMock.Anything, 1, "thanksbot", "slack", []string{"admin"}, (*time.Time)(nil)).
+					Return(nil, service.ErrInvalidScope).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid api key scope"}`,
+		},
+		{
+			name:        "Error - Expiry in the past",
+			requestBody: `{"username":"thanksbot","name":"slack","scopes":["coins:send"],"expiresAt":"2020-01-01T00:00:00Z"}`,
+			mockSetup: func() {
+				mockAPIKeyService.On("Create", testifyMock.Anything, 1, "thanksbot", "slack", []string{"coins:send"}, testifyMock.Anything).
+					Return(nil, service.ErrInvalidExpiry).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"expiresAt must be in the future"}`,
+		},
+		{
+			name:        "Error - Employee not found",
+			requestBody: `{"username":"ghost","name":"slack","scopes":["coins:send"]}`,
+			mockSetup: func() {
+				mockAPIKeyService.On("Create", testifyMock.Anything, 1, "ghost", "slack", []string{"coins:send"}, (*time.Time)(nil)).
+					Return(nil, service.ErrEmployeeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"employee not found"}`,
+		},
+		{
+			name:        "Error - Internal Server Error",
+			requestBody: `{"username":"thanksbot","name":"slack","scopes":["coins:send"]}`,
+			mockSetup: func() {
+				mockAPIKeyService.On("Create", testifyMock.Anything, 1, "thanksbot", "slack", []string{"coins:send"}, (*time.Time)(nil)).
+					Return(nil, service.ErrAPIKeyCreationFailed).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to create api key"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/api-keys", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+
+			err := apiKeyHandler.CreateKey(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAPIKeyService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	e := echo.New()
+	mockAPIKeyService := new(mock.MockAPIKeyService)
+	apiKeyHandler := httphandler.NewAPIKeyHandler(mockAPIKeyService)
+
+	createdAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success - Keys listed",
+			mockSetup: func() {
+				mockAPIKeyService.On("List", testifyMock.Anything).
+					Return([]dto.APIKey{
+						{ID: 7, Username: "thanksbot", Name: "slack", Prefix: "shop_abcdefg", Scopes: []string{"coins:send"}, CreatedAt: createdAt, RevokedAt: &createdAt},
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":7,"username":"thanksbot","name":"slack","prefix":"shop_abcdefg","scopes":["coins:send"],` +
+				`"revokedAt":"2024-06-01T12:00:00Z","createdAt":"2024-06-01T12:00:00Z"}]`,
+		},
+		{
+			name: "Error - Internal Server Error",
+			mockSetup: func() {
+				mockAPIKeyService.On("List", testifyMock.Anything).
+					Return(nil, service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to list api keys"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/api-keys", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := apiKeyHandler.ListKeys(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAPIKeyService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeKey(t *testing.T) {
+	e := echo.New()
+	mockAPIKeyService := new(mock.MockAPIKeyService)
+	apiKeyHandler := httphandler.NewAPIKeyHandler(mockAPIKeyService)
+
+	tests := []struct {
+		name           string
+		keyID          string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success - Key revoked",
+			keyID: "7",
+			mockSetup: func() {
+				mockAPIKeyService.On("Revoke", testifyMock.Anything, 7).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"api key revoked successfully"}`,
+		},
+		{
+			name:           "Error - Invalid id",
+			keyID:          "abc",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid api key id"}`,
+		},
+		{
+			name:  "Error - Key not found",
+			keyID: "8",
+			mockSetup: func() {
+				mockAPIKeyService.On("Revoke", testifyMock.Anything, 8).Return(service.ErrAPIKeyNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"api key not found"}`,
+		},
+		{
+			name:  "Error - Internal Server Error",
+			keyID: "7",
+			mockSetup: func() {
+				mockAPIKeyService.On("Revoke", testifyMock.Anything, 7).Return(service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to revoke api key"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/admin/api-keys/"+tt.keyID, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/admin/api-keys/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(tt.keyID)
+
+			err := apiKeyHandler.RevokeKey(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAPIKeyService.AssertExpectations(t)
+		})
+	}
+}
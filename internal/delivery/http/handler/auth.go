@@ -8,22 +8,25 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
 type AuthHandler struct {
-	authService service.AuthService
-	tokenTTL    time.Duration
-	secure      bool
-	validate    *validator.Validate
+	authService     service.AuthService
+	throttleService service.LoginThrottleService
+	tokenTTL        time.Duration
+	secure          bool
+	validate        *validator.Validate
 }
 
-func NewAuthHandler(authService service.AuthService, tokenTTL time.Duration, secure bool) *AuthHandler {
+func NewAuthHandler(authService service.AuthService, throttleService service.LoginThrottleService, tokenTTL time.Duration, secure bool) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		tokenTTL:    tokenTTL,
-		secure:      secure,
-		validate:    validator.New(),
+		authService:     authService,
+		throttleService: throttleService,
+		tokenTTL:        tokenTTL,
+		secure:          secure,
+		validate:        validator.New(),
 	}
 }
 
@@ -41,15 +44,219 @@ func (h *AuthHandler) GetToken(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
 	}
 
-	token, err := h.authService.AuthorizeUser(c.Request().Context(), request.Username, request.Password)
+	ctx := c.Request().Context()
+	clientIP := c.RealIP()
+
+	if err := h.throttleService.Check(ctx, request.Username, clientIP); err != nil {
+		switch err {
+		case service.ErrTooManyLoginAttempts:
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "too many failed login attempts, try again later"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to authorize employee"})
+		}
+	}
+
+	response, err := h.authService.AuthorizeUser(ctx, request.Username, request.Password)
 	if err != nil {
 		switch err {
 		case service.ErrInvalidCredentials:
+			h.throttleService.RecordFailure(ctx, request.Username, clientIP)
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid username or password"})
 		default:
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to authorize employee"})
 		}
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"token": token})
+	h.throttleService.RecordSuccess(ctx, request.Username)
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandler) Register(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.RegisterRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	response, err := h.authService.Register(c.Request().Context(), request.Username, request.Password, request.InviteCode)
+	if err != nil {
+		switch err {
+		case service.ErrRegistrationNotAllowed:
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "registration is not allowed"})
+		case service.ErrEmployeeAlreadyExists:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "employee already exists"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to register employee"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+func (h *AuthHandler) RefreshToken(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.RefreshRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	response, err := h.authService.RefreshToken(c.Request().Context(), request.RefreshToken)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidRefreshToken:
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired refresh token"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to refresh token"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandler) Logout(c echo.Context) error {
+	claims, ok := c.Get("claims").(*entity.TokenClaims)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.RefreshRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	err := h.authService.Logout(c.Request().Context(), claims, request.RefreshToken)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidRefreshToken:
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired refresh token"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to log out"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "logged out successfully"})
+}
+
+func (h *AuthHandler) GetJWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+func (h *AuthHandler) UnlockEmployee(c echo.Context) error {
+	err := h.throttleService.Unlock(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		switch err {
+		case service.ErrEmployeeNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to unlock employee"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "employee unlocked successfully"})
+}
+
+func (h *AuthHandler) ChangePassword(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.ChangePasswordRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	err := h.authService.ChangePassword(c.Request().Context(), userID, c.RealIP(), request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCredentials:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid current password"})
+		case service.ErrTooManyLoginAttempts:
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "too many failed login attempts, try again later"})
+		case service.ErrEmployeeNotFound:
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to change password"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "password changed successfully"})
+}
+
+func (h *AuthHandler) IssuePasswordReset(c echo.Context) error {
+	issuerID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	response, err := h.authService.IssuePasswordReset(c.Request().Context(), issuerID, c.Param("username"))
+	if err != nil {
+		switch err {
+		case service.ErrEmployeeNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to issue password reset"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+func (h *AuthHandler) ResetPassword(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.ResetPasswordRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	err := h.authService.ResetPassword(c.Request().Context(), request.ResetToken, request.NewPassword)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidPasswordResetToken:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid or expired password reset token"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reset password"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "password reset successfully"})
 }
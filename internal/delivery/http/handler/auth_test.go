@@ -13,6 +13,7 @@ import (
 	testifyMock "github.com/stretchr/testify/mock"
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
 )
@@ -20,7 +21,8 @@ import (
 func TestGetToken(t *testing.T) {
 	e := echo.New()
 	mockAuthService := new(mock.MockAuthService)
-	handler := httphandler.NewAuthHandler(mockAuthService, time.Hour, false)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
 
 	tests := []struct {
 		name           string
@@ -36,8 +38,10 @@ func TestGetToken(t *testing.T) {
 				Password: "password123",
 			},
 			mockSetup: func() {
+				mockThrottleService.On("Check", testifyMock.Anything, "testuser", "192.0.2.1").Return(nil).Once()
 				mockAuthService.On("AuthorizeUser", testifyMock.Anything, "testuser", "password123").
-					Return("valid-token", nil)
+					Return(&dto.AuthResponse{Token: "valid-token", RefreshToken: "refresh-token", ExpiresIn: 900}, nil).Once()
+				mockThrottleService.On("RecordSuccess", testifyMock.Anything, "testuser").Once()
 			},
 			expectedStatus: http.StatusOK,
 			expectedError:  "",
@@ -66,12 +70,40 @@ func TestGetToken(t *testing.T) {
 				Password: "wrongpassword",
 			},
 			mockSetup: func() {
+				mockThrottleService.On("Check", testifyMock.Anything, "testuser", "192.0.2.1").Return(nil).Once()
 				mockAuthService.On("AuthorizeUser", testifyMock.Anything, "testuser", "wrongpassword").
-					Return("", service.ErrInvalidCredentials)
+					Return(nil, service.ErrInvalidCredentials).Once()
+				mockThrottleService.On("RecordFailure", testifyMock.Anything, "testuser", "192.0.2.1").Once()
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "invalid username or password",
 		},
+		{
+			name: "Error - Locked out",
+			requestBody: dto.AuthRequest{
+				Username: "testuser",
+				Password: "password123",
+			},
+			mockSetup: func() {
+				mockThrottleService.On("Check", testifyMock.Anything, "testuser", "192.0.2.1").
+					Return(service.ErrTooManyLoginAttempts).Once()
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedError:  "too many failed login attempts",
+		},
+		{
+			name: "Error - Throttle check failed",
+			requestBody: dto.AuthRequest{
+				Username: "testuser",
+				Password: "password123",
+			},
+			mockSetup: func() {
+				mockThrottleService.On("Check", testifyMock.Anything, "testuser", "192.0.2.1").
+					Return(service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "failed to authorize employee",
+		},
 	}
 
 	for _, tt := range tests {
@@ -100,6 +132,573 @@ func TestGetToken(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			mockAuthService.AssertExpectations(t)
+			mockThrottleService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTokenIgnoresSpoofedForwardedFor(t *testing.T) {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	// Every attempt claims a different client address, but all of them are
+	// counted against the address of the connection.
+	for _, forwardedFor := range []string{"203.0.113.1", "203.0.113.2", "198.51.100.7, 203.0.113.3"} {
+		mockThrottleService.On("Check", testifyMock.Anything, "testuser", "192.0.2.1").Return(nil).Once()
+		mockAuthService.On("AuthorizeUser", testifyMock.Anything, "testuser", "wrongpassword").
+			Return(nil, service.ErrInvalidCredentials).Once()
+		mockThrottleService.On("RecordFailure", testifyMock.Anything, "testuser", "192.0.2.1").Once()
+
+		reqBody, _ := json.Marshal(dto.AuthRequest{Username: "testuser", Password: "wrongpassword"})
+		req := httptest.NewRequest(http.MethodPost, "/api/auth", bytes.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetToken(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+
+	mockAuthService.AssertExpectations(t)
+	mockThrottleService.AssertExpectations(t)
+}
+
+func TestRefreshToken(t *testing.T) {
+	e := echo.New()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Token refreshed",
+			requestBody: `{"refreshToken":"old-refresh"}`,
+			mockSetup: func() {
+				mockAuthService.On("RefreshToken", testifyMock.Anything, "old-refresh").
+					Return(&dto.AuthResponse{Token: "new-token", RefreshToken: "new-refresh", ExpiresIn: 900}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"token":"new-token","refreshToken":"new-refresh","expiresIn":900}`,
+		},
+		{
+			name:           "Error - Missing refresh token",
+			requestBody:    `{}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Invalid refresh token",
+			requestBody: `{"refreshToken":"old-refresh"}`,
+			mockSetup: func() {
+				mockAuthService.On("RefreshToken", testifyMock.Anything, "old-refresh").
+					Return(nil, service.ErrInvalidRefreshToken).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"invalid or expired refresh token"}`,
+		},
+		{
+			name:        "Error - Internal Server Error",
+			requestBody: `{"refreshToken":"old-refresh"}`,
+			mockSetup: func() {
+				mockAuthService.On("RefreshToken", testifyMock.Anything, "old-refresh").
+					Return(nil, service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to refresh token"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewReader([]byte(tt.requestBody)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.RefreshToken(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	e := echo.New()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	claims := &entity.TokenClaims{UserID: 1, Role: entity.RoleEmployee, JTI: "token-id"}
+
+	tests := []struct {
+		name           string
+		claims         interface{}
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Logged out",
+			claims:      claims,
+			requestBody: `{"refreshToken":"refresh-token"}`,
+			mockSetup: func() {
+				mockAuthService.On("Logout", testifyMock.Anything, claims, "refresh-token").
+					Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"logged out successfully"}`,
+		},
+		{
+			name:           "Error - Unauthorized",
+			claims:         nil,
+			requestBody:    `{"refreshToken":"refresh-token"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}`,
+		},
+		{
+			name:           "Error - Missing refresh token",
+			claims:         claims,
+			requestBody:    `{}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Unknown refresh token",
+			claims:      claims,
+			requestBody: `{"refreshToken":"refresh-token"}`,
+			mockSetup: func() {
+				mockAuthService.On("Logout", testifyMock.Anything, claims, "refresh-token").
+					Return(service.ErrInvalidRefreshToken).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"invalid or expired refresh token"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", bytes.NewReader([]byte(tt.requestBody)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.claims != nil {
+				c.Set("claims", tt.claims)
+			}
+
+			err := handler.Logout(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetJWKS(t *testing.T) {
+	e := echo.New()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	mockAuthService.On("JWKS").Return(dto.JWKS{Keys: []dto.JWK{
+		{Kty: "OKP", Kid: "ed-1", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "x-value"},
+	}}).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.GetJWKS(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"keys":[{"kty":"OKP","kid":"ed-1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"x-value"}]}`, rec.Body.String())
+
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestRegister(t *testing.T) {
+	e := echo.New()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Employee registered",
+			requestBody: `{"username":"bob","password":"password","inviteCode":"welcome"}`,
+			mockSetup: func() {
+				mockAuthService.On("Register", testifyMock.Anything, "bob", "password", "welcome").
+					Return(&dto.AuthResponse{Token: "token", RefreshToken: "refresh", ExpiresIn: 900}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"token":"token","refreshToken":"refresh","expiresIn":900}`,
+		},
+		{
+			name:           "Error - Short password",
+			requestBody:    `{"username":"bob","password":"123"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Registration not allowed",
+			requestBody: `{"username":"bob","password":"password"}`,
+			mockSetup: func() {
+				mockAuthService.On("Register", testifyMock.Anything, "bob", "password", "").
+					Return(nil, service.ErrRegistrationNotAllowed).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"registration is not allowed"}`,
+		},
+		{
+			name:        "Error - Username taken",
+			requestBody: `{"username":"bob","password":"password"}`,
+			mockSetup: func() {
+				mockAuthService.On("Register", testifyMock.Anything, "bob", "password", "").
+					Return(nil, service.ErrEmployeeAlreadyExists).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"employee already exists"}`,
+		},
+		{
+			name:        "Error - Internal Server Error",
+			requestBody: `{"username":"bob","password":"password"}`,
+			mockSetup: func() {
+				mockAuthService.On("Register", testifyMock.Anything, "bob", "password", "").
+					Return(nil, service.ErrEmployeeCreationFailed).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to register employee"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/register", bytes.NewReader([]byte(tt.requestBody)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.Register(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnlockEmployee(t *testing.T) {
+	e := echo.New()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	tests := []struct {
+		name           string
+		username       string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "Success - Employee unlocked",
+			username: "bob",
+			mockSetup: func() {
+				mockThrottleService.On("Unlock", testifyMock.Anything, "bob").Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"employee unlocked successfully"}`,
+		},
+		{
+			name:     "Error - Employee not found",
+			username: "ghost",
+			mockSetup: func() {
+				mockThrottleService.On("Unlock", testifyMock.Anything, "ghost").Return(service.ErrEmployeeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"employee not found"}`,
+		},
+		{
+			name:     "Error - Internal Server Error",
+			username: "bob",
+			mockSetup: func() {
+				mockThrottleService.On("Unlock", testifyMock.Anything, "bob").Return(service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to unlock employee"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/admin/employees/:username/unlock")
+			c.SetParamNames("username")
+			c.SetParamValues(tt.username)
+
+			err := handler.UnlockEmployee(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockThrottleService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	e := echo.New()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Password changed",
+			requestBody: `{"currentPassword":"old-password","newPassword":"new-password"}`,
+			mockSetup: func() {
+				mockAuthService.On("ChangePassword", testifyMock.Anything, 1, "192.0.2.1", "old-password", "new-password").Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"password changed successfully"}`,
+		},
+		{
+			name:           "Error - Short new password",
+			requestBody:    `{"currentPassword":"old-password","newPassword":"123"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Wrong current password",
+			requestBody: `{"currentPassword":"wrong","newPassword":"new-password"}`,
+			mockSetup: func() {
+				mockAuthService.On("ChangePassword", testifyMock.Anything, 1, "192.0.2.1", "wrong", "new-password").
+					Return(service.ErrInvalidCredentials).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid current password"}`,
+		},
+		{
+			name:        "Error - Too many failed attempts",
+			requestBody: `{"currentPassword":"wrong","newPassword":"new-password"}`,
+			mockSetup: func() {
+				mockAuthService.On("ChangePassword", testifyMock.Anything, 1, "192.0.2.1", "wrong", "new-password").
+					Return(service.ErrTooManyLoginAttempts).Once()
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   `{"error":"too many failed login attempts, try again later"}`,
+		},
+		{
+			name:        "Error - Internal Server Error",
+			requestBody: `{"currentPassword":"old-password","newPassword":"new-password"}`,
+			mockSetup: func() {
+				mockAuthService.On("ChangePassword", testifyMock.Anything, 1, "192.0.2.1", "old-password", "new-password").
+					Return(service.ErrPasswordUpdateFailed).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to change password"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/password", bytes.NewReader([]byte(tt.requestBody)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+
+			err := handler.ChangePassword(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssuePasswordReset(t *testing.T) {
+	e := echo.New()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	tests := []struct {
+		name           string
+		username       string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "Success - Reset token issued",
+			username: "bob",
+			mockSetup: func() {
+				mockAuthService.On("IssuePasswordReset", testifyMock.Anything, 1, "bob").
+					Return(&dto.PasswordResetResponse{ResetToken: "reset-token", ExpiresAt: "2024-06-01T12:00:00Z"}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"resetToken":"reset-token","expiresAt":"2024-06-01T12:00:00Z"}`,
+		},
+		{
+			name:     "Error - Employee not found",
+			username: "ghost",
+			mockSetup: func() {
+				mockAuthService.On("IssuePasswordReset", testifyMock.Anything, 1, "ghost").
+					Return(nil, service.ErrEmployeeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"employee not found"}`,
+		},
+		{
+			name:     "Error - Internal Server Error",
+			username: "bob",
+			mockSetup: func() {
+				mockAuthService.On("IssuePasswordReset", testifyMock.Anything, 1, "bob").
+					Return(nil, service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to issue password reset"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/admin/employees/:username/password-reset")
+			c.SetParamNames("username")
+			c.SetParamValues(tt.username)
+			c.Set("userID", 1)
+
+			err := handler.IssuePasswordReset(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	e := echo.New()
+	mockAuthService := new(mock.MockAuthService)
+	mockThrottleService := new(mock.MockLoginThrottleService)
+	handler := httphandler.NewAuthHandler(mockAuthService, mockThrottleService, time.Hour, false)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Password reset",
+			requestBody: `{"resetToken":"reset-token","newPassword":"new-password"}`,
+			mockSetup: func() {
+				mockAuthService.On("ResetPassword", testifyMock.Anything, "reset-token", "new-password").Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"password reset successfully"}`,
+		},
+		{
+			name:           "Error - Missing token",
+			requestBody:    `{"newPassword":"new-password"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Invalid token",
+			requestBody: `{"resetToken":"used-token","newPassword":"new-password"}`,
+			mockSetup: func() {
+				mockAuthService.On("ResetPassword", testifyMock.Anything, "used-token", "new-password").
+					Return(service.ErrInvalidPasswordResetToken).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid or expired password reset token"}`,
+		},
+		{
+			name:        "Error - Internal Server Error",
+			requestBody: `{"resetToken":"reset-token","newPassword":"new-password"}`,
+			mockSetup: func() {
+				mockAuthService.On("ResetPassword", testifyMock.Anything, "reset-token", "new-password").
+					Return(service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to reset password"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/password/reset", bytes.NewReader([]byte(tt.requestBody)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.ResetPassword(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockAuthService.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,172 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+type MerchCatalogHandler struct {
+	catalogService service.MerchCatalogService
+	validate       *validator.Validate
+}
+
+func NewMerchCatalogHandler(catalogService service.MerchCatalogService) *MerchCatalogHandler {
+	return &MerchCatalogHandler{
+		catalogService: catalogService,
+		validate:       validator.New(),
+	}
+}
+
+func (h *MerchCatalogHandler) ListItems(c echo.Context) error {
+	items, err := h.catalogService.ListItems(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to fetch merch"})
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *MerchCatalogHandler) GetItem(c echo.Context) error {
+	item, err := h.catalogService.GetItem(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		switch err {
+		case service.ErrMerchNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "merch not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to fetch merch"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *MerchCatalogHandler) CreateItem(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.CreateMerchRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	item, err := h.catalogService.CreateItem(c.Request().Context(), request.Name, *request.Price, request.Stock, request.Category)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidMerchCategory:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merch category"})
+		case service.ErrMerchAlreadyExists:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "merch already exists"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create merch"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, item)
+}
+
+func (h *MerchCatalogHandler) UpdatePrice(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.UpdateMerchPriceRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	item, err := h.catalogService.UpdatePrice(c.Request().Context(), c.Param("name"), *request.Price)
+	if err != nil {
+		switch err {
+		case service.ErrMerchNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "merch not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update merch"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *MerchCatalogHandler) SetCategory(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.SetMerchCategoryRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	err := h.catalogService.SetCategory(c.Request().Context(), c.Param("name"), request.Category)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidMerchCategory:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid merch category"})
+		case service.ErrMerchNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "merch not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update merch"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "category updated successfully"})
+}
+
+func (h *MerchCatalogHandler) RetireItem(c echo.Context) error {
+	err := h.catalogService.RetireItem(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		switch err {
+		case service.ErrMerchNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "merch not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to retire merch"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "merch retired successfully"})
+}
+
+func (h *MerchCatalogHandler) Restock(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.RestockMerchRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	item, err := h.catalogService.Restock(c.Request().Context(), c.Param("name"), request.Quantity)
+	if err != nil {
+		switch err {
+		case service.ErrMerchNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "merch not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to restock merch"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, item)
+}
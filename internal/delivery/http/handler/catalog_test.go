@@ -0,0 +1,397 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestListMerch(t *testing.T) {
+	e := echo.New()
+	mockCatalogService := new(mock.MockMerchCatalogService)
+	handler := httphandler.NewMerchCatalogHandler(mockCatalogService)
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success - Catalog returned",
+			mockSetup: func() {
+				mockCatalogService.On("ListItems", testifyMock.Anything).
+					Return([]dto.MerchItem{{Name: "book", Price: 50}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"book","price":50}]`,
+		},
+		{
+			name: "Error - Internal Server Error",
+			mockSetup: func() {
+				mockCatalogService.On("ListItems", testifyMock.Anything).
+					Return(nil, errors.New("unexpected error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to fetch merch"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/merch", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.ListItems(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockCatalogService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetMerch(t *testing.T) {
+	e := echo.New()
+	mockCatalogService := new(mock.MockMerchCatalogService)
+	handler := httphandler.NewMerchCatalogHandler(mockCatalogService)
+
+	tests := []struct {
+		name           string
+		itemName       string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "Success - Item returned",
+			itemName: "book",
+			mockSetup: func() {
+				mockCatalogService.On("GetItem", testifyMock.Anything, "book").
+					Return(&dto.MerchItem{Name: "book", Price: 50}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"book","price":50}`,
+		},
+		{
+			name:     "Error - Item not found",
+			itemName: "yacht",
+			mockSetup: func() {
+				mockCatalogService.On("GetItem", testifyMock.Anything, "yacht").
+					Return(nil, service.ErrMerchNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"merch not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/merch/"+tt.itemName, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues(tt.itemName)
+
+			err := handler.GetItem(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockCatalogService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateMerch(t *testing.T) {
+	e := echo.New()
+	mockCatalogService := new(mock.MockMerchCatalogService)
+	handler := httphandler.NewMerchCatalogHandler(mockCatalogService)
+
+	stock := 0
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Item created",
+			requestBody: `{"name":"sticker","price":0}`,
+			mockSetup: func() {
+				mockCatalogService.On("CreateItem", testifyMock.Anything, "sticker", 0, (*int)(nil), "").
+					Return(&dto.MerchItem{Name: "sticker", Price: 0}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"name":"sticker","price":0}`,
+		},
+		{
+			name:        "Success - Item created with stock",
+			requestBody: `{"name":"hoodie","price":300,"stock":0}`,
+			mockSetup: func() {
+				mockCatalogService.On("CreateItem", testifyMock.Anything, "hoodie", 300, &stock, "").
+					Return(&dto.MerchItem{Name: "hoodie", Price: 300, Stock: &stock}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"name":"hoodie","price":300,"stock":0}`,
+		},
+		{
+			name:        "Success - Item created in category",
+			requestBody: `{"name":"sticker","price":5,"category":"stationery"}`,
+			mockSetup: func() {
+				mockCatalogService.On("CreateItem", testifyMock.Anything, "sticker", 5, (*int)(nil), "stationery").
+					Return(&dto.MerchItem{Name: "sticker", Price: 5, Category: "stationery"}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"name":"sticker","price":5,"category":"stationery"}`,
+		},
+		{
+			name:           "Error - Negative stock",
+			requestBody:    `{"name":"hoodie","price":300,"stock":-1}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:           "Error - Missing price",
+			requestBody:    `{"name":"sticker"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Item already exists",
+			requestBody: `{"name":"book","price":10}`,
+			mockSetup: func() {
+				mockCatalogService.On("CreateItem", testifyMock.Anything, "book", 10, (*int)(nil), "").
+					Return(nil, service.ErrMerchAlreadyExists).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"merch already exists"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/merch", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.CreateItem(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockCatalogService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateMerchPrice(t *testing.T) {
+	e := echo.New()
+	mockCatalogService := new(mock.MockMerchCatalogService)
+	handler := httphandler.NewMerchCatalogHandler(mockCatalogService)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Price updated",
+			requestBody: `{"price":70}`,
+			mockSetup: func() {
+				mockCatalogService.On("UpdatePrice", testifyMock.Anything, "book", 70).
+					Return(&dto.MerchItem{Name: "book", Price: 70}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"book","price":70}`,
+		},
+		{
+			name:           "Error - Negative price",
+			requestBody:    `{"price":-1}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Item not found",
+			requestBody: `{"price":70}`,
+			mockSetup: func() {
+				mockCatalogService.On("UpdatePrice", testifyMock.Anything, "book", 70).
+					Return(nil, service.ErrMerchNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"merch not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/admin/merch/book", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues("book")
+
+			err := handler.UpdatePrice(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockCatalogService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetireMerch(t *testing.T) {
+	e := echo.New()
+	mockCatalogService := new(mock.MockMerchCatalogService)
+	handler := httphandler.NewMerchCatalogHandler(mockCatalogService)
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success - Item retired",
+			mockSetup: func() {
+				mockCatalogService.On("RetireItem", testifyMock.Anything, "book").
+					Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"merch retired successfully"}`,
+		},
+		{
+			name: "Error - Item not found",
+			mockSetup: func() {
+				mockCatalogService.On("RetireItem", testifyMock.Anything, "book").
+					Return(service.ErrMerchNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"merch not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/admin/merch/book", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues("book")
+
+			err := handler.RetireItem(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockCatalogService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestockMerch(t *testing.T) {
+	e := echo.New()
+	mockCatalogService := new(mock.MockMerchCatalogService)
+	handler := httphandler.NewMerchCatalogHandler(mockCatalogService)
+
+	stock := 25
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Item restocked",
+			requestBody: `{"quantity":10}`,
+			mockSetup: func() {
+				mockCatalogService.On("Restock", testifyMock.Anything, "hoodie", 10).
+					Return(&dto.MerchItem{Name: "hoodie", Price: 300, Stock: &stock}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"hoodie","price":300,"stock":25}`,
+		},
+		{
+			name:           "Error - Zero quantity",
+			requestBody:    `{"quantity":0}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Item not found",
+			requestBody: `{"quantity":10}`,
+			mockSetup: func() {
+				mockCatalogService.On("Restock", testifyMock.Anything, "hoodie", 10).
+					Return(nil, service.ErrMerchNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"merch not found"}`,
+		},
+		{
+			name:        "Error - Internal Server Error",
+			requestBody: `{"quantity":10}`,
+			mockSetup: func() {
+				mockCatalogService.On("Restock", testifyMock.Anything, "hoodie", 10).
+					Return(nil, errors.New("unexpected error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to restock merch"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/merch/hoodie/restock", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues("hoodie")
+
+			err := handler.Restock(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockCatalogService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package httphandler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+type DisputeHandler struct {
+	disputeService service.DisputeService
+	validate       *validator.Validate
+}
+
+func NewDisputeHandler(disputeService service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+		validate:       validator.New(),
+	}
+}
+
+func (h *DisputeHandler) OpenDispute(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	transactionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid transaction id"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.OpenDisputeRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	dispute, err := h.disputeService.Open(c.Request().Context(), userID, transactionID, request.Reason)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidDispute:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+		case service.ErrTransferNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "transaction not found"})
+		case service.ErrTransferNotDisputable:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "transaction cannot be disputed"})
+		case service.ErrDisputeAlreadyOpen:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "transaction is already disputed"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to open dispute"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, dispute)
+}
+
+func (h *DisputeHandler) ListDisputes(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	disputes, err := h.disputeService.List(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list disputes"})
+	}
+
+	return c.JSON(http.StatusOK, disputes)
+}
+
+func (h *DisputeHandler) ListAllDisputes(c echo.Context) error {
+	var request dto.ListDisputesRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid query parameters"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	disputes, err := h.disputeService.ListAll(c.Request().Context(), request.Status)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidDispute:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list disputes"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, disputes)
+}
+
+func (h *DisputeHandler) ApproveDispute(c echo.Context) error {
+	return h.resolve(c, func(ctx context.Context, adminID, disputeID int, request dto.ResolveDisputeRequest) (*dto.Dispute, error) {
+		return h.disputeService.Approve(ctx, adminID, disputeID, request.Note, request.Force)
+	})
+}
+
+func (h *DisputeHandler) RejectDispute(c echo.Context) error {
+	return h.resolve(c, func(ctx context.Context, adminID, disputeID int, request dto.ResolveDisputeRequest) (*dto.Dispute, error) {
+		return h.disputeService.Reject(ctx, adminID, disputeID, request.Note)
+	})
+}
+
+// resolve handles approve and reject calls. The JSON body is optional.
+func (h *DisputeHandler) resolve(c echo.Context, resolve func(ctx context.Context, adminID, disputeID int, request dto.ResolveDisputeRequest) (*dto.Dispute, error)) error {
+	adminID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	disputeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid dispute id"})
+	}
+
+	var request dto.ResolveDisputeRequest
+	if c.Request().ContentLength != 0 {
+		if c.Request().Header.Get("Content-Type") != "application/json" {
+			return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+		}
+
+		if err := c.Bind(&request); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+		}
+
+		if err := h.validate.Struct(request); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+		}
+	}
+
+	dispute, err := resolve(c.Request().Context(), adminID, disputeID, request)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidDispute:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+		case service.ErrDisputeNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "dispute not found"})
+		case service.ErrDisputeAlreadyResolved:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "dispute is already resolved"})
+		case service.ErrInsufficientFunds:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "recipient has already spent the coins, approve with force to claw them back"})
+		case service.ErrConcurrentUpdate:
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "too many concurrent transfers, try again"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to resolve dispute"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, dispute)
+}
@@ -0,0 +1,199 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestOpenDispute(t *testing.T) {
+	e := echo.New()
+	mockDisputeService := new(mock.MockDisputeService)
+	disputeHandler := httphandler.NewDisputeHandler(mockDisputeService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		transactionID  string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:          "Success - Dispute opened",
+			transactionID: "7",
+			requestBody:   `{"reason":"wrong bob"}`,
+			mockSetup: func() {
+				mockDisputeService.On("Open", testifyMock.Anything, 1, 7, "wrong bob").Return(&dto.Dispute{
+					ID: 4, TransactionID: 7, FromUser: "alice", ToUser: "bob", Amount: 500,
+					Reason: "wrong bob", Status: entity.DisputeOpen, CreatedAt: createdAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":4,"transactionId":7,"fromUser":"alice","toUser":"bob","amount":500,` +
+				`"reason":"wrong bob","status":"open","createdAt":"2024-06-03T12:00:00Z"}`,
+		},
+		{
+			name:           "Error - Missing reason",
+			transactionID:  "7",
+			requestBody:    `{}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:          "Error - Transaction not found",
+			transactionID: "8",
+			requestBody:   `{"reason":"wrong bob"}`,
+			mockSetup: func() {
+				mockDisputeService.On("Open", testifyMock.Anything, 1, 8, "wrong bob").Return(nil, service.ErrTransferNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"transaction not found"}`,
+		},
+		{
+			name:          "Error - Already disputed",
+			transactionID: "7",
+			requestBody:   `{"reason":"wrong bob"}`,
+			mockSetup: func() {
+				mockDisputeService.On("Open", testifyMock.Anything, 1, 7, "wrong bob").Return(nil, service.ErrDisputeAlreadyOpen).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"transaction is already disputed"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/transactions/"+tt.transactionID+"/disputes", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.transactionID)
+
+			err := disputeHandler.OpenDispute(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockDisputeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApproveDispute(t *testing.T) {
+	e := echo.New()
+	mockDisputeService := new(mock.MockDisputeService)
+	disputeHandler := httphandler.NewDisputeHandler(mockDisputeService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	resolvedAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Forced clawback",
+			requestBody: `{"note":"sent by mistake","force":true}`,
+			mockSetup: func() {
+				mockDisputeService.On("Approve", testifyMock.Anything, 10, 4, "sent by mistake", true).Return(&dto.Dispute{
+					ID: 4, TransactionID: 7, FromUser: "alice", ToUser: "bob", Amount: 500, Reason: "wrong bob",
+					Status: entity.DisputeApproved, ResolvedBy: "admin", Note: "sent by mistake", ReversalID: 9, Clawback: true,
+					CreatedAt: createdAt, ResolvedAt: &resolvedAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":4,"transactionId":7,"fromUser":"alice","toUser":"bob","amount":500,"reason":"wrong bob",` +
+				`"status":"approved","resolvedBy":"admin","note":"sent by mistake","reversalId":9,"clawback":true,` +
+				`"createdAt":"2024-06-03T12:00:00Z","resolvedAt":"2024-06-03T13:00:00Z"}`,
+		},
+		{
+			name:        "Error - Coins already spent",
+			requestBody: "",
+			mockSetup: func() {
+				mockDisputeService.On("Approve", testifyMock.Anything, 10, 4, "", false).Return(nil, service.ErrInsufficientFunds).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"recipient has already spent the coins, approve with force to claw them back"}`,
+		},
+		{
+			name:        "Error - Already resolved",
+			requestBody: "",
+			mockSetup: func() {
+				mockDisputeService.On("Approve", testifyMock.Anything, 10, 4, "", false).Return(nil, service.ErrDisputeAlreadyResolved).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"dispute is already resolved"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/disputes/4/approve", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 10)
+			c.SetParamNames("id")
+			c.SetParamValues("4")
+
+			err := disputeHandler.ApproveDispute(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockDisputeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListAllDisputes(t *testing.T) {
+	e := echo.New()
+	mockDisputeService := new(mock.MockDisputeService)
+	disputeHandler := httphandler.NewDisputeHandler(mockDisputeService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/disputes?status=pending", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := disputeHandler.ListAllDisputes(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error":"invalid request data"}`, rec.Body.String())
+
+	mockDisputeService.On("ListAll", testifyMock.Anything, "open").Return([]dto.Dispute{}, nil).Once()
+
+	req = httptest.NewRequest(http.MethodGet, "/api/admin/disputes?status=open", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+
+	err = disputeHandler.ListAllDisputes(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+
+	mockDisputeService.AssertExpectations(t)
+}
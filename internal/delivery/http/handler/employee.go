@@ -3,17 +3,22 @@ package httphandler
 import (
 	"net/http"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
 type EmployeeHandler struct {
 	employeeService service.EmployeeService
+	validate        *validator.Validate
 }
 
 func NewEmployeeHandler(employeeService service.EmployeeService) *EmployeeHandler {
 	return &EmployeeHandler{
 		employeeService: employeeService,
+		validate:        validator.New(),
 	}
 }
 
@@ -30,3 +35,61 @@ func (h *EmployeeHandler) GetEmployeeInfo(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, employeeInfo)
 }
+
+func (h *EmployeeHandler) SetRole(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.SetRoleRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	err := h.employeeService.SetRole(c.Request().Context(), c.Param("username"), request.Role)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidRole:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid role"})
+		case service.ErrEmployeeNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to set role"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "role updated successfully"})
+}
+
+func (h *EmployeeHandler) SetOffice(c echo.Context) error {
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.SetOfficeRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	err := h.employeeService.SetOffice(c.Request().Context(), c.Param("username"), request.Office)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidOffice:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid office"})
+		case service.ErrEmployeeNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to set office"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "office updated successfully"})
+}
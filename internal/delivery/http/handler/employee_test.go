@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -12,6 +13,7 @@ import (
 	testifyMock "github.com/stretchr/testify/mock"
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	httphandler "github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
 	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
 )
 
@@ -35,7 +37,7 @@ func TestGetEmployeeInfo(t *testing.T) {
 					Return(&dto.EmployeeInfoResponse{
 						Coins: 100,
 						Inventory: []*dto.InventoryItem{
-							{Type: "book", Quantity: 1},
+							{Type: "book", Quantity: 1, Spent: 45},
 						},
 						CoinHistory: &dto.CoinHistory{
 							Received: []dto.CoinTransaction{
@@ -51,7 +53,7 @@ func TestGetEmployeeInfo(t *testing.T) {
 			expectedBody: map[string]interface{}{
 				"coins": float64(100),
 				"inventory": []interface{}{
-					map[string]interface{}{"type": "book", "quantity": float64(1)},
+					map[string]interface{}{"type": "book", "quantity": float64(1), "spent": float64(45)},
 				},
 				"coinHistory": map[string]interface{}{
 					"received": []interface{}{
@@ -105,3 +107,140 @@ func TestGetEmployeeInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestSetRole(t *testing.T) {
+	e := echo.New()
+	mockEmployeeService := new(mock.MockEmployeeService)
+	employeeHandler := httphandler.NewEmployeeHandler(mockEmployeeService)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Role updated",
+			requestBody: `{"role":"admin"}`,
+			mockSetup: func() {
+				mockEmployeeService.On("SetRole", testifyMock.Anything, "alice", "admin").
+					Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"role updated successfully"}`,
+		},
+		{
+			name:           "Error - Missing role",
+			requestBody:    `{}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Invalid role",
+			requestBody: `{"role":"overlord"}`,
+			mockSetup: func() {
+				mockEmployeeService.On("SetRole", testifyMock.Anything, "alice", "overlord").
+					Return(service.ErrInvalidRole).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid role"}`,
+		},
+		{
+			name:        "Error - Employee not found",
+			requestBody: `{"role":"hr"}`,
+			mockSetup: func() {
+				mockEmployeeService.On("SetRole", testifyMock.Anything, "alice", "hr").
+					Return(service.ErrEmployeeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"employee not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/employees/alice/role", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("username")
+			c.SetParamValues("alice")
+
+			err := employeeHandler.SetRole(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockEmployeeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetOffice(t *testing.T) {
+	e := echo.New()
+	mockEmployeeService := new(mock.MockEmployeeService)
+	employeeHandler := httphandler.NewEmployeeHandler(mockEmployeeService)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Office updated",
+			requestBody: `{"office":"msk"}`,
+			mockSetup: func() {
+				mockEmployeeService.On("SetOffice", testifyMock.Anything, "alice", "msk").
+					Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"office updated successfully"}`,
+		},
+		{
+			name:        "Error - Blank office",
+			requestBody: `{"office":"   "}`,
+			mockSetup: func() {
+				mockEmployeeService.On("SetOffice", testifyMock.Anything, "alice", "   ").
+					Return(service.ErrInvalidOffice).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid office"}`,
+		},
+		{
+			name:        "Error - Employee not found",
+			requestBody: `{"office":"spb"}`,
+			mockSetup: func() {
+				mockEmployeeService.On("SetOffice", testifyMock.Anything, "alice", "spb").
+					Return(service.ErrEmployeeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"employee not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/employees/alice/office", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("username")
+			c.SetParamValues("alice")
+
+			err := employeeHandler.SetOffice(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockEmployeeService.AssertExpectations(t)
+		})
+	}
+}
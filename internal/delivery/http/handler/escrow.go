@@ -0,0 +1,121 @@
+package httphandler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+type EscrowHandler struct {
+	escrowService service.EscrowService
+	validate      *validator.Validate
+}
+
+func NewEscrowHandler(escrowService service.EscrowService) *EscrowHandler {
+	return &EscrowHandler{
+		escrowService: escrowService,
+		validate:      validator.New(),
+	}
+}
+
+func (h *EscrowHandler) HoldCoins(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.SendCoinRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	note := entity.TransferNote{Message: request.Message, Category: request.Category}
+	transfer, err := h.escrowService.Hold(c.Request().Context(), userID, request.ToUser, request.Amount, note)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidMessage:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "message is too long"})
+		case service.ErrInvalidCategory:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid category"})
+		case service.ErrEmployeeNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
+		case service.ErrInsufficientFunds:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "insufficient funds"})
+		case service.ErrSelfTransaction:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "sender and recipient cannot be the same user"})
+		case service.ErrConcurrentUpdate:
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "too many concurrent transfers, try again"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to send coins"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, transfer)
+}
+
+func (h *EscrowHandler) ListPending(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	transfers, err := h.escrowService.ListPending(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list pending transfers"})
+	}
+
+	return c.JSON(http.StatusOK, transfers)
+}
+
+func (h *EscrowHandler) AcceptTransfer(c echo.Context) error {
+	return h.resolve(c, h.escrowService.Accept)
+}
+
+func (h *EscrowHandler) DeclineTransfer(c echo.Context) error {
+	return h.resolve(c, h.escrowService.Decline)
+}
+
+func (h *EscrowHandler) resolve(c echo.Context, resolve func(ctx context.Context, userID, transferID int) (*dto.PendingTransfer, error)) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	transferID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid transfer id"})
+	}
+
+	transfer, err := resolve(c.Request().Context(), userID, transferID)
+	if err != nil {
+		switch err {
+		case service.ErrPendingTransferNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "pending transfer not found"})
+		case service.ErrPendingTransferResolved:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "pending transfer is already resolved"})
+		case service.ErrPendingTransferExpired:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "pending transfer has expired"})
+		case service.ErrConcurrentUpdate:
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "too many concurrent transfers, try again"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to resolve pending transfer"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, transfer)
+}
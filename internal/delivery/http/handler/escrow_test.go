@@ -0,0 +1,223 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestHoldCoins(t *testing.T) {
+	e := echo.New()
+	mockEscrowService := new(mock.MockEscrowService)
+	escrowHandler := httphandler.NewEscrowHandler(mockEscrowService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(72 * time.Hour)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Coins held",
+			requestBody: `{"toUser":"bob","amount":500,"category":"bonus"}`,
+			mockSetup: func() {
+				mockEscrowService.On("Hold", testifyMock.Anything, 1, "bob", 500, entity.TransferNote{Category: "bonus"}).
+					Return(&dto.PendingTransfer{
+						ID: 3, FromUser: "alice", ToUser: "bob", Amount: 500, Category: "bonus",
+						Status: entity.PendingTransferPending, ExpiresAt: expiresAt, CreatedAt: createdAt,
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":3,"fromUser":"alice","toUser":"bob","amount":500,"category":"bonus","status":"pending",` +
+				`"expiresAt":"2024-06-06T12:00:00Z","createdAt":"2024-06-03T12:00:00Z"}`,
+		},
+		{
+			name:           "Error - Invalid amount",
+			requestBody:    `{"toUser":"bob","amount":0}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Insufficient funds",
+			requestBody: `{"toUser":"bob","amount":5000}`,
+			mockSetup: func() {
+				mockEscrowService.On("Hold", testifyMock.Anything, 1, "bob", 5000, entity.TransferNote{}).
+					Return(nil, service.ErrInsufficientFunds).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"insufficient funds"}`,
+		},
+		{
+			name:        "Error - Employee not found",
+			requestBody: `{"toUser":"ghost","amount":500}`,
+			mockSetup: func() {
+				mockEscrowService.On("Hold", testifyMock.Anything, 1, "ghost", 500, entity.TransferNote{}).
+					Return(nil, service.ErrEmployeeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"employee not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/transfers/pending", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+
+			err := escrowHandler.HoldCoins(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockEscrowService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListPending(t *testing.T) {
+	e := echo.New()
+	mockEscrowService := new(mock.MockEscrowService)
+	escrowHandler := httphandler.NewEscrowHandler(mockEscrowService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(72 * time.Hour)
+
+	mockEscrowService.On("ListPending", testifyMock.Anything, 2).Return(&dto.PendingTransfersResponse{
+		Incoming: []dto.PendingTransfer{{
+			ID: 3, FromUser: "alice", ToUser: "bob", Amount: 500,
+			Status: entity.PendingTransferPending, ExpiresAt: expiresAt, CreatedAt: createdAt,
+		}},
+		Outgoing: []dto.PendingTransfer{},
+	}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/transfers/pending", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("userID", 2)
+
+	err := escrowHandler.ListPending(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"incoming":[{"id":3,"fromUser":"alice","toUser":"bob","amount":500,"status":"pending",`+
+		`"expiresAt":"2024-06-06T12:00:00Z","createdAt":"2024-06-03T12:00:00Z"}],"outgoing":[]}`, rec.Body.String())
+
+	mockEscrowService.AssertExpectations(t)
+}
+
+func TestResolvePendingTransfer(t *testing.T) {
+	e := echo.New()
+	mockEscrowService := new(mock.MockEscrowService)
+	escrowHandler := httphandler.NewEscrowHandler(mockEscrowService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(72 * time.Hour)
+	resolvedAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		accept         bool
+		transferID     string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "Success - Accepted",
+			accept:     true,
+			transferID: "3",
+			mockSetup: func() {
+				mockEscrowService.On("Accept", testifyMock.Anything, 2, 3).Return(&dto.PendingTransfer{
+					ID: 3, FromUser: "alice", ToUser: "bob", Amount: 500, Status: entity.PendingTransferAccepted,
+					ExpiresAt: expiresAt, CreatedAt: createdAt, ResolvedAt: &resolvedAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":3,"fromUser":"alice","toUser":"bob","amount":500,"status":"accepted",` +
+				`"expiresAt":"2024-06-06T12:00:00Z","createdAt":"2024-06-03T12:00:00Z","resolvedAt":"2024-06-03T13:00:00Z"}`,
+		},
+		{
+			name:       "Error - Declined twice",
+			accept:     false,
+			transferID: "3",
+			mockSetup: func() {
+				mockEscrowService.On("Decline", testifyMock.Anything, 2, 3).Return(nil, service.ErrPendingTransferResolved).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"pending transfer is already resolved"}`,
+		},
+		{
+			name:       "Error - Expired",
+			accept:     true,
+			transferID: "3",
+			mockSetup: func() {
+				mockEscrowService.On("Accept", testifyMock.Anything, 2, 3).Return(nil, service.ErrPendingTransferExpired).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"pending transfer has expired"}`,
+		},
+		{
+			name:       "Error - Not found",
+			accept:     true,
+			transferID: "3",
+			mockSetup: func() {
+				mockEscrowService.On("Accept", testifyMock.Anything, 2, 3).Return(nil, service.ErrPendingTransferNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"pending transfer not found"}`,
+		},
+		{
+			name:           "Error - Invalid id",
+			accept:         true,
+			transferID:     "abc",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid transfer id"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			action, resolve := "decline", escrowHandler.DeclineTransfer
+			if tt.accept {
+				action, resolve = "accept", escrowHandler.AcceptTransfer
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/transfers/pending/"+tt.transferID+"/"+action, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 2)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.transferID)
+
+			err := resolve(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockEscrowService.AssertExpectations(t)
+		})
+	}
+}
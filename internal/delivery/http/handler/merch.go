@@ -3,17 +3,23 @@ package httphandler
 import (
 	"net/http"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
 type MerchHandler struct {
 	merchService service.MerchService
+	validate     *validator.Validate
 }
 
 func NewMerchHandler(merchService service.MerchService) *MerchHandler {
 	return &MerchHandler{
 		merchService: merchService,
+		validate:     validator.New(),
 	}
 }
 
@@ -35,6 +41,10 @@ func (h *MerchHandler) BuyItem(c echo.Context) error {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "merch not found"})
 		case service.ErrInsufficientFunds:
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "insufficient funds"})
+		case service.ErrOutOfStock:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "merch out of stock"})
+		case service.ErrConcurrentUpdate:
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "too many concurrent purchases, try again"})
 		default:
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to buy merch"})
 		}
@@ -42,3 +52,54 @@ func (h *MerchHandler) BuyItem(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "merch purchased successfully"})
 }
+
+func (h *MerchHandler) Checkout(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.CheckoutRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	cart := make([]entity.OrderLine, len(request.Items))
+	for i, line := range request.Items {
+		cart[i] = entity.OrderLine{Item: line.Item, Quantity: line.Quantity}
+	}
+
+	order, err := h.merchService.Checkout(c.Request().Context(), userID, cart, request.PromoCode)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidPromoCode:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid or expired promo code"})
+		case service.ErrPromoCodeNotApplicable:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "promo code does not apply to the order"})
+		case service.ErrPromoCodeExhausted:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "promo code usage limit reached"})
+		case service.ErrMerchNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "merch not found"})
+		case service.ErrInsufficientFunds:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "insufficient funds"})
+		case service.ErrOutOfStock:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "merch out of stock"})
+		case service.ErrConcurrentUpdate:
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "too many concurrent purchases, try again"})
+		case service.ErrInvalidCart:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid cart"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to place order"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, order)
+}
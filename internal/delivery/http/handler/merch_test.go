@@ -4,13 +4,16 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	testifyMock "github.com/stretchr/testify/mock"
 
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
 )
@@ -77,6 +80,17 @@ func TestBuyItem(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"error":"insufficient funds"}`,
 		},
+		{
+			name:     "Error - Out Of Stock",
+			userID:   1,
+			itemName: "book",
+			mockSetup: func() {
+				mockMerchService.On("BuyItem", testifyMock.Anything, 1, "book").
+					Return(service.ErrOutOfStock).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"merch out of stock"}`,
+		},
 		{
 			name:     "Error - Internal Server Error",
 			userID:   1,
@@ -113,3 +127,200 @@ func TestBuyItem(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckout(t *testing.T) {
+	e := echo.New()
+	mockMerchService := new(mock.MockMerchService)
+	handler := httphandler.NewMerchHandler(mockMerchService)
+
+	tests := []struct {
+		name           string
+		userID         interface{}
+		contentType    string
+		body           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Order Placed",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"book","quantity":2},{"item":"pen","quantity":1}]}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{
+					{Item: "book", Quantity: 2},
+					{Item: "pen", Quantity: 1},
+				}, "").Return(&dto.OrderResponse{
+					OrderID: 7,
+					Total:   110,
+					Lines: []dto.OrderLine{
+						{Item: "book", Quantity: 2, UnitPrice: 50, Total: 100},
+						{Item: "pen", Quantity: 1, UnitPrice: 10, Total: 10},
+					},
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"orderId":7,"total":110,"lines":[{"item":"book","quantity":2,"unitPrice":50,"total":100},{"item":"pen","quantity":1,"unitPrice":10,"total":10}]}`,
+		},
+		{
+			name:        "Success - Order Placed With Promo Code",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"hoody","quantity":1}],"promoCode":"summer24"}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{{Item: "hoody", Quantity: 1}}, "summer24").
+					Return(&dto.OrderResponse{
+						OrderID: 8,
+						Total:   240,
+						Lines:   []dto.OrderLine{{Item: "hoody", Quantity: 1, UnitPrice: 240, Total: 240, ListPrice: 300, Promotion: "Summer sale"}},
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"orderId":8,"total":240,"lines":[{"item":"hoody","quantity":1,"unitPrice":240,"total":240,` +
+				`"listPrice":300,"promotion":"Summer sale"}]}`,
+		},
+		{
+			name:        "Error - Unknown Promo Code",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"hoody","quantity":1}],"promoCode":"sumer24"}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{{Item: "hoody", Quantity: 1}}, "sumer24").
+					Return(nil, service.ErrInvalidPromoCode).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid or expired promo code"}`,
+		},
+		{
+			name:        "Error - Promo Code Does Not Apply",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"book","quantity":1}],"promoCode":"summer24"}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{{Item: "book", Quantity: 1}}, "summer24").
+					Return(nil, service.ErrPromoCodeNotApplicable).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"promo code does not apply to the order"}`,
+		},
+		{
+			name:        "Error - Promo Code Used Up",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"hoody","quantity":1}],"promoCode":"summer24"}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{{Item: "hoody", Quantity: 1}}, "summer24").
+					Return(nil, service.ErrPromoCodeExhausted).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"promo code usage limit reached"}`,
+		},
+		{
+			name:           "Error - Unauthorized",
+			userID:         nil,
+			contentType:    "application/json",
+			body:           `{"items":[{"item":"book","quantity":1}]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}`,
+		},
+		{
+			name:           "Error - Unsupported Content Type",
+			userID:         1,
+			contentType:    "text/plain",
+			body:           `{"items":[{"item":"book","quantity":1}]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `{"error":"Content-Type must be application/json"}`,
+		},
+		{
+			name:           "Error - Empty Cart",
+			userID:         1,
+			contentType:    "application/json",
+			body:           `{"items":[]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:           "Error - Invalid Quantity",
+			userID:         1,
+			contentType:    "application/json",
+			body:           `{"items":[{"item":"book","quantity":0}]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Merch Not Found",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"unknown","quantity":1}]}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{{Item: "unknown", Quantity: 1}}, "").
+					Return(nil, service.ErrMerchNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"merch not found"}`,
+		},
+		{
+			name:        "Error - Insufficient Funds",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"book","quantity":1}]}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{{Item: "book", Quantity: 1}}, "").
+					Return(nil, service.ErrInsufficientFunds).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"insufficient funds"}`,
+		},
+		{
+			name:        "Error - Out Of Stock",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"hoodie","quantity":5}]}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{{Item: "hoodie", Quantity: 5}}, "").
+					Return(nil, service.ErrOutOfStock).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"merch out of stock"}`,
+		},
+		{
+			name:        "Error - Internal Server Error",
+			userID:      1,
+			contentType: "application/json",
+			body:        `{"items":[{"item":"book","quantity":1}]}`,
+			mockSetup: func() {
+				mockMerchService.On("Checkout", testifyMock.Anything, 1, []entity.OrderLine{{Item: "book", Quantity: 1}}, "").
+					Return(nil, errors.New("unexpected error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to place order"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/buy", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.userID != nil {
+				c.Set("userID", tt.userID)
+			}
+
+			err := handler.Checkout(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockMerchService.AssertExpectations(t)
+		})
+	}
+}
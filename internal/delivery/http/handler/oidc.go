@@ -0,0 +1,87 @@
+package httphandler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+// oidcStateCookie ties a login to the browser that started it, so that a
+// callback with someone else's code and state is refused.
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	oidcService service.OIDCService
+}
+
+func NewOIDCHandler(oidcService service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+func (h *OIDCHandler) Login(c echo.Context) error {
+	authURL, state, err := h.oidcService.AuthCodeURL(c.Request().Context())
+	if err != nil {
+		switch err {
+		case service.ErrIdentityProviderUnavailable:
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": "identity provider unavailable"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to start login"})
+		}
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/auth/oidc",
+		Secure:   c.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+func (h *OIDCHandler) Callback(c echo.Context) error {
+	if providerError := c.QueryParam("error"); providerError != "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "login rejected by identity provider: " + providerError})
+	}
+
+	code, state := c.QueryParam("code"), c.QueryParam("state")
+	if code == "" || state == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code and state are required"})
+	}
+
+	cookie, err := c.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "login was not started in this browser"})
+	}
+	c.SetCookie(&http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     "/api/auth/oidc",
+		MaxAge:   -1,
+		Secure:   c.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	response, err := h.oidcService.Exchange(c.Request().Context(), code, state)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidLoginState:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid or expired login state"})
+		case service.ErrInvalidIDToken:
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid id token"})
+		case service.ErrIdentityProviderUnavailable:
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": "identity provider unavailable"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to authorize employee"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,197 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	e := echo.New()
+	mockOIDCService := new(mock.MockOIDCService)
+	handler := httphandler.NewOIDCHandler(mockOIDCService)
+
+	tests := []struct {
+		name             string
+		mockSetup        func()
+		expectedStatus   int
+		expectedLocation string
+		expectedCookie   string
+		expectedBody     string
+	}{
+		{
+			name: "Success - Redirect to provider",
+			mockSetup: func() {
+				mockOIDCService.On("AuthCodeURL", testifyMock.Anything).
+					Return("https://sso.example.com/authorize?state=abc", "abc", nil).Once()
+			},
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://sso.example.com/authorize?state=abc",
+			expectedCookie:   "abc",
+		},
+		{
+			name: "Error - Provider unavailable",
+			mockSetup: func() {
+				mockOIDCService.On("AuthCodeURL", testifyMock.Anything).
+					Return("", "", service.ErrIdentityProviderUnavailable).Once()
+			},
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   `{"error":"identity provider unavailable"}`,
+		},
+		{
+			name: "Error - Internal Server Error",
+			mockSetup: func() {
+				mockOIDCService.On("AuthCodeURL", testifyMock.Anything).
+					Return("", "", service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to start login"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.Login(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedLocation, rec.Header().Get("Location"))
+			if tt.expectedCookie != "" {
+				cookies := rec.Result().Cookies()
+				if assert.Len(t, cookies, 1) {
+					assert.Equal(t, "oidc_state", cookies[0].Name)
+					assert.Equal(t, tt.expectedCookie, cookies[0].Value)
+					assert.True(t, cookies[0].HttpOnly)
+					assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+				}
+			}
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+
+			mockOIDCService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOIDCCallback(t *testing.T) {
+	e := echo.New()
+	mockOIDCService := new(mock.MockOIDCService)
+	handler := httphandler.NewOIDCHandler(mockOIDCService)
+
+	tests := []struct {
+		name           string
+		query          string
+		cookie         string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success - Tokens issued",
+			query:  "?code=code&state=state",
+			cookie: "state",
+			mockSetup: func() {
+				mockOIDCService.On("Exchange", testifyMock.Anything, "code", "state").
+					Return(&dto.AuthResponse{Token: "token", RefreshToken: "refresh", ExpiresIn: 900}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"token":"token","refreshToken":"refresh","expiresIn":900}`,
+		},
+		{
+			name:           "Error - Provider error",
+			query:          "?error=access_denied&state=state",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"login rejected by identity provider: access_denied"}`,
+		},
+		{
+			name:           "Error - Missing code",
+			query:          "?state=state",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"code and state are required"}`,
+		},
+		{
+			name:   "Error - Invalid state",
+			query:  "?code=code&state=stale",
+			cookie: "stale",
+			mockSetup: func() {
+				mockOIDCService.On("Exchange", testifyMock.Anything, "code", "stale").
+					Return(nil, service.ErrInvalidLoginState).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid or expired login state"}`,
+		},
+		{
+			name:   "Error - Invalid ID token",
+			query:  "?code=code&state=state",
+			cookie: "state",
+			mockSetup: func() {
+				mockOIDCService.On("Exchange", testifyMock.Anything, "code", "state").
+					Return(nil, service.ErrInvalidIDToken).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"invalid id token"}`,
+		},
+		{
+			name:   "Error - Provider unavailable",
+			query:  "?code=code&state=state",
+			cookie: "state",
+			mockSetup: func() {
+				mockOIDCService.On("Exchange", testifyMock.Anything, "code", "state").
+					Return(nil, service.ErrIdentityProviderUnavailable).Once()
+			},
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   `{"error":"identity provider unavailable"}`,
+		},
+		{
+			name:           "Error - Missing state cookie",
+			query:          "?code=code&state=state",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"login was not started in this browser"}`,
+		},
+		{
+			name:           "Error - Mismatched state cookie",
+			query:          "?code=code&state=state",
+			cookie:         "other",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"login was not started in this browser"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/callback"+tt.query, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "oidc_state", Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.Callback(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockOIDCService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package httphandler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+type OrderHandler struct {
+	orderService service.OrderService
+	validate     *validator.Validate
+}
+
+func NewOrderHandler(orderService service.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+		validate:     validator.New(),
+	}
+}
+
+func (h *OrderHandler) ListOrders(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	orders, err := h.orderService.ListOrders(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list orders"})
+	}
+
+	return c.JSON(http.StatusOK, orders)
+}
+
+func (h *OrderHandler) ListAllOrders(c echo.Context) error {
+	var request dto.ListOrdersRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid query parameters"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	filter := entity.OrderFilter{
+		Office:           request.Office,
+		Item:             request.Item,
+		Status:           request.Status,
+		FulfilmentStatus: request.FulfilmentStatus,
+	}
+
+	orders, err := h.orderService.ListAll(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list orders"})
+	}
+
+	return c.JSON(http.StatusOK, orders)
+}
+
+func (h *OrderHandler) CancelOrder(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	return h.update(c, "order can no longer be cancelled", func(ctx context.Context, orderID int) (*dto.Order, error) {
+		return h.orderService.Cancel(ctx, userID, orderID)
+	})
+}
+
+// AdvanceOrder moves the order to its next fulfilment step. The JSON body with
+// the expected step is optional.
+func (h *OrderHandler) AdvanceOrder(c echo.Context) error {
+	var request dto.AdvanceOrderRequest
+	if c.Request().ContentLength != 0 {
+		if c.Request().Header.Get("Content-Type") != "application/json" {
+			return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+		}
+
+		if err := c.Bind(&request); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+		}
+
+		if err := h.validate.Struct(request); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+		}
+	}
+
+	return h.update(c, "order is not at the previous fulfilment step", func(ctx context.Context, orderID int) (*dto.Order, error) {
+		return h.orderService.Advance(ctx, orderID, request.Status)
+	})
+}
+
+func (h *OrderHandler) RefundOrder(c echo.Context) error {
+	return h.update(c, "order is already cancelled or refunded", h.orderService.Refund)
+}
+
+func (h *OrderHandler) update(c echo.Context, transitionError string, update func(ctx context.Context, orderID int) (*dto.Order, error)) error {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid order id"})
+	}
+
+	order, err := update(c.Request().Context(), orderID)
+	if err != nil {
+		switch err {
+		case service.ErrOrderNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "order not found"})
+		case service.ErrOrderTransition:
+			return c.JSON(http.StatusConflict, map[string]string{"error": transitionError})
+		case service.ErrCancelWindowEnded:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "order cancellation window has ended"})
+		case service.ErrConcurrentUpdate:
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "too many concurrent updates, try again"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update order"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
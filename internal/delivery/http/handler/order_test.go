@@ -0,0 +1,255 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestListOrders(t *testing.T) {
+	e := echo.New()
+	mockOrderService := new(mock.MockOrderService)
+	orderHandler := httphandler.NewOrderHandler(mockOrderService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	cancellableUntil := createdAt.Add(time.Hour)
+
+	mockOrderService.On("ListOrders", testifyMock.Anything, 1).Return([]dto.Order{{
+		ID: 5, Employee: "alice", Status: entity.OrderPlaced, FulfilmentStatus: entity.FulfilmentNew, Total: 580, Items: []dto.OrderItem{{Item: "hoody", Quantity: 2, UnitPrice: 290, Total: 580}},
+		CreatedAt: createdAt, CancellableUntil: &cancellableUntil,
+	}}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("userID", 1)
+
+	err := orderHandler.ListOrders(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"id":5,"employee":"alice","status":"placed","fulfilmentStatus":"new","total":580,"items":[{"item":"hoody","quantity":2,"unitPrice":290,"total":580}],`+
+		`"createdAt":"2024-06-03T12:00:00Z","cancellableUntil":"2024-06-03T13:00:00Z"}]`, rec.Body.String())
+
+	mockOrderService.AssertExpectations(t)
+}
+
+func TestCancelOrder(t *testing.T) {
+	e := echo.New()
+	mockOrderService := new(mock.MockOrderService)
+	orderHandler := httphandler.NewOrderHandler(mockOrderService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	cancelledAt := createdAt.Add(10 * time.Minute)
+
+	tests := []struct {
+		name           string
+		orderID        string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success - Order cancelled",
+			orderID: "5",
+			mockSetup: func() {
+				mockOrderService.On("Cancel", testifyMock.Anything, 1, 5).Return(&dto.Order{
+					ID: 5, Employee: "alice", Status: entity.OrderCancelled, FulfilmentStatus: entity.FulfilmentNew, Total: 580, Items: []dto.OrderItem{{Item: "hoody", Quantity: 2, UnitPrice: 290, Total: 580}},
+					CreatedAt: createdAt, StatusChangedAt: &cancelledAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":5,"employee":"alice","status":"cancelled","fulfilmentStatus":"new","total":580,"items":[{"item":"hoody","quantity":2,"unitPrice":290,"total":580}],` +
+				`"createdAt":"2024-06-03T12:00:00Z","statusChangedAt":"2024-06-03T12:10:00Z"}`,
+		},
+		{
+			name:    "Error - Window ended",
+			orderID: "5",
+			mockSetup: func() {
+				mockOrderService.On("Cancel", testifyMock.Anything, 1, 5).Return(nil, service.ErrCancelWindowEnded).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"order cancellation window has ended"}`,
+		},
+		{
+			name:    "Error - Already fulfilled",
+			orderID: "5",
+			mockSetup: func() {
+				mockOrderService.On("Cancel", testifyMock.Anything, 1, 5).Return(nil, service.ErrOrderTransition).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"order can no longer be cancelled"}`,
+		},
+		{
+			name:    "Error - Not found",
+			orderID: "6",
+			mockSetup: func() {
+				mockOrderService.On("Cancel", testifyMock.Anything, 1, 6).Return(nil, service.ErrOrderNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"order not found"}`,
+		},
+		{
+			name:           "Error - Invalid id",
+			orderID:        "abc",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid order id"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/orders/"+tt.orderID+"/cancel", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.orderID)
+
+			err := orderHandler.CancelOrder(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockOrderService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListAllOrders(t *testing.T) {
+	e := echo.New()
+	mockOrderService := new(mock.MockOrderService)
+	orderHandler := httphandler.NewOrderHandler(mockOrderService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	mockOrderService.On("ListAll", testifyMock.Anything, entity.OrderFilter{Office: "msk", Item: "hoody", Status: entity.OrderPlaced}).
+		Return([]dto.Order{{
+			ID: 5, Employee: "alice", Office: "msk", Status: entity.OrderPlaced, FulfilmentStatus: entity.FulfilmentPacked, Total: 580,
+			Items: []dto.OrderItem{{Item: "hoody", Quantity: 2, UnitPrice: 290, Total: 580}}, CreatedAt: createdAt,
+		}}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/orders?office=msk&item=hoody&status=placed", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := orderHandler.ListAllOrders(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"id":5,"employee":"alice","office":"msk","status":"placed","fulfilmentStatus":"packed","total":580,`+
+		`"items":[{"item":"hoody","quantity":2,"unitPrice":290,"total":580}],"createdAt":"2024-06-03T12:00:00Z"}]`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/admin/orders?fulfilment=lost", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+
+	err = orderHandler.ListAllOrders(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error":"invalid request data"}`, rec.Body.String())
+
+	mockOrderService.AssertExpectations(t)
+}
+
+func TestAdvanceOrder(t *testing.T) {
+	e := echo.New()
+	mockOrderService := new(mock.MockOrderService)
+	orderHandler := httphandler.NewOrderHandler(mockOrderService)
+
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	packedAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Next step",
+			requestBody: "",
+			mockSetup: func() {
+				mockOrderService.On("Advance", testifyMock.Anything, 5, "").Return(&dto.Order{
+					ID: 5, Employee: "alice", Status: entity.OrderPlaced, FulfilmentStatus: entity.FulfilmentPacked, Total: 580,
+					Items: []dto.OrderItem{{Item: "hoody", Quantity: 2, UnitPrice: 290, Total: 580}}, CreatedAt: createdAt, FulfilmentChangedAt: &packedAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":5,"employee":"alice","status":"placed","fulfilmentStatus":"packed","total":580,` +
+				`"items":[{"item":"hoody","quantity":2,"unitPrice":290,"total":580}],"createdAt":"2024-06-03T12:00:00Z","fulfilmentChangedAt":"2024-06-03T13:00:00Z"}`,
+		},
+		{
+			name:        "Error - Step already done",
+			requestBody: `{"status":"packed"}`,
+			mockSetup: func() {
+				mockOrderService.On("Advance", testifyMock.Anything, 5, "packed").Return(nil, service.ErrOrderTransition).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"order is not at the previous fulfilment step"}`,
+		},
+		{
+			name:           "Error - Unknown step",
+			requestBody:    `{"status":"shipped"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/5/advance", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 10)
+			c.SetParamNames("id")
+			c.SetParamValues("5")
+
+			err := orderHandler.AdvanceOrder(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockOrderService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRefundOrder(t *testing.T) {
+	e := echo.New()
+	mockOrderService := new(mock.MockOrderService)
+	orderHandler := httphandler.NewOrderHandler(mockOrderService)
+
+	mockOrderService.On("Refund", testifyMock.Anything, 5).Return(nil, service.ErrOrderTransition).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/orders/5/refund", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("userID", 10)
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+
+	err := orderHandler.RefundOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.JSONEq(t, `{"error":"order is already cancelled or refunded"}`, rec.Body.String())
+
+	mockOrderService.AssertExpectations(t)
+}
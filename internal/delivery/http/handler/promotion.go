@@ -0,0 +1,104 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+type PromotionHandler struct {
+	promotionService service.PromotionService
+	validate         *validator.Validate
+}
+
+func NewPromotionHandler(promotionService service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: promotionService,
+		validate:         validator.New(),
+	}
+}
+
+func (h *PromotionHandler) CreatePromotion(c echo.Context) error {
+	adminID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.CreatePromotionRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	promotion := entity.Promotion{
+		Name:               request.Name,
+		DiscountType:       request.DiscountType,
+		Discount:           request.Discount,
+		Item:               request.Item,
+		Category:           request.Category,
+		Code:               request.Code,
+		EndsAt:             request.EndsAt,
+		MaxUses:            request.MaxUses,
+		MaxUsesPerEmployee: request.MaxUsesPerEmployee,
+	}
+	if request.StartsAt != nil {
+		promotion.StartsAt = *request.StartsAt
+	}
+
+	created, err := h.promotionService.Create(c.Request().Context(), adminID, promotion)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidPromotion:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid promotion"})
+		case service.ErrMerchNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "merch not found"})
+		case service.ErrPromoCodeAlreadyExists:
+			return c.JSON(http.StatusConflict, map[string]string{"error": "promo code already exists"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create promotion"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *PromotionHandler) ListPromotions(c echo.Context) error {
+	promotions, err := h.promotionService.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list promotions"})
+	}
+
+	return c.JSON(http.StatusOK, promotions)
+}
+
+func (h *PromotionHandler) DisablePromotion(c echo.Context) error {
+	promotionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid promotion id"})
+	}
+
+	err = h.promotionService.Disable(c.Request().Context(), promotionID)
+	if err != nil {
+		switch err {
+		case service.ErrPromotionNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "promotion not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to disable promotion"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "promotion disabled successfully"})
+}
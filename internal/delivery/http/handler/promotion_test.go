@@ -0,0 +1,163 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	e := echo.New()
+	mockPromotionService := new(mock.MockPromotionService)
+	promotionHandler := httphandler.NewPromotionHandler(mockPromotionService)
+
+	startsAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 5, 30, 12, 0, 0, 0, time.UTC)
+	maxUses := 100
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success - Promotion created",
+			requestBody: `{"name":"Hoody sale","discountType":"percent","discount":25,"item":"hoody","code":"hoody25",` +
+				`"startsAt":"2024-06-01T00:00:00Z","endsAt":"2024-06-08T00:00:00Z","maxUses":100}`,
+			mockSetup: func() {
+				mockPromotionService.On("Create", testifyMock.Anything, 10, entity.Promotion{
+					Name: "Hoody sale", DiscountType: entity.DiscountPercent, Discount: 25, Item: "hoody", Code: "hoody25",
+					StartsAt: startsAt, EndsAt: endsAt, MaxUses: &maxUses,
+				}).Return(&dto.Promotion{
+					ID: 4, Name: "Hoody sale", DiscountType: entity.DiscountPercent, Discount: 25, Item: "hoody", Code: "HOODY25",
+					Status: entity.PromotionScheduled, StartsAt: startsAt, EndsAt: endsAt, MaxUses: &maxUses, CreatedAt: createdAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":4,"name":"Hoody sale","discountType":"percent","discount":25,"item":"hoody","code":"HOODY25",` +
+				`"status":"scheduled","startsAt":"2024-06-01T00:00:00Z","endsAt":"2024-06-08T00:00:00Z","maxUses":100,"uses":0,` +
+				`"createdAt":"2024-05-30T12:00:00Z"}`,
+		},
+		{
+			name:           "Error - Unknown discount type",
+			requestBody:    `{"name":"Sale","discountType":"bogo","discount":1,"endsAt":"2024-06-08T00:00:00Z"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:        "Error - Invalid promotion",
+			requestBody: `{"name":"Free","discountType":"percent","discount":150,"endsAt":"2024-06-08T00:00:00Z"}`,
+			mockSetup: func() {
+				mockPromotionService.On("Create", testifyMock.Anything, 10, entity.Promotion{
+					Name: "Free", DiscountType: entity.DiscountPercent, Discount: 150, EndsAt: endsAt,
+				}).Return(nil, service.ErrInvalidPromotion).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid promotion"}`,
+		},
+		{
+			name:        "Error - Code already exists",
+			requestBody: `{"name":"Sale","discountType":"fixed","discount":10,"code":"SALE","endsAt":"2024-06-08T00:00:00Z"}`,
+			mockSetup: func() {
+				mockPromotionService.On("Create", testifyMock.Anything, 10, entity.Promotion{
+					Name: "Sale", DiscountType: entity.DiscountFixed, Discount: 10, Code: "SALE", EndsAt: endsAt,
+				}).Return(nil, service.ErrPromoCodeAlreadyExists).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"promo code already exists"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/promotions", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 10)
+
+			err := promotionHandler.CreatePromotion(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockPromotionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisablePromotion(t *testing.T) {
+	e := echo.New()
+	mockPromotionService := new(mock.MockPromotionService)
+	promotionHandler := httphandler.NewPromotionHandler(mockPromotionService)
+
+	tests := []struct {
+		name           string
+		promotionID    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Promotion disabled",
+			promotionID: "4",
+			mockSetup: func() {
+				mockPromotionService.On("Disable", testifyMock.Anything, 4).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"promotion disabled successfully"}`,
+		},
+		{
+			name:        "Error - Not found",
+			promotionID: "5",
+			mockSetup: func() {
+				mockPromotionService.On("Disable", testifyMock.Anything, 5).Return(service.ErrPromotionNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"promotion not found"}`,
+		},
+		{
+			name:           "Error - Invalid id",
+			promotionID:    "abc",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid promotion id"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/admin/promotions/"+tt.promotionID, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.promotionID)
+
+			err := promotionHandler.DisablePromotion(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockPromotionService.AssertExpectations(t)
+		})
+	}
+}
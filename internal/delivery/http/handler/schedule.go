@@ -0,0 +1,213 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+type ScheduleHandler struct {
+	scheduleService service.ScheduleService
+	validate        *validator.Validate
+}
+
+func NewScheduleHandler(scheduleService service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		validate:        validator.New(),
+	}
+}
+
+func scheduleDefinition(request dto.ScheduleRequest) (entity.ScheduleDefinition, error) {
+	definition := entity.ScheduleDefinition{
+		Name:       request.Name,
+		Recipients: request.Recipients,
+		Amount:     request.Amount,
+		Note:       entity.TransferNote{Message: request.Message, Category: request.Category},
+		Cron:       request.Cron,
+		Active:     request.Active == nil || *request.Active,
+	}
+
+	if request.Interval != "" {
+		interval, err := time.ParseDuration(request.Interval)
+		if err != nil {
+			return entity.ScheduleDefinition{}, err
+		}
+		definition.Interval = interval
+	}
+
+	return definition, nil
+}
+
+func scheduleError(c echo.Context, err error, fallback string) error {
+	switch err {
+	case service.ErrInvalidSchedule:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid schedule: set either a valid cron expression or an interval of at least 1m"})
+	case service.ErrInvalidMessage:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message is too long"})
+	case service.ErrInvalidCategory:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid category"})
+	case service.ErrSelfTransaction:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "sender and recipient cannot be the same user"})
+	case service.ErrEmployeeNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
+	case service.ErrScheduleNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "schedule not found"})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fallback})
+	}
+}
+
+func (h *ScheduleHandler) CreateSchedule(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.ScheduleRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	definition, err := scheduleDefinition(request)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid interval"})
+	}
+
+	schedule, err := h.scheduleService.Create(c.Request().Context(), userID, definition)
+	if err != nil {
+		return scheduleError(c, err, "failed to create schedule")
+	}
+
+	return c.JSON(http.StatusCreated, schedule)
+}
+
+func (h *ScheduleHandler) ListSchedules(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	schedules, err := h.scheduleService.List(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list schedules"})
+	}
+
+	return c.JSON(http.StatusOK, schedules)
+}
+
+func (h *ScheduleHandler) GetSchedule(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	scheduleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid schedule id"})
+	}
+
+	schedule, err := h.scheduleService.Get(c.Request().Context(), userID, scheduleID)
+	if err != nil {
+		return scheduleError(c, err, "failed to get schedule")
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (h *ScheduleHandler) UpdateSchedule(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	scheduleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid schedule id"})
+	}
+
+	if c.Request().Header.Get("Content-Type") != "application/json" {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+	}
+
+	var request dto.ScheduleRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	definition, err := scheduleDefinition(request)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid interval"})
+	}
+
+	schedule, err := h.scheduleService.Update(c.Request().Context(), userID, scheduleID, definition)
+	if err != nil {
+		return scheduleError(c, err, "failed to update schedule")
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (h *ScheduleHandler) DeleteSchedule(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	scheduleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid schedule id"})
+	}
+
+	err = h.scheduleService.Delete(c.Request().Context(), userID, scheduleID)
+	if err != nil {
+		return scheduleError(c, err, "failed to delete schedule")
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "schedule deleted successfully"})
+}
+
+func (h *ScheduleHandler) ListRuns(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	scheduleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid schedule id"})
+	}
+
+	limit := 0
+	if value := c.QueryParam("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit"})
+		}
+	}
+
+	runs, err := h.scheduleService.ListRuns(c.Request().Context(), userID, scheduleID, limit)
+	if err != nil {
+		return scheduleError(c, err, "failed to list schedule runs")
+	}
+
+	return c.JSON(http.StatusOK, runs)
+}
@@ -0,0 +1,265 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestCreateSchedule(t *testing.T) {
+	e := echo.New()
+	mockScheduleService := new(mock.MockScheduleService)
+	scheduleHandler := httphandler.NewScheduleHandler(mockScheduleService)
+
+	nextRunAt := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Success - Cron schedule created",
+			requestBody: `{"recipients":["bob"],"amount":10,"cron":"0 9 * * 1","message":"weekly thanks"}`,
+			mockSetup: func() {
+				mockScheduleService.On("Create", testifyMock.Anything, 1, entity.ScheduleDefinition{
+					Recipients: []string{"bob"},
+					Amount:     10,
+					Note:       entity.TransferNote{Message: "weekly thanks"},
+					Cron:       "0 9 * * 1",
+					Active:     true,
+				}).Return(&dto.Schedule{
+					ID: 4, Recipients: []string{"bob"}, Amount: 10, Message: "weekly thanks", Cron: "0 9 * * 1",
+					Active: true, NextRunAt: nextRunAt, CreatedAt: createdAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":4,"recipients":["bob"],"amount":10,"message":"weekly thanks","cron":"0 9 * * 1",` +
+				`"active":true,"nextRunAt":"2024-06-10T09:00:00Z","createdAt":"2024-06-03T12:00:00Z"}`,
+		},
+		{
+			name:        "Success - Paused interval schedule created",
+			requestBody: `{"recipients":["bob"],"amount":10,"interval":"24h","active":false}`,
+			mockSetup: func() {
+				mockScheduleService.On("Create", testifyMock.Anything, 1, entity.ScheduleDefinition{
+					Recipients: []string{"bob"},
+					Amount:     10,
+					Interval:   24 * time.Hour,
+					Active:     false,
+				}).Return(&dto.Schedule{
+					ID: 5, Recipients: []string{"bob"}, Amount: 10, Interval: "24h0m0s",
+					NextRunAt: nextRunAt, CreatedAt: createdAt,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":5,"recipients":["bob"],"amount":10,"interval":"24h0m0s",` +
+				`"active":false,"nextRunAt":"2024-06-10T09:00:00Z","createdAt":"2024-06-03T12:00:00Z"}`,
+		},
+		{
+			name:           "Error - No recipients",
+			requestBody:    `{"recipients":[],"amount":10,"cron":"0 9 * * 1"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:           "Error - Invalid interval",
+			requestBody:    `{"recipients":["bob"],"amount":10,"interval":"daily"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid interval"}`,
+		},
+		{
+			name:        "Error - Invalid schedule",
+			requestBody: `{"recipients":["bob"],"amount":10,"cron":"every monday"}`,
+			mockSetup: func() {
+				mockScheduleService.On("Create", testifyMock.Anything, 1, testifyMock.Anything).
+					Return(nil, service.ErrInvalidSchedule).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid schedule: set either a valid cron expression or an interval of at least 1m"}`,
+		},
+		{
+			name:        "Error - Recipient not found",
+			requestBody: `{"recipients":["ghost"],"amount":10,"cron":"0 9 * * 1"}`,
+			mockSetup: func() {
+				mockScheduleService.On("Create", testifyMock.Anything, 1, testifyMock.Anything).
+					Return(nil, service.ErrEmployeeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"employee not found"}`,
+		},
+		{
+			name:        "Error - Internal Server Error",
+			requestBody: `{"recipients":["bob"],"amount":10,"cron":"0 9 * * 1"}`,
+			mockSetup: func() {
+				mockScheduleService.On("Create", testifyMock.Anything, 1, testifyMock.Anything).
+					Return(nil, service.ErrDatabaseError).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to create schedule"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/schedules", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+
+			err := scheduleHandler.CreateSchedule(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockScheduleService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	e := echo.New()
+	mockScheduleService := new(mock.MockScheduleService)
+	scheduleHandler := httphandler.NewScheduleHandler(mockScheduleService)
+
+	tests := []struct {
+		name           string
+		scheduleID     string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "Success - Schedule deleted",
+			scheduleID: "4",
+			mockSetup: func() {
+				mockScheduleService.On("Delete", testifyMock.Anything, 1, 4).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"schedule deleted successfully"}`,
+		},
+		{
+			name:           "Error - Invalid id",
+			scheduleID:     "abc",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid schedule id"}`,
+		},
+		{
+			name:       "Error - Schedule not found",
+			scheduleID: "4",
+			mockSetup: func() {
+				mockScheduleService.On("Delete", testifyMock.Anything, 1, 4).Return(service.ErrScheduleNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"schedule not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/schedules/"+tt.scheduleID, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.scheduleID)
+
+			err := scheduleHandler.DeleteSchedule(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockScheduleService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListRuns(t *testing.T) {
+	e := echo.New()
+	mockScheduleService := new(mock.MockScheduleService)
+	scheduleHandler := httphandler.NewScheduleHandler(mockScheduleService)
+
+	scheduledFor := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	timestamp := time.Date(2024, 6, 10, 9, 0, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success - Runs listed",
+			query: "?limit=10",
+			mockSetup: func() {
+				mockScheduleService.On("ListRuns", testifyMock.Anything, 1, 4, 10).Return([]dto.ScheduleRun{
+					{ID: 2, Recipient: "carol", Amount: 10, Status: entity.ScheduleRunFailed, Error: "insufficient funds", ScheduledFor: scheduledFor, Timestamp: timestamp},
+					{ID: 1, Recipient: "bob", Amount: 10, Status: entity.ScheduleRunSucceeded, ScheduledFor: scheduledFor, Timestamp: timestamp},
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":2,"recipient":"carol","amount":10,"status":"failed","error":"insufficient funds",` +
+				`"scheduledFor":"2024-06-10T09:00:00Z","timestamp":"2024-06-10T09:00:05Z"},` +
+				`{"id":1,"recipient":"bob","amount":10,"status":"succeeded",` +
+				`"scheduledFor":"2024-06-10T09:00:00Z","timestamp":"2024-06-10T09:00:05Z"}]`,
+		},
+		{
+			name:           "Error - Invalid limit",
+			query:          "?limit=-1",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid limit"}`,
+		},
+		{
+			name:  "Error - Schedule not found",
+			query: "",
+			mockSetup: func() {
+				mockScheduleService.On("ListRuns", testifyMock.Anything, 1, 4, 0).Return(nil, service.ErrScheduleNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"schedule not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/schedules/4/runs"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+			c.SetParamNames("id")
+			c.SetParamValues("4")
+
+			err := scheduleHandler.ListRuns(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockScheduleService.AssertExpectations(t)
+		})
+	}
+}
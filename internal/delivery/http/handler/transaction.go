@@ -1,12 +1,19 @@
 package httphandler
 
 import (
+	"encoding/csv"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
@@ -41,14 +48,21 @@ func (h *TransactionHandler) SendCoin(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
 	}
 
-	if err := h.transactionService.SendCoins(c.Request().Context(), userID, request.ToUser, request.Amount); err != nil {
+	note := entity.TransferNote{Message: request.Message, Category: request.Category}
+	if err := h.transactionService.SendCoins(c.Request().Context(), userID, request.ToUser, request.Amount, note); err != nil {
 		switch err {
+		case service.ErrInvalidMessage:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "message is too long"})
+		case service.ErrInvalidCategory:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid category"})
 		case service.ErrEmployeeNotFound:
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
 		case service.ErrInsufficientFunds:
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "insufficient funds"})
 		case service.ErrSelfTransaction:
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "sender and recipient cannot be the same user"})
+		case service.ErrConcurrentUpdate:
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "too many concurrent transfers, try again"})
 		default:
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to send coins"})
 		}
@@ -56,3 +70,140 @@ func (h *TransactionHandler) SendCoin(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "coins sent successfully"})
 }
+
+func (h *TransactionHandler) GetHistory(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var request dto.HistoryRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid query parameters"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	filter := entity.TransferFilter{
+		Direction:    request.Direction,
+		Counterparty: request.Counterparty,
+		Category:     request.Category,
+		Search:       request.Search,
+		Limit:        request.Limit,
+	}
+
+	if request.From != "" {
+		from, _ := time.Parse(time.RFC3339, request.From)
+		from = from.UTC()
+		filter.From = &from
+	}
+
+	if request.To != "" {
+		to, _ := time.Parse(time.RFC3339, request.To)
+		to = to.UTC()
+		filter.To = &to
+	}
+
+	history, err := h.transactionService.GetHistory(c.Request().Context(), userID, filter, request.Cursor)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCursor:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid cursor"})
+		case service.ErrInvalidCategory:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid category"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to fetch history"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+func (h *TransactionHandler) IssueCoins(c echo.Context) error {
+	userID, ok := c.Get("userID").(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var request dto.GrantRequest
+	contentType := c.Request().Header.Get("Content-Type")
+	switch {
+	case contentType == "application/json":
+		if err := c.Bind(&request); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON data"})
+		}
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "CSV file is required"})
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "CSV file is required"})
+		}
+		defer file.Close()
+
+		request.Reason = c.FormValue("reason")
+		request.Grants, err = parseGrantsCSV(file)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid CSV data"})
+		}
+	default:
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json or multipart/form-data"})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+	}
+
+	grants := make([]entity.CoinGrant, len(request.Grants))
+	for i, line := range request.Grants {
+		grants[i] = entity.CoinGrant{Username: line.User, Amount: line.Amount}
+	}
+
+	response, err := h.transactionService.IssueCoins(c.Request().Context(), userID, request.Reason, grants)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidGrant:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request data"})
+		case service.ErrEmployeeNotFound:
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "employee not found"})
+		case service.ErrConcurrentUpdate:
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "too many concurrent updates, try again"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to issue coins"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// parseGrantsCSV reads "user,amount" rows. A leading header row is skipped.
+func parseGrantsCSV(r io.Reader) ([]dto.GrantLine, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	grants := make([]dto.GrantLine, 0, len(records))
+	for i, record := range records {
+		amount, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, errors.New("invalid amount in CSV row " + strconv.Itoa(i+1))
+		}
+
+		grants = append(grants, dto.GrantLine{User: strings.TrimSpace(record[0]), Amount: amount})
+	}
+
+	return grants, nil
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/handler"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
 )
@@ -41,7 +44,7 @@ func TestSendCoin(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func() {
-				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50).
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50, entity.TransferNote{}).
 					Return(nil).Once()
 			},
 			expectedStatus: http.StatusOK,
@@ -74,6 +77,48 @@ func TestSendCoin(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"error":"invalid request data"}`,
 		},
+		{
+			name:   "Success - Coins sent with message",
+			userID: 1,
+			requestBody: dto.SendCoinRequest{
+				ToUser:   "bob",
+				Amount:   50,
+				Message:  "thanks for the review",
+				Category: "thanks",
+			},
+			contentType: "application/json",
+			mockSetup: func() {
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50,
+					entity.TransferNote{Message: "thanks for the review", Category: "thanks"}).
+					Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"coins sent successfully"}`,
+		},
+		{
+			name:        "Error - Message too long",
+			userID:      1,
+			requestBody: dto.SendCoinRequest{ToUser: "bob", Amount: 50, Message: "long"},
+			contentType: "application/json",
+			mockSetup: func() {
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50, entity.TransferNote{Message: "long"}).
+					Return(service.ErrInvalidMessage).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"message is too long"}`,
+		},
+		{
+			name:        "Error - Invalid category",
+			userID:      1,
+			requestBody: dto.SendCoinRequest{ToUser: "bob", Amount: 50, Category: "bribe"},
+			contentType: "application/json",
+			mockSetup: func() {
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50, entity.TransferNote{Category: "bribe"}).
+					Return(service.ErrInvalidCategory).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid category"}`,
+		},
 		{
 			name:   "Error - Employee Not Found",
 			userID: 1,
@@ -83,7 +128,7 @@ func TestSendCoin(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func() {
-				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50).
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50, entity.TransferNote{}).
 					Return(service.ErrEmployeeNotFound).Once()
 			},
 			expectedStatus: http.StatusNotFound,
@@ -98,7 +143,7 @@ func TestSendCoin(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func() {
-				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50).
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50, entity.TransferNote{}).
 					Return(service.ErrInsufficientFunds).Once()
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -113,12 +158,24 @@ func TestSendCoin(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func() {
-				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "alice", 50).
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "alice", 50, entity.TransferNote{}).
 					Return(service.ErrSelfTransaction).Once()
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"error":"sender and recipient cannot be the same user"}`,
 		},
+		{
+			name:        "Error - Concurrent update",
+			userID:      1,
+			requestBody: dto.SendCoinRequest{ToUser: "bob", Amount: 50},
+			contentType: "application/json",
+			mockSetup: func() {
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50, entity.TransferNote{}).
+					Return(service.ErrConcurrentUpdate).Once()
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"too many concurrent transfers, try again"}`,
+		},
 		{
 			name:   "Error - Internal Server Error",
 			userID: 1,
@@ -128,7 +185,7 @@ func TestSendCoin(t *testing.T) {
 			},
 			contentType: "application/json",
 			mockSetup: func() {
-				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50).
+				mockTransactionService.On("SendCoins", testifyMock.Anything, 1, "bob", 50, entity.TransferNote{}).
 					Return(errors.New("unexpected error")).Once()
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -159,3 +216,246 @@ func TestSendCoin(t *testing.T) {
 		})
 	}
 }
+
+func TestGetHistory(t *testing.T) {
+	e := echo.New()
+	mockTransactionService := new(mock.MockTransactionService)
+	handler := httphandler.NewTransactionHandler(mockTransactionService)
+
+	from := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		userID         interface{}
+		query          string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success - History returned",
+			userID: 1,
+			query:  "?direction=sent&counterparty=bob&from=2025-02-01T12:00:00%2B03:00&limit=10&cursor=abc",
+			mockSetup: func() {
+				filter := entity.TransferFilter{Direction: "sent", Counterparty: "bob", From: &from, Limit: 10}
+				mockTransactionService.On("GetHistory", testifyMock.Anything, 1, filter, "abc").
+					Return(&dto.HistoryResponse{
+						Transactions: []dto.HistoryTransaction{{ID: 7, Kind: "transfer", Counterparty: "bob", Amount: 10, Direction: "sent", Timestamp: from}},
+						NextCursor:   "next",
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"transactions":[{"id":7,"kind":"transfer","counterparty":"bob","amount":10,"direction":"sent","timestamp":"2025-02-01T09:00:00Z"}],"nextCursor":"next"}`,
+		},
+		{
+			name:           "Error - Unauthorized",
+			userID:         nil,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}`,
+		},
+		{
+			name:           "Error - Invalid direction",
+			userID:         1,
+			query:          "?direction=sideways",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:           "Error - Invalid date",
+			userID:         1,
+			query:          "?from=yesterday",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:   "Success - Filtered by category and text",
+			userID: 1,
+			query:  "?category=thanks&q=review",
+			mockSetup: func() {
+				filter := entity.TransferFilter{Category: "thanks", Search: "review"}
+				mockTransactionService.On("GetHistory", testifyMock.Anything, 1, filter, "").
+					Return(&dto.HistoryResponse{
+						Transactions: []dto.HistoryTransaction{{ID: 8, Kind: "transfer", Counterparty: "bob", Amount: 10, Direction: "received",
+							Message: "thanks for the review", Category: "thanks", Timestamp: from}},
+					}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"transactions":[{"id":8,"kind":"transfer","counterparty":"bob","amount":10,"direction":"received",` +
+				`"message":"thanks for the review","category":"thanks","timestamp":"2025-02-01T09:00:00Z"}]}`,
+		},
+		{
+			name:   "Error - Invalid category",
+			userID: 1,
+			query:  "?category=bribe",
+			mockSetup: func() {
+				mockTransactionService.On("GetHistory", testifyMock.Anything, 1, entity.TransferFilter{Category: "bribe"}, "").
+					Return(nil, service.ErrInvalidCategory).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid category"}`,
+		},
+		{
+			name:   "Error - Invalid cursor",
+			userID: 1,
+			query:  "?cursor=broken",
+			mockSetup: func() {
+				mockTransactionService.On("GetHistory", testifyMock.Anything, 1, entity.TransferFilter{}, "broken").
+					Return(nil, service.ErrInvalidCursor).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid cursor"}`,
+		},
+		{
+			name:   "Error - Internal Server Error",
+			userID: 1,
+			mockSetup: func() {
+				mockTransactionService.On("GetHistory", testifyMock.Anything, 1, entity.TransferFilter{}, "").
+					Return(nil, errors.New("unexpected error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to fetch history"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/history"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.userID != nil {
+				c.Set("userID", tt.userID)
+			}
+
+			err := handler.GetHistory(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockTransactionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssueCoins(t *testing.T) {
+	e := echo.New()
+	mockTransactionService := new(mock.MockTransactionService)
+	handler := httphandler.NewTransactionHandler(mockTransactionService)
+
+	csvBody := func(reason, content string) (string, *bytes.Buffer) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("reason", reason)
+		part, _ := writer.CreateFormFile("file", "grants.csv")
+		_, _ = part.Write([]byte(content))
+		_ = writer.Close()
+		return writer.FormDataContentType(), body
+	}
+
+	tests := []struct {
+		name           string
+		userID         interface{}
+		request        func() (string, *bytes.Buffer)
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success - JSON grants",
+			userID: 9,
+			request: func() (string, *bytes.Buffer) {
+				return "application/json", bytes.NewBufferString(`{"reason":"Q1 bonus","grants":[{"user":"alice","amount":100}]}`)
+			},
+			mockSetup: func() {
+				mockTransactionService.On("IssueCoins", testifyMock.Anything, 9, "Q1 bonus", []entity.CoinGrant{{Username: "alice", Amount: 100}}).
+					Return(&dto.GrantResponse{GrantID: 1, Total: 100, Recipients: 1}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"grantId":1,"total":100,"recipients":1}`,
+		},
+		{
+			name:   "Success - CSV upload",
+			userID: 9,
+			request: func() (string, *bytes.Buffer) {
+				return csvBody("hackathon", "user,amount\nalice,300\nbob, 200\n")
+			},
+			mockSetup: func() {
+				mockTransactionService.On("IssueCoins", testifyMock.Anything, 9, "hackathon", []entity.CoinGrant{{Username: "alice", Amount: 300}, {Username: "bob", Amount: 200}}).
+					Return(&dto.GrantResponse{GrantID: 2, Total: 500, Recipients: 2}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"grantId":2,"total":500,"recipients":2}`,
+		},
+		{
+			name:   "Error - Invalid CSV",
+			userID: 9,
+			request: func() (string, *bytes.Buffer) {
+				return csvBody("hackathon", "alice,300\nbob,lots\n")
+			},
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid CSV data"}`,
+		},
+		{
+			name:   "Error - Missing reason",
+			userID: 9,
+			request: func() (string, *bytes.Buffer) {
+				return "application/json", bytes.NewBufferString(`{"grants":[{"user":"alice","amount":100}]}`)
+			},
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request data"}`,
+		},
+		{
+			name:   "Error - Unsupported Content-Type",
+			userID: 9,
+			request: func() (string, *bytes.Buffer) {
+				return "text/plain", bytes.NewBufferString("alice,100")
+			},
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `{"error":"Content-Type must be application/json or multipart/form-data"}`,
+		},
+		{
+			name:   "Error - Recipient not found",
+			userID: 9,
+			request: func() (string, *bytes.Buffer) {
+				return "application/json", bytes.NewBufferString(`{"reason":"Q1 bonus","grants":[{"user":"ghost","amount":100}]}`)
+			},
+			mockSetup: func() {
+				mockTransactionService.On("IssueCoins", testifyMock.Anything, 9, "Q1 bonus", []entity.CoinGrant{{Username: "ghost", Amount: 100}}).
+					Return(nil, service.ErrEmployeeNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"employee not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			contentType, body := tt.request()
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/grants", body)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tt.userID != nil {
+				c.Set("userID", tt.userID)
+			}
+
+			err := handler.IssueCoins(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+
+			mockTransactionService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// idempotencyStoreTimeout bounds storing the outcome of a request, which
+	// is done even if the client has gone away.
+	idempotencyStoreTimeout = 5 * time.Second
+)
+
+// IdempotencyMiddleware replays the stored response for a repeated Idempotency-Key
+// instead of executing the request again. It must run after JWTMiddleware.
+func IdempotencyMiddleware(idempotencyService service.IdempotencyService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(idempotencyKeyHeader)
+			if key == "" {
+				return next(c)
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid idempotency key"})
+			}
+
+			userID, ok := c.Get("userID").(int)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read request body"})
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			fingerprint := requestFingerprint(c.Request(), body)
+
+			record, err := idempotencyService.Begin(ctx, userID, key, fingerprint)
+			if err != nil {
+				switch err {
+				case service.ErrIdempotencyKeyMismatch:
+					return c.JSON(http.StatusConflict, map[string]string{"error": "idempotency key was used with a different request"})
+				case service.ErrIdempotencyKeyInProgress:
+					return c.JSON(http.StatusConflict, map[string]string{"error": "request with this idempotency key is in progress"})
+				default:
+					return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to process idempotency key"})
+				}
+			}
+
+			if record != nil {
+				c.Response().Header().Set(idempotentReplayedHeader, "true")
+				return c.JSONBlob(record.ResponseStatus, record.ResponseBody)
+			}
+
+			// The key is released if the request fails or panics, so that it can
+			// be retried; a stored response is kept until the key expires.
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyStoreTimeout)
+				defer cancel()
+				if err := idempotencyService.Release(storeCtx, userID, key); err != nil {
+					log.Printf("failed to release idempotency key %q of user %d: %v", key, userID, err)
+				}
+			}()
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError {
+				return nil
+			}
+
+			storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyStoreTimeout)
+			defer cancel()
+			if err := idempotencyService.Complete(storeCtx, userID, key, status, recorder.body.Bytes()); err != nil {
+				log.Printf("failed to store response for idempotency key %q of user %d: %v", key, userID, err)
+			}
+			completed = true
+
+			return nil
+		}
+	}
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/middleware"
+	"github.com/vit6556/avito-internship-assignment/internal/service/mock"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	liveContext := testifyMock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+
+	tests := []struct {
+		name      string
+		handler   echo.HandlerFunc
+		mockSetup func(m *mock.MockIdempotencyService)
+		released  bool
+	}{
+		{
+			name: "Success - Response Stored After Client Disconnect",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+			},
+			mockSetup: func(m *mock.MockIdempotencyService) {
+				m.On("Begin", testifyMock.Anything, 1, "key-1", testifyMock.Anything).Return(nil, nil)
+				m.On("Complete", liveContext, 1, "key-1", http.StatusOK, testifyMock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "Error - Key Released After Server Error",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+			},
+			mockSetup: func(m *mock.MockIdempotencyService) {
+				m.On("Begin", testifyMock.Anything, 1, "key-1", testifyMock.Anything).Return(nil, nil)
+				m.On("Release", liveContext, 1, "key-1").Return(nil)
+			},
+			released: true,
+		},
+		{
+			name: "Error - Key Released After Panic",
+			handler: func(c echo.Context) error {
+				panic("handler failed")
+			},
+			mockSetup: func(m *mock.MockIdempotencyService) {
+				m.On("Begin", testifyMock.Anything, 1, "key-1", testifyMock.Anything).Return(nil, nil)
+				m.On("Release", liveContext, 1, "key-1").Return(nil)
+			},
+			released: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockIdempotencyService := new(mock.MockIdempotencyService)
+			tt.mockSetup(mockIdempotencyService)
+
+			e := echo.New()
+			ctx, cancel := context.WithCancel(context.Background())
+			req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{"toUser":"bob","amount":10}`)).WithContext(ctx)
+			req.Header.Set("Idempotency-Key", "key-1")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("userID", 1)
+
+			// The client goes away while the request is being handled.
+			handler := middleware.IdempotencyMiddleware(mockIdempotencyService)(func(c echo.Context) error {
+				cancel()
+				return tt.handler(c)
+			})
+
+			func() {
+				defer func() { _ = recover() }()
+				_ = handler(c)
+			}()
+
+			mockIdempotencyService.AssertExpectations(t)
+			if tt.released {
+				mockIdempotencyService.AssertNotCalled(t, "Complete", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything, testifyMock.Anything, testifyMock.Anything)
+			}
+		})
+	}
+}
@@ -2,29 +2,64 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
-func JWTMiddleware(authService service.AuthService) echo.MiddlewareFunc {
+// JWTMiddleware authenticates the request by a Bearer access token. When
+// scopes are given, an X-API-Key header with a key holding all of them is
+// accepted instead; without scopes API keys are rejected.
+func JWTMiddleware(authService service.AuthService, apiKeyService service.APIKeyService, scopes ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if rawKey := c.Request().Header.Get("X-API-Key"); rawKey != "" {
+				return authenticateAPIKey(c, next, apiKeyService, rawKey, scopes)
+			}
+
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
 			}
 
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-			userID, err := authService.ValidateToken(tokenString)
+			claims, err := authService.ValidateToken(c.Request().Context(), tokenString)
 			if err != nil {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
 			}
 
-			c.Set("userID", userID)
+			c.Set("userID", claims.UserID)
+			c.Set("role", claims.Role)
+			c.Set("claims", claims)
 
 			return next(c)
 		}
 	}
 }
+
+func authenticateAPIKey(c echo.Context, next echo.HandlerFunc, apiKeyService service.APIKeyService, rawKey string, scopes []string) error {
+	if len(scopes) == 0 {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "api keys are not accepted for this endpoint"})
+	}
+
+	key, err := apiKeyService.Authenticate(c.Request().Context(), rawKey)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(key.Scopes, scope) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "api key lacks scope " + scope})
+		}
+	}
+
+	// API keys never carry elevated roles, whatever the role of their employee.
+	c.Set("userID", key.EmployeeID)
+	c.Set("role", entity.RoleEmployee)
+	c.Set("apiKey", key)
+
+	return next(c)
+}
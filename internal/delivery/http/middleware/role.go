@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireRole allows the request only if the caller has one of the given roles.
+// It must run after JWTMiddleware.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	allowed := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		allowed[role] = struct{}{}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, ok := c.Get("role").(string)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+
+			if _, ok := allowed[role]; !ok {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// Scopes limit the operations an API key may perform.
+const (
+	ScopeCoinsRead = "coins:read"
+	ScopeCoinsSend = "coins:send"
+	ScopeMerchRead = "merch:read"
+	ScopeMerchBuy  = "merch:buy"
+)
+
+var APIKeyScopes = []string{ScopeCoinsRead, ScopeCoinsSend, ScopeMerchRead, ScopeMerchBuy}
+
+type APIKey struct {
+	ID         int
+	EmployeeID int
+	Username   string
+	Name       string
+	Prefix     string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type TokenClaims struct {
+	UserID int
+	Role   string
+	// JTI is empty for tokens issued before revocation was introduced.
+	JTI string
+	// TokenVersion must match the employee's current version for the token to be valid.
+	TokenVersion int
+	ExpiresAt    time.Time
+}
@@ -1,5 +1,30 @@
 package entity
 
+import "time"
+
+const (
+	TransferDirectionSent     = "sent"
+	TransferDirectionReceived = "received"
+
+	TransferKindTransfer = "transfer"
+	TransferKindIssuance = "issuance"
+	TransferKindReversal = "reversal"
+
+	TransferCategoryThanks = "thanks"
+	TransferCategoryBonus  = "bonus"
+	TransferCategoryBet    = "bet"
+	TransferCategoryGift   = "gift"
+	TransferCategoryOther  = "other"
+)
+
+var TransferCategories = []string{
+	TransferCategoryThanks,
+	TransferCategoryBonus,
+	TransferCategoryBet,
+	TransferCategoryGift,
+	TransferCategoryOther,
+}
+
 type CoinTransaction struct {
 	User   string
 	Amount int
@@ -9,3 +34,48 @@ type CoinHistory struct {
 	Received []CoinTransaction
 	Sent     []CoinTransaction
 }
+
+type Transfer struct {
+	ID           int
+	Kind         string
+	Counterparty string
+	Amount       int
+	Direction    string
+	Reason       string
+	Message      string
+	Category     string
+	// ReversalOf is the id of the transfer a reversal returns, ReversedBy
+	// the id of the reversal that returned a transfer.
+	ReversalOf int
+	ReversedBy int
+	Timestamp  time.Time
+}
+
+// TransferNote is the optional message and category attached to a coin transfer.
+type TransferNote struct {
+	Message  string
+	Category string
+}
+
+type TransferCursor struct {
+	Timestamp time.Time
+	ID        int
+}
+
+type TransferFilter struct {
+	Direction    string
+	Counterparty string
+	Category     string
+	// Search matches transfers whose message contains the text, ignoring case.
+	Search string
+	From   *time.Time
+	To     *time.Time
+	After  *TransferCursor
+	Limit  int
+}
+
+type CoinGrant struct {
+	EmployeeID int
+	Username   string
+	Amount     int
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+const (
+	DisputeOpen     = "open"
+	DisputeApproved = "approved"
+	DisputeRejected = "rejected"
+)
+
+var DisputeStatuses = []string{
+	DisputeOpen,
+	DisputeApproved,
+	DisputeRejected,
+}
+
+type Dispute struct {
+	ID            int
+	TransactionID int
+	SenderID      int
+	Sender        string
+	ReceiverID    int
+	Receiver      string
+	Amount        int
+	Reason        string
+	Status        string
+	ResolvedBy    string
+	Note          string
+	ReversalID    int
+	// Clawback is set when the reversal took the receiver's balance below zero.
+	Clawback   bool
+	CreatedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+type DisputeFilter struct {
+	Status string
+	// EmployeeID limits the disputes to the ones about transfers the employee
+	// sent or received.
+	EmployeeID int
+}
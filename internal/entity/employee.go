@@ -1,10 +1,20 @@
 package entity
 
+const (
+	RoleEmployee = "employee"
+	RoleHR       = "hr"
+	RoleAdmin    = "admin"
+	// RoleOfficeManager prepares merch orders and hands them over.
+	RoleOfficeManager = "office_manager"
+)
+
 type Employee struct {
 	ID           int
 	Balance      int
 	Username     string
 	PasswordHash string
+	Role         string
+	TokenVersion int
 }
 
 type EmployeeInfo struct {
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+const (
+	PendingTransferPending  = "pending"
+	PendingTransferAccepted = "accepted"
+	PendingTransferDeclined = "declined"
+	PendingTransferExpired  = "expired"
+)
+
+// PendingTransfer is a transfer held in escrow until the recipient accepts or
+// declines it. The coins leave the sender's balance when the transfer is made
+// and go back to the sender if it is declined or expires.
+type PendingTransfer struct {
+	ID         int
+	SenderID   int
+	Sender     string
+	ReceiverID int
+	Receiver   string
+	Amount     int
+	Note       TransferNote
+	Status     string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	ResolvedAt *time.Time
+}
@@ -0,0 +1,10 @@
+package entity
+
+type IdempotencyRecord struct {
+	UserID         int
+	Key            string
+	Fingerprint    string
+	Completed      bool
+	ResponseStatus int
+	ResponseBody   []byte
+}
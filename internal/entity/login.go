@@ -0,0 +1,6 @@
+package entity
+
+const (
+	LoginScopeUsername = "username"
+	LoginScopeIP       = "ip"
+)
@@ -1,12 +1,81 @@
 package entity
 
+import "time"
+
 type MerchItem struct {
-	ID    int
-	Name  string
-	Price int
+	ID       int
+	Name     string
+	Price    int
+	Category string
+	// Stock is nil for items that are not tracked and never run out.
+	Stock *int
 }
 
 type InventoryItem struct {
 	Type     string
 	Quantity int
+	// Spent is the total charged for the item, at the prices paid.
+	Spent int
+}
+
+const (
+	OrderPlaced    = "placed"
+	OrderFulfilled = "fulfilled"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+const (
+	FulfilmentNew            = "new"
+	FulfilmentPacked         = "packed"
+	FulfilmentReadyForPickup = "ready_for_pickup"
+	FulfilmentHandedOver     = "handed_over"
+)
+
+// FulfilmentSteps are the steps a placed order goes through, in order. An
+// order that is handed over becomes fulfilled.
+var FulfilmentSteps = []string{FulfilmentNew, FulfilmentPacked, FulfilmentReadyForPickup, FulfilmentHandedOver}
+
+// OrderTransitions lists the statuses an order can move to from each status.
+// Cancelled and refunded orders are final.
+var OrderTransitions = map[string][]string{
+	OrderPlaced:    {OrderFulfilled, OrderCancelled, OrderRefunded},
+	OrderFulfilled: {OrderRefunded},
+}
+
+type OrderLine struct {
+	ItemID    int
+	Item      string
+	Quantity  int
+	UnitPrice int
+	// Total is what the line was charged. It is recorded with the purchase,
+	// so later repricing does not change it.
+	Total int
+	// ListPrice is the catalog price when a promotion lowered UnitPrice.
+	ListPrice int
+	Promotion *Promotion
+}
+
+type Order struct {
+	ID         int
+	EmployeeID int
+	Employee   string
+	Office     string
+	Lines      []OrderLine
+	Total      int
+	Status     string
+	CreatedAt  time.Time
+	// StatusChangedAt is nil while the order is still placed.
+	StatusChangedAt     *time.Time
+	FulfilmentStatus    string
+	FulfilmentChangedAt *time.Time
+}
+
+// OrderFilter narrows down a list of orders. Zero fields are not applied.
+type OrderFilter struct {
+	EmployeeID       int
+	Office           string
+	Item             string
+	Status           string
+	FulfilmentStatus string
 }
@@ -0,0 +1,9 @@
+package entity
+
+// OIDCLoginState is kept between redirecting an employee to the identity
+// provider and handling the callback.
+type OIDCLoginState struct {
+	State        string
+	CodeVerifier string
+	Nonce        string
+}
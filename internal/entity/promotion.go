@@ -0,0 +1,96 @@
+package entity
+
+import "time"
+
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+const (
+	PromotionScheduled = "scheduled"
+	PromotionActive    = "active"
+	PromotionEnded     = "ended"
+	PromotionDisabled  = "disabled"
+)
+
+// Promotion lowers the price of merch while it runs. It covers a single item,
+// a category or, when neither is set, the whole catalog. A promotion with a
+// Code only applies to orders placed with that code.
+type Promotion struct {
+	ID           int
+	Name         string
+	DiscountType string
+	Discount     int
+	ItemID       *int
+	Item         string
+	Category     string
+	Code         string
+	StartsAt     time.Time
+	EndsAt       time.Time
+	// MaxUses and MaxUsesPerEmployee limit how many orders may use a code.
+	// Cancelled and refunded orders give the use back.
+	MaxUses            *int
+	MaxUsesPerEmployee *int
+	Uses               int
+	CreatedAt          time.Time
+	DisabledAt         *time.Time
+}
+
+func (p *Promotion) Status(now time.Time) string {
+	switch {
+	case p.DisabledAt != nil:
+		return PromotionDisabled
+	case now.Before(p.StartsAt):
+		return PromotionScheduled
+	case now.Before(p.EndsAt):
+		return PromotionActive
+	default:
+		return PromotionEnded
+	}
+}
+
+func (p *Promotion) AppliesTo(item MerchItem) bool {
+	switch {
+	case p.ItemID != nil:
+		return *p.ItemID == item.ID
+	case p.Category != "":
+		return p.Category == item.Category
+	default:
+		return true
+	}
+}
+
+// Price returns the discounted price of one unit. Percentage discounts round
+// the price down and a price never goes below zero.
+func (p *Promotion) Price(price int) int {
+	switch p.DiscountType {
+	case DiscountPercent:
+		return price * (100 - p.Discount) / 100
+	case DiscountFixed:
+		return max(price-p.Discount, 0)
+	default:
+		return price
+	}
+}
+
+// BestPrice returns the lowest unit price of item under promotions and the
+// promotion that gives it. Promotions do not stack; on a tie the earlier one
+// wins. Without a promotion that lowers the price it returns the catalog
+// price and nil.
+func BestPrice(item MerchItem, promotions []*Promotion) (int, *Promotion) {
+	price := item.Price
+	var best *Promotion
+	for _, promotion := range promotions {
+		if !promotion.AppliesTo(item) {
+			continue
+		}
+
+		if discounted := promotion.Price(item.Price); discounted < price {
+			price = discounted
+			best = promotion
+		}
+	}
+
+	return price, best
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+const (
+	ScheduleRunSucceeded = "succeeded"
+	ScheduleRunFailed    = "failed"
+)
+
+type ScheduleRecipient struct {
+	EmployeeID int
+	Username   string
+}
+
+// ScheduledTransfer sends Amount coins from its owner to every recipient each
+// time it runs. Exactly one of Cron and Interval is set.
+type ScheduledTransfer struct {
+	ID         int
+	OwnerID    int
+	Name       string
+	Recipients []ScheduleRecipient
+	Amount     int
+	Note       TransferNote
+	Cron       string
+	Interval   time.Duration
+	Active     bool
+	NextRunAt  time.Time
+	CreatedAt  time.Time
+}
+
+type ScheduleDefinition struct {
+	Name       string
+	Recipients []string
+	Amount     int
+	Note       TransferNote
+	Cron       string
+	Interval   time.Duration
+	Active     bool
+}
+
+type ScheduleRun struct {
+	ID           int
+	ScheduleID   int
+	RecipientID  int
+	Recipient    string
+	Amount       int
+	Status       string
+	Error        string
+	ScheduledFor time.Time
+	Timestamp    time.Time
+}
@@ -0,0 +1,167 @@
+package apikeyservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+const (
+	// keyPrefix makes keys easy to recognise, for example by secret scanners.
+	keyPrefix = "shop_"
+	// displayPrefixLength is how much of a key is kept in clear text.
+	displayPrefixLength = 12
+)
+
+type APIKeyService struct {
+	employeeRepo database.EmployeeRepository
+	apiKeyRepo   database.APIKeyRepository
+}
+
+func NewAPIKeyService(employeeRepo database.EmployeeRepository, apiKeyRepo database.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		employeeRepo: employeeRepo,
+		apiKeyRepo:   apiKeyRepo,
+	}
+}
+
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func toDTO(key *entity.APIKey) dto.APIKey {
+	return dto.APIKey{
+		ID:         key.ID,
+		Username:   key.Username,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func (s *APIKeyService) Create(ctx context.Context, creatorID int, username, name string, scopes []string, expiresAt *time.Time) (*dto.CreatedAPIKey, error) {
+	scopes = slices.Compact(slices.Sorted(slices.Values(scopes)))
+	for _, scope := range scopes {
+		if !slices.Contains(entity.APIKeyScopes, scope) {
+			return nil, service.ErrInvalidScope
+		}
+	}
+
+	if expiresAt != nil {
+		if !expiresAt.After(time.Now()) {
+			return nil, service.ErrInvalidExpiry
+		}
+		// expires_at has no time zone, so the client's offset would be lost.
+		utc := expiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	employee, err := s.employeeRepo.GetEmployeeByUsername(ctx, username)
+	if err != nil {
+		log.Printf("employee %q not found: %v", username, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return nil, service.ErrEmployeeNotFound
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Println("failed to generate api key:", err)
+		return nil, service.ErrAPIKeyCreationFailed
+	}
+	rawKey := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := entity.APIKey{
+		EmployeeID: employee.ID,
+		Username:   employee.Username,
+		Name:       name,
+		Prefix:     rawKey[:displayPrefixLength],
+		Scopes:     scopes,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	key.ID, err = s.apiKeyRepo.CreateAPIKey(ctx, key, hashKey(rawKey), creatorID)
+	if err != nil {
+		log.Printf("failed to create api key for employee %q: %v", username, err)
+		return nil, service.ErrAPIKeyCreationFailed
+	}
+
+	return &dto.CreatedAPIKey{
+		APIKey: toDTO(&key),
+		Key:    rawKey,
+	}, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]dto.APIKey, error) {
+	keys, err := s.apiKeyRepo.ListAPIKeys(ctx)
+	if err != nil {
+		log.Printf("failed to list api keys: %v", err)
+		return nil, service.ErrDatabaseError
+	}
+
+	response := make([]dto.APIKey, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, toDTO(key))
+	}
+
+	return response, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, keyID int) error {
+	err := s.apiKeyRepo.RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		log.Printf("failed to revoke api key %d: %v", keyID, err)
+		switch err {
+		case database.ErrAPIKeyNotFound:
+			return service.ErrAPIKeyNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	return nil
+}
+
+// Authenticate returns the key matching rawKey if it is neither revoked nor
+// expired, and records its use.
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*entity.APIKey, error) {
+	key, err := s.apiKeyRepo.GetAPIKeyByHash(ctx, hashKey(rawKey))
+	if err != nil {
+		switch err {
+		case database.ErrAPIKeyNotFound:
+			return nil, service.ErrInvalidAPIKey
+		default:
+			log.Printf("failed to look up api key: %v", err)
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now())) {
+		return nil, service.ErrInvalidAPIKey
+	}
+
+	if err := s.apiKeyRepo.TouchAPIKey(ctx, key.ID); err != nil {
+		log.Printf("failed to record use of api key %d: %v", key.ID, err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,256 @@
+package apikeyservice_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/apikey"
+)
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockAPIKeyRepo := new(mock.MockAPIKeyRepository)
+	apiKeyService := apikeyservice.NewAPIKeyService(mockEmployeeRepo, mockAPIKeyRepo)
+
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+	moscow := time.Date(2100, 10, 20, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	tests := []struct {
+		name          string
+		scopes        []string
+		expiresAt     *time.Time
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:      "Success - Key Created",
+			scopes:    []string{entity.ScopeCoinsSend, entity.ScopeCoinsRead, entity.ScopeCoinsSend},
+			expiresAt: &future,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "thanksbot").
+					Return(&entity.Employee{ID: 5, Username: "thanksbot"}, nil)
+				mockAPIKeyRepo.On("CreateAPIKey", ctx, testifyMock.MatchedBy(func(key entity.APIKey) bool {
+					return key.EmployeeID == 5 && key.Name == "slack" &&
+						assert.ObjectsAreEqual([]string{entity.ScopeCoinsRead, entity.ScopeCoinsSend}, key.Scopes) &&
+						strings.HasPrefix(key.Prefix, "shop_")
+				}), testifyMock.Anything, 1).Return(7, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "Success - Expiry With Offset Stored In UTC",
+			scopes:    []string{entity.ScopeCoinsSend},
+			expiresAt: &moscow,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "thanksbot").
+					Return(&entity.Employee{ID: 5, Username: "thanksbot"}, nil)
+				mockAPIKeyRepo.On("CreateAPIKey", ctx, testifyMock.MatchedBy(func(key entity.APIKey) bool {
+					return key.ExpiresAt != nil && key.ExpiresAt.Location() == time.UTC &&
+						key.ExpiresAt.Equal(time.Date(2100, 10, 20, 9, 0, 0, 0, time.UTC))
+				}), testifyMock.Anything, 1).Return(7, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Error - Unknown Scope",
+			scopes: []string{"admin"},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidScope,
+		},
+		{
+			name:      "Error - Expiry In The Past",
+			scopes:    []string{entity.ScopeCoinsSend},
+			expiresAt: &past,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidExpiry,
+		},
+		{
+			name:   "Error - Employee Not Found",
+			scopes: []string{entity.ScopeCoinsSend},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "thanksbot").Return(nil, database.ErrEmployeeNotFound)
+			},
+			expectedError: service.ErrEmployeeNotFound,
+		},
+		{
+			name:   "Error - Database Error",
+			scopes: []string{entity.ScopeCoinsSend},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "thanksbot").
+					Return(&entity.Employee{ID: 5, Username: "thanksbot"}, nil)
+				mockAPIKeyRepo.On("CreateAPIKey", ctx, testifyMock.Anything, testifyMock.Anything, 1).
+					Return(0, database.ErrDatabaseInsertFailed)
+			},
+			expectedError: service.ErrAPIKeyCreationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			key, err := apiKeyService.Create(ctx, 1, "thanksbot", "slack", tt.scopes, tt.expiresAt)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, 7, key.ID)
+				assert.Equal(t, key.Prefix, key.Key[:len(key.Prefix)])
+				if key.ExpiresAt != nil {
+					assert.Equal(t, time.UTC, key.ExpiresAt.Location())
+				}
+				mockAPIKeyRepo.AssertCalled(t, "CreateAPIKey", ctx, testifyMock.Anything, hashHelper(key.Key), 1)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockAPIKeyRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockAPIKeyRepo := new(mock.MockAPIKeyRepository)
+	apiKeyService := apikeyservice.NewAPIKeyService(mockEmployeeRepo, mockAPIKeyRepo)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedKey   *entity.APIKey
+		expectedError error
+	}{
+		{
+			name: "Success - Active Key",
+			mockSetup: func() {
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.On("GetAPIKeyByHash", ctx, hashHelper("shop_key")).
+					Return(&entity.APIKey{ID: 7, EmployeeID: 5, Scopes: []string{entity.ScopeCoinsSend}, ExpiresAt: &future}, nil)
+				mockAPIKeyRepo.On("TouchAPIKey", ctx, 7).Return(nil)
+			},
+			expectedKey:   &entity.APIKey{ID: 7, EmployeeID: 5, Scopes: []string{entity.ScopeCoinsSend}, ExpiresAt: &future},
+			expectedError: nil,
+		},
+		{
+			name: "Success - Last Use Not Recorded",
+			mockSetup: func() {
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.On("GetAPIKeyByHash", ctx, hashHelper("shop_key")).
+					Return(&entity.APIKey{ID: 7, EmployeeID: 5}, nil)
+				mockAPIKeyRepo.On("TouchAPIKey", ctx, 7).Return(database.ErrDatabaseUpdateFailed)
+			},
+			expectedKey:   &entity.APIKey{ID: 7, EmployeeID: 5},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Unknown Key",
+			mockSetup: func() {
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.On("GetAPIKeyByHash", ctx, hashHelper("shop_key")).Return(nil, database.ErrAPIKeyNotFound)
+			},
+			expectedKey:   nil,
+			expectedError: service.ErrInvalidAPIKey,
+		},
+		{
+			name: "Error - Expired Key",
+			mockSetup: func() {
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.On("GetAPIKeyByHash", ctx, hashHelper("shop_key")).
+					Return(&entity.APIKey{ID: 7, EmployeeID: 5, ExpiresAt: &past}, nil)
+			},
+			expectedKey:   nil,
+			expectedError: service.ErrInvalidAPIKey,
+		},
+		{
+			name: "Error - Revoked Key",
+			mockSetup: func() {
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.On("GetAPIKeyByHash", ctx, hashHelper("shop_key")).
+					Return(&entity.APIKey{ID: 7, EmployeeID: 5, RevokedAt: &past}, nil)
+			},
+			expectedKey:   nil,
+			expectedError: service.ErrInvalidAPIKey,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockAPIKeyRepo.ExpectedCalls = nil
+				mockAPIKeyRepo.On("GetAPIKeyByHash", ctx, hashHelper("shop_key")).Return(nil, database.ErrDatabaseQueryFailed)
+			},
+			expectedKey:   nil,
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			key, err := apiKeyService.Authenticate(ctx, "shop_key")
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedKey, key)
+
+			mockAPIKeyRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockAPIKeyRepo := new(mock.MockAPIKeyRepository)
+	apiKeyService := apikeyservice.NewAPIKeyService(mockEmployeeRepo, mockAPIKeyRepo)
+
+	createdAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockAPIKeyRepo.On("ListAPIKeys", ctx).Return([]*entity.APIKey{
+		{ID: 7, EmployeeID: 5, Username: "thanksbot", Name: "slack", Prefix: "shop_abcdefg", Scopes: []string{entity.ScopeCoinsSend}, CreatedAt: createdAt},
+	}, nil)
+	mockAPIKeyRepo.On("RevokeAPIKey", ctx, 7).Return(nil)
+	mockAPIKeyRepo.On("RevokeAPIKey", ctx, 8).Return(database.ErrAPIKeyNotFound)
+
+	keys, err := apiKeyService.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.APIKey{
+		{ID: 7, Username: "thanksbot", Name: "slack", Prefix: "shop_abcdefg", Scopes: []string{entity.ScopeCoinsSend}, CreatedAt: createdAt},
+	}, keys)
+
+	assert.NoError(t, apiKeyService.Revoke(ctx, 7))
+	assert.Equal(t, service.ErrAPIKeyNotFound, apiKeyService.Revoke(ctx, 8))
+
+	mockAPIKeyRepo.AssertExpectations(t)
+}
+
+func hashHelper(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,107 +2,485 @@ package authservice
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"log"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
+// externalPasswordHash is stored for employees created through an external
+// identity provider. It is not a valid hash, so password login is impossible
+// until a password is set through a reset.
+const externalPasswordHash = "!"
+
+// RegistrationPolicy controls how new employees get an account.
+type RegistrationPolicy struct {
+	// AutoProvision creates unknown employees on their first login.
+	AutoProvision bool
+	// InviteCode, when set, must be presented on registration.
+	InviteCode string
+	// AllowedUsernames, when not empty, limits registration to these usernames.
+	AllowedUsernames []string
+}
+
 type AuthService struct {
 	employeeRepo       database.EmployeeRepository
-	jwtSecret          string
+	tokenRepo          database.TokenRepository
+	throttleService    service.LoginThrottleService
+	keys               *KeySet
+	hasher             *PasswordHasher
 	tokenTTL           time.Duration
+	refreshTokenTTL    time.Duration
+	passwordResetTTL   time.Duration
 	defaultUserBalance int
+	registration       RegistrationPolicy
 }
 
-func NewAuthService(employeeRepo database.EmployeeRepository, jwtSecret string, tokenTTL time.Duration, defaultUserBalance int) *AuthService {
+func NewAuthService(
+	employeeRepo database.EmployeeRepository,
+	tokenRepo database.TokenRepository,
+	throttleService service.LoginThrottleService,
+	keys *KeySet,
+	hasher *PasswordHasher,
+	tokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+	passwordResetTTL time.Duration,
+	defaultUserBalance int,
+	registration RegistrationPolicy,
+) *AuthService {
 	return &AuthService{
 		employeeRepo:       employeeRepo,
-		jwtSecret:          jwtSecret,
+		tokenRepo:          tokenRepo,
+		throttleService:    throttleService,
+		keys:               keys,
+		hasher:             hasher,
 		tokenTTL:           tokenTTL,
+		refreshTokenTTL:    refreshTokenTTL,
+		passwordResetTTL:   passwordResetTTL,
 		defaultUserBalance: defaultUserBalance,
+		registration:       registration,
 	}
 }
 
-func hashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+func randomToken(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
 }
 
-func checkPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+func randomID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
 }
 
-func (s *AuthService) AuthorizeUser(ctx context.Context, username, password string) (string, error) {
+// hashRefreshToken returns the form in which refresh and password reset tokens are stored.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *AuthService) AuthorizeUser(ctx context.Context, username, password string) (*dto.AuthResponse, error) {
 	employee, err := s.employeeRepo.GetEmployeeByUsername(ctx, username)
-	if err != nil {
-		passwordHash, err := hashPassword(password)
+	switch {
+	case err == database.ErrEmployeeNotFound && s.registration.AutoProvision:
+		employee, err = s.createEmployee(ctx, username, password)
 		if err != nil {
-			log.Println("failed to hash password:", err)
-			return "", service.ErrEmployeeCreationFailed
+			return nil, err
 		}
+		return s.startSession(ctx, employee)
+	case err == database.ErrEmployeeNotFound:
+		return nil, service.ErrInvalidCredentials
+	case err != nil:
+		log.Printf("failed to get employee %q: %v", username, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	ok, needsRehash := s.hasher.Verify(password, employee.PasswordHash)
+	if !ok {
+		return nil, service.ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		s.rehashPassword(ctx, employee, password)
+	}
+
+	return s.startSession(ctx, employee)
+}
+
+// rehashPassword upgrades a hash made with an outdated algorithm or cost.
+// Failures are only logged: the employee can still log in with the old hash.
+func (s *AuthService) rehashPassword(ctx context.Context, employee *entity.Employee, password string) {
+	passwordHash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Println("failed to hash password:", err)
+		return
+	}
 
-		newEmployeeID, err := s.employeeRepo.CreateEmployee(
-			ctx,
-			entity.Employee{
-				Username:     username,
-				PasswordHash: passwordHash,
-				Balance:      s.defaultUserBalance,
-			},
-		)
+	err = s.employeeRepo.UpdatePasswordHash(ctx, employee.ID, employee.PasswordHash, passwordHash)
+	if err != nil {
+		log.Printf("failed to rehash password of employee %d: %v", employee.ID, err)
+		return
+	}
+
+	employee.PasswordHash = passwordHash
+}
+
+// AuthorizeExternal signs in an employee whose identity was confirmed by an
+// external identity provider. Unknown employees are created on first sign-in.
+func (s *AuthService) AuthorizeExternal(ctx context.Context, username string) (*dto.AuthResponse, error) {
+	employee, err := s.employeeRepo.GetEmployeeByUsername(ctx, username)
+	switch {
+	case err == database.ErrEmployeeNotFound:
+		employee, err = s.insertEmployee(ctx, username, externalPasswordHash)
 		if err != nil {
-			log.Println("failed to create user:", err)
-			return "", service.ErrEmployeeCreationFailed
+			return nil, err
 		}
+	case err != nil:
+		log.Printf("failed to get employee %q: %v", username, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	return s.startSession(ctx, employee)
+}
+
+func (s *AuthService) Register(ctx context.Context, username, password, inviteCode string) (*dto.AuthResponse, error) {
+	if !s.registrationAllowed(username, inviteCode) {
+		return nil, service.ErrRegistrationNotAllowed
+	}
 
-		employee = &entity.Employee{
-			ID:           newEmployeeID,
+	employee, err := s.createEmployee(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, employee)
+}
+
+func (s *AuthService) registrationAllowed(username, inviteCode string) bool {
+	if s.registration.InviteCode != "" &&
+		subtle.ConstantTimeCompare([]byte(inviteCode), []byte(s.registration.InviteCode)) != 1 {
+		return false
+	}
+
+	if len(s.registration.AllowedUsernames) > 0 && !slices.Contains(s.registration.AllowedUsernames, username) {
+		return false
+	}
+
+	return true
+}
+
+func (s *AuthService) createEmployee(ctx context.Context, username, password string) (*entity.Employee, error) {
+	passwordHash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Println("failed to hash password:", err)
+		return nil, service.ErrEmployeeCreationFailed
+	}
+
+	return s.insertEmployee(ctx, username, passwordHash)
+}
+
+func (s *AuthService) insertEmployee(ctx context.Context, username, passwordHash string) (*entity.Employee, error) {
+	newEmployeeID, err := s.employeeRepo.CreateEmployee(
+		ctx,
+		entity.Employee{
 			Username:     username,
 			PasswordHash: passwordHash,
 			Balance:      s.defaultUserBalance,
+		},
+	)
+	if err != nil {
+		log.Println("failed to create user:", err)
+		switch err {
+		case database.ErrEmployeeAlreadyExists:
+			return nil, service.ErrEmployeeAlreadyExists
+		default:
+			return nil, service.ErrEmployeeCreationFailed
 		}
-	} else if !checkPasswordHash(password, employee.PasswordHash) {
-		return "", service.ErrInvalidCredentials
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	return &entity.Employee{
+		ID:           newEmployeeID,
+		Username:     username,
+		PasswordHash: passwordHash,
+		Balance:      s.defaultUserBalance,
+		Role:         entity.RoleEmployee,
+	}, nil
+}
+
+// startSession opens a new refresh token family and issues the first token pair.
+func (s *AuthService) startSession(ctx context.Context, employee *entity.Employee) (*dto.AuthResponse, error) {
+	familyID, err := randomID()
+	if err != nil {
+		log.Println("failed to generate refresh token family:", err)
+		return nil, service.ErrAuthenticationFailed
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		log.Println("failed to generate refresh token:", err)
+		return nil, service.ErrAuthenticationFailed
+	}
+
+	err = s.tokenRepo.CreateRefreshToken(ctx, employee.ID, familyID, hashRefreshToken(refreshToken), s.refreshTokenTTL)
+	if err != nil {
+		log.Printf("failed to store refresh token for employee %d: %v", employee.ID, err)
+		return nil, service.ErrAuthenticationFailed
+	}
+
+	return s.issueTokens(employee, refreshToken)
+}
+
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthResponse, error) {
+	newRefreshToken, err := randomToken(32)
+	if err != nil {
+		log.Println("failed to generate refresh token:", err)
+		return nil, service.ErrAuthenticationFailed
+	}
+
+	employeeID, err := s.tokenRepo.RotateRefreshToken(ctx, hashRefreshToken(refreshToken), hashRefreshToken(newRefreshToken), s.refreshTokenTTL)
+	if err != nil {
+		switch err {
+		case database.ErrRefreshTokenNotFound:
+			return nil, service.ErrInvalidRefreshToken
+		case database.ErrRefreshTokenReused:
+			log.Println("refresh token reuse detected, token family revoked")
+			return nil, service.ErrInvalidRefreshToken
+		default:
+			log.Println("failed to rotate refresh token:", err)
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	// The employee is read again so that a changed role reaches the new access token.
+	employee, err := s.employeeRepo.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		log.Printf("employee %d not found: %v", employeeID, err)
+		return nil, service.ErrInvalidRefreshToken
+	}
+
+	return s.issueTokens(employee, newRefreshToken)
+}
+
+func (s *AuthService) Logout(ctx context.Context, claims *entity.TokenClaims, refreshToken string) error {
+	err := s.tokenRepo.RevokeRefreshFamily(ctx, claims.UserID, hashRefreshToken(refreshToken))
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens of employee %d: %v", claims.UserID, err)
+		switch err {
+		case database.ErrRefreshTokenNotFound:
+			return service.ErrInvalidRefreshToken
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	if claims.JTI == "" {
+		return nil
+	}
+
+	err = s.tokenRepo.RevokeAccessToken(ctx, claims.JTI, claims.ExpiresAt)
+	if err != nil {
+		log.Printf("failed to revoke access token of employee %d: %v", claims.UserID, err)
+		return service.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// ChangePassword sets a new password after checking the current one. Wrong
+// current passwords count as failed logins of the employee.
+func (s *AuthService) ChangePassword(ctx context.Context, userID int, clientIP, currentPassword, newPassword string) error {
+	employee, err := s.employeeRepo.GetEmployeeByID(ctx, userID)
+	if err != nil {
+		log.Printf("employee %d not found: %v", userID, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return service.ErrEmployeeNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	if err := s.throttleService.Check(ctx, employee.Username, clientIP); err != nil {
+		return err
+	}
+
+	if ok, _ := s.hasher.Verify(currentPassword, employee.PasswordHash); !ok {
+		s.throttleService.RecordFailure(ctx, employee.Username, clientIP)
+		return service.ErrInvalidCredentials
+	}
+	s.throttleService.RecordSuccess(ctx, employee.Username)
+
+	return s.setPassword(ctx, userID, newPassword)
+}
+
+// IssuePasswordReset creates a one-time token that lets the employee set a new
+// password without knowing the current one.
+func (s *AuthService) IssuePasswordReset(ctx context.Context, issuerID int, username string) (*dto.PasswordResetResponse, error) {
+	employee, err := s.employeeRepo.GetEmployeeByUsername(ctx, username)
+	if err != nil {
+		log.Printf("employee %q not found: %v", username, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return nil, service.ErrEmployeeNotFound
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	resetToken, err := randomToken(32)
+	if err != nil {
+		log.Println("failed to generate password reset token:", err)
+		return nil, service.ErrAuthenticationFailed
+	}
+
+	expiresAt := time.Now().Add(s.passwordResetTTL)
+	err = s.tokenRepo.CreatePasswordResetToken(ctx, employee.ID, issuerID, hashRefreshToken(resetToken), s.passwordResetTTL)
+	if err != nil {
+		log.Printf("failed to store password reset token for employee %d: %v", employee.ID, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	return &dto.PasswordResetResponse{
+		ResetToken: resetToken,
+		ExpiresAt:  expiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (s *AuthService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	passwordHash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		log.Println("failed to hash password:", err)
+		return service.ErrPasswordUpdateFailed
+	}
+
+	err = s.tokenRepo.ResetPassword(ctx, hashRefreshToken(resetToken), passwordHash)
+	if err != nil {
+		switch err {
+		case database.ErrPasswordResetTokenNotFound:
+			return service.ErrInvalidPasswordResetToken
+		default:
+			log.Println("failed to reset password:", err)
+			return service.ErrPasswordUpdateFailed
+		}
+	}
+
+	return nil
+}
+
+// setPassword stores the new password; the repository revokes all tokens of the employee.
+func (s *AuthService) setPassword(ctx context.Context, employeeID int, password string) error {
+	passwordHash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Println("failed to hash password:", err)
+		return service.ErrPasswordUpdateFailed
+	}
+
+	err = s.employeeRepo.UpdatePassword(ctx, employeeID, passwordHash)
+	if err != nil {
+		log.Printf("failed to update password of employee %d: %v", employeeID, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return service.ErrEmployeeNotFound
+		default:
+			return service.ErrPasswordUpdateFailed
+		}
+	}
+
+	return nil
+}
+
+func (s *AuthService) issueTokens(employee *entity.Employee, refreshToken string) (*dto.AuthResponse, error) {
+	jti, err := randomID()
+	if err != nil {
+		log.Println("failed to generate token id:", err)
+		return nil, service.ErrAuthenticationFailed
+	}
+
+	tokenString, err := s.keys.sign(jwt.MapClaims{
 		"user_id": employee.ID,
+		"role":    employee.Role,
+		"jti":     jti,
+		"ver":     employee.TokenVersion,
 		"exp":     time.Now().Add(s.tokenTTL).Unix(),
 	})
-
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
 	if err != nil {
 		log.Println("failed to create jwt token:", err)
-		return "", service.ErrAuthenticationFailed
+		return nil, service.ErrAuthenticationFailed
 	}
 
-	return tokenString, nil
+	return &dto.AuthResponse{
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.tokenTTL.Seconds()),
+	}, nil
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (int, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtSecret), nil
-	})
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*entity.TokenClaims, error) {
+	token, err := jwt.Parse(tokenString, s.keys.keyFunc, jwt.WithValidMethods(s.keys.methods))
 
 	if err != nil || !token.Valid {
-		return 0, service.ErrInvalidToken
+		return nil, service.ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, service.ErrInvalidToken
+		return nil, service.ErrInvalidToken
 	}
 
 	userID, ok := claims["user_id"].(float64)
 	if !ok {
-		return 0, service.ErrInvalidToken
+		return nil, service.ErrInvalidToken
 	}
 
-	return int(userID), nil
+	// Tokens issued before roles were introduced carry no role claim.
+	role, ok := claims["role"].(string)
+	if !ok {
+		role = entity.RoleEmployee
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil, service.ErrInvalidToken
+	}
+
+	jti, _ := claims["jti"].(string)
+	// Tokens issued before password changes were introduced carry no version.
+	version, _ := claims["ver"].(float64)
+
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(ctx, jti, int(userID), int(version))
+	if err != nil {
+		log.Printf("failed to check revocation of token %q: %v", jti, err)
+		return nil, service.ErrInvalidToken
+	}
+	if revoked {
+		return nil, service.ErrInvalidToken
+	}
+
+	return &entity.TokenClaims{
+		UserID:       int(userID),
+		Role:         role,
+		JTI:          jti,
+		TokenVersion: int(version),
+		ExpiresAt:    expiresAt.Time,
+	}, nil
+}
+
+func (s *AuthService) JWKS() dto.JWKS {
+	return s.keys.jwks()
 }
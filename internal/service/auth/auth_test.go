@@ -2,29 +2,35 @@ package authservice_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
 	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
 	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 	"github.com/vit6556/avito-internship-assignment/internal/service/auth"
+	serviceMock "github.com/vit6556/avito-internship-assignment/internal/service/mock"
 	"golang.org/x/crypto/bcrypt"
 )
 
 func TestAuthorizeUser(t *testing.T) {
 	ctx := context.Background()
 	mockEmployeeRepo := new(mock.MockEmployeeRepository)
-	authService := authservice.NewAuthService(mockEmployeeRepo, "secret", time.Hour, 1000)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	autoProvision := authservice.RegistrationPolicy{AutoProvision: true}
 
 	tests := []struct {
 		name          string
 		existingUser  *entity.Employee
 		username      string
 		password      string
+		registration  authservice.RegistrationPolicy
 		mockSetup     func()
 		expectedError error
 	}{
@@ -35,8 +41,11 @@ func TestAuthorizeUser(t *testing.T) {
 			password:     "password",
 			mockSetup: func() {
 				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
 				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").
 					Return(&entity.Employee{ID: 1, Username: "alice", PasswordHash: hashPasswordHelper("password")}, nil)
+				mockTokenRepo.On("CreateRefreshToken", ctx, 1, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).
+					Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -53,16 +62,20 @@ func TestAuthorizeUser(t *testing.T) {
 			expectedError: service.ErrInvalidCredentials,
 		},
 		{
-			name:         "Success - New User Creation",
+			name:         "Success - New User Auto Provisioned",
 			existingUser: nil,
 			username:     "bob",
 			password:     "newpassword",
+			registration: autoProvision,
 			mockSetup: func() {
 				mockEmployeeRepo.ExpectedCalls = nil
 				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
-					Return(nil, service.ErrEmployeeNotFound)
+					Return(nil, database.ErrEmployeeNotFound)
 				mockEmployeeRepo.On("CreateEmployee", ctx, testifyMock.Anything).
 					Return(2, nil)
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("CreateRefreshToken", ctx, 2, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).
+					Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -71,64 +84,383 @@ func TestAuthorizeUser(t *testing.T) {
 			existingUser: nil,
 			username:     "bob",
 			password:     "newpassword",
+			registration: autoProvision,
 			mockSetup: func() {
 				mockEmployeeRepo.ExpectedCalls = nil
 				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
-					Return(nil, service.ErrEmployeeNotFound)
+					Return(nil, database.ErrEmployeeNotFound)
 				mockEmployeeRepo.On("CreateEmployee", ctx, testifyMock.Anything).
-					Return(0, service.ErrEmployeeCreationFailed)
+					Return(0, database.ErrEmployeeCreationFailed)
 			},
 			expectedError: service.ErrEmployeeCreationFailed,
 		},
+		{
+			name:         "Error - Unknown User Without Auto Provisioning",
+			existingUser: nil,
+			username:     "bob",
+			password:     "newpassword",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
+					Return(nil, database.ErrEmployeeNotFound)
+			},
+			expectedError: service.ErrInvalidCredentials,
+		},
+		{
+			name:         "Error - Database Outage Does Not Create User",
+			existingUser: nil,
+			username:     "bob",
+			password:     "newpassword",
+			registration: autoProvision,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
+					Return(nil, database.ErrDatabaseQueryFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+		{
+			name:         "Error - Failed to Store Refresh Token",
+			existingUser: &entity.Employee{ID: 1, Username: "alice", PasswordHash: hashPasswordHelper("password")},
+			username:     "alice",
+			password:     "password",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").
+					Return(&entity.Employee{ID: 1, Username: "alice", PasswordHash: hashPasswordHelper("password")}, nil)
+				mockTokenRepo.On("CreateRefreshToken", ctx, 1, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).
+					Return(database.ErrDatabaseInsertFailed)
+			},
+			expectedError: service.ErrAuthenticationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, tt.registration)
+
+			response, err := authService.AuthorizeUser(ctx, tt.username, tt.password)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.NotEmpty(t, response.Token)
+				assert.NotEmpty(t, response.RefreshToken)
+				assert.Equal(t, 3600, response.ExpiresIn)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthorizeExternal(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{})
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Existing Employee",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").
+					Return(&entity.Employee{ID: 1, Username: "alice", Role: entity.RoleEmployee}, nil)
+				mockTokenRepo.On("CreateRefreshToken", ctx, 1, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success - Employee Created Without Password",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").Return(nil, database.ErrEmployeeNotFound)
+				mockEmployeeRepo.On("CreateEmployee", ctx, testifyMock.MatchedBy(func(employee entity.Employee) bool {
+					ok, _ := bcryptHasher().Verify("", employee.PasswordHash)
+					return employee.Username == "alice" && employee.Balance == 1000 && !ok
+				})).Return(2, nil)
+				mockTokenRepo.On("CreateRefreshToken", ctx, 2, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Creation Failed",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").Return(nil, database.ErrEmployeeNotFound)
+				mockEmployeeRepo.On("CreateEmployee", ctx, testifyMock.Anything).Return(0, database.ErrEmployeeCreationFailed)
+			},
+			expectedError: service.ErrEmployeeCreationFailed,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").Return(nil, database.ErrDatabaseQueryFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			response, err := authService.AuthorizeExternal(ctx, "alice")
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.NotEmpty(t, response.Token)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTokenRepo := new(mock.MockTokenRepository)
+
+	tests := []struct {
+		name          string
+		username      string
+		inviteCode    string
+		registration  authservice.RegistrationPolicy
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:     "Success - Open Registration",
+			username: "bob",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("CreateEmployee", ctx, testifyMock.Anything).Return(2, nil)
+				mockTokenRepo.On("CreateRefreshToken", ctx, 2, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:         "Success - Valid Invite Code",
+			username:     "bob",
+			inviteCode:   "welcome",
+			registration: authservice.RegistrationPolicy{InviteCode: "welcome"},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("CreateEmployee", ctx, testifyMock.Anything).Return(2, nil)
+				mockTokenRepo.On("CreateRefreshToken", ctx, 2, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:         "Error - Wrong Invite Code",
+			username:     "bob",
+			inviteCode:   "guess",
+			registration: authservice.RegistrationPolicy{InviteCode: "welcome"},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrRegistrationNotAllowed,
+		},
+		{
+			name:         "Error - Username Not Allowed",
+			username:     "mallory",
+			registration: authservice.RegistrationPolicy{AllowedUsernames: []string{"bob", "carol"}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrRegistrationNotAllowed,
+		},
+		{
+			name:     "Error - Username Taken",
+			username: "bob",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("CreateEmployee", ctx, testifyMock.Anything).Return(0, database.ErrEmployeeAlreadyExists)
+			},
+			expectedError: service.ErrEmployeeAlreadyExists,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
+			authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, tt.registration)
 
-			token, err := authService.AuthorizeUser(ctx, tt.username, tt.password)
+			response, err := authService.Register(ctx, tt.username, "password", tt.inviteCode)
 
 			assert.Equal(t, tt.expectedError, err)
 			if tt.expectedError == nil {
-				assert.NotEmpty(t, token)
+				assert.NotEmpty(t, response.Token)
+				assert.NotEmpty(t, response.RefreshToken)
 			}
 
 			mockEmployeeRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
 		})
 	}
 }
 
 func TestValidateToken(t *testing.T) {
+	ctx := context.Background()
 	mockEmployeeRepo := new(mock.MockEmployeeRepository)
-	authService := authservice.NewAuthService(mockEmployeeRepo, "secret", time.Hour, 1000)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{})
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name           string
+		token          string
+		mockSetup      func()
+		expectedClaims *entity.TokenClaims
+		expectedError  error
+	}{
+		{
+			name:  "Success - Valid Token",
+			token: generateToken(1, entity.RoleAdmin, "token-id", expiresAt, "secret"),
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("IsAccessTokenRevoked", ctx, "token-id", 1, 0).Return(false, nil)
+			},
+			expectedClaims: &entity.TokenClaims{UserID: 1, Role: entity.RoleAdmin, JTI: "token-id", ExpiresAt: expiresAt},
+			expectedError:  nil,
+		},
+		{
+			name:  "Success - Token Without Role And ID",
+			token: generateToken(1, "", "", expiresAt, "secret"),
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("IsAccessTokenRevoked", ctx, "", 1, 0).Return(false, nil)
+			},
+			expectedClaims: &entity.TokenClaims{UserID: 1, Role: entity.RoleEmployee, ExpiresAt: expiresAt},
+			expectedError:  nil,
+		},
+		{
+			name:  "Error - Revoked Token",
+			token: generateToken(1, entity.RoleEmployee, "token-id", expiresAt, "secret"),
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("IsAccessTokenRevoked", ctx, "token-id", 1, 0).Return(true, nil)
+			},
+			expectedClaims: nil,
+			expectedError:  service.ErrInvalidToken,
+		},
+		{
+			name:  "Error - Denylist Unavailable",
+			token: generateToken(1, entity.RoleEmployee, "token-id", expiresAt, "secret"),
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("IsAccessTokenRevoked", ctx, "token-id", 1, 0).Return(false, database.ErrDatabaseQueryFailed)
+			},
+			expectedClaims: nil,
+			expectedError:  service.ErrInvalidToken,
+		},
+		{
+			name:  "Error - Invalid Token",
+			token: "invalid.token.string",
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+			},
+			expectedClaims: nil,
+			expectedError:  service.ErrInvalidToken,
+		},
+		{
+			name:  "Error - Expired Token",
+			token: generateToken(1, entity.RoleEmployee, "token-id", time.Now().Add(-time.Hour), "secret"),
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+			},
+			expectedClaims: nil,
+			expectedError:  service.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			claims, err := authService.ValidateToken(ctx, tt.token)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedClaims, claims)
+
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{})
 
 	tests := []struct {
 		name          string
-		token         string
 		mockSetup     func()
-		expectedUser  int
 		expectedError error
 	}{
 		{
-			name:          "Success - Valid Token",
-			token:         generateToken(1, "secret"),
-			mockSetup:     func() {},
-			expectedUser:  1,
+			name: "Success - Token Rotated",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("RotateRefreshToken", ctx, hashHelper("refresh-token"), testifyMock.Anything, 24*time.Hour).
+					Return(1, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Role: entity.RoleHR, TokenVersion: 2}, nil)
+				mockTokenRepo.On("IsAccessTokenRevoked", ctx, testifyMock.Anything, 1, 2).Return(false, nil)
+			},
 			expectedError: nil,
 		},
 		{
-			name:          "Error - Invalid Token",
-			token:         "invalid.token.string",
-			mockSetup:     func() {},
-			expectedUser:  0,
-			expectedError: service.ErrInvalidToken,
+			name: "Error - Unknown Token",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("RotateRefreshToken", ctx, hashHelper("refresh-token"), testifyMock.Anything, 24*time.Hour).
+					Return(0, database.ErrRefreshTokenNotFound)
+			},
+			expectedError: service.ErrInvalidRefreshToken,
 		},
 		{
-			name:          "Error - Expired Token",
-			token:         generateExpiredToken(1, "secret"),
-			mockSetup:     func() {},
-			expectedUser:  0,
-			expectedError: service.ErrInvalidToken,
+			name: "Error - Reused Token",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("RotateRefreshToken", ctx, hashHelper("refresh-token"), testifyMock.Anything, 24*time.Hour).
+					Return(0, database.ErrRefreshTokenReused)
+			},
+			expectedError: service.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("RotateRefreshToken", ctx, hashHelper("refresh-token"), testifyMock.Anything, 24*time.Hour).
+					Return(0, database.ErrDatabaseTransaction)
+			},
+			expectedError: service.ErrDatabaseError,
 		},
 	}
 
@@ -136,10 +468,298 @@ func TestValidateToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			userID, err := authService.ValidateToken(tt.token)
+			response, err := authService.RefreshToken(ctx, "refresh-token")
 
 			assert.Equal(t, tt.expectedError, err)
-			assert.Equal(t, tt.expectedUser, userID)
+			if tt.expectedError == nil {
+				assert.NotEqual(t, "refresh-token", response.RefreshToken)
+
+				claims, err := authService.ValidateToken(ctx, response.Token)
+				assert.NoError(t, err)
+				assert.Equal(t, entity.RoleHR, claims.Role)
+				assert.Equal(t, 2, claims.TokenVersion)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{})
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		claims        *entity.TokenClaims
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "Success - Tokens Revoked",
+			claims: &entity.TokenClaims{UserID: 1, JTI: "token-id", ExpiresAt: expiresAt},
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("RevokeRefreshFamily", ctx, 1, hashHelper("refresh-token")).Return(nil)
+				mockTokenRepo.On("RevokeAccessToken", ctx, "token-id", expiresAt).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Success - Legacy Access Token",
+			claims: &entity.TokenClaims{UserID: 1, ExpiresAt: expiresAt},
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("RevokeRefreshFamily", ctx, 1, hashHelper("refresh-token")).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Error - Unknown Refresh Token",
+			claims: &entity.TokenClaims{UserID: 1, JTI: "token-id", ExpiresAt: expiresAt},
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("RevokeRefreshFamily", ctx, 1, hashHelper("refresh-token")).Return(database.ErrRefreshTokenNotFound)
+			},
+			expectedError: service.ErrInvalidRefreshToken,
+		},
+		{
+			name:   "Error - Database Error",
+			claims: &entity.TokenClaims{UserID: 1, JTI: "token-id", ExpiresAt: expiresAt},
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("RevokeRefreshFamily", ctx, 1, hashHelper("refresh-token")).Return(nil)
+				mockTokenRepo.On("RevokeAccessToken", ctx, "token-id", expiresAt).Return(database.ErrDatabaseInsertFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := authService.Logout(ctx, tt.claims, "refresh-token")
+
+			assert.Equal(t, tt.expectedError, err)
+
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	mockThrottleService := new(serviceMock.MockLoginThrottleService)
+	authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, mockThrottleService, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{})
+
+	passwordHash := hashPasswordHelper("old-password")
+
+	tests := []struct {
+		name            string
+		currentPassword string
+		mockSetup       func()
+		expectedError   error
+	}{
+		{
+			name:            "Success - Password Changed",
+			currentPassword: "old-password",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockThrottleService.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", PasswordHash: passwordHash}, nil)
+				mockThrottleService.On("Check", ctx, "alice", "10.0.0.1").Return(nil)
+				mockThrottleService.On("RecordSuccess", ctx, "alice").Return()
+				mockEmployeeRepo.On("UpdatePassword", ctx, 1, testifyMock.MatchedBy(func(hash string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+				})).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "Error - Wrong Current Password",
+			currentPassword: "wrong-password",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockThrottleService.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", PasswordHash: passwordHash}, nil)
+				mockThrottleService.On("Check", ctx, "alice", "10.0.0.1").Return(nil)
+				mockThrottleService.On("RecordFailure", ctx, "alice", "10.0.0.1").Return()
+			},
+			expectedError: service.ErrInvalidCredentials,
+		},
+		{
+			name:            "Error - Locked Out",
+			currentPassword: "old-password",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockThrottleService.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", PasswordHash: passwordHash}, nil)
+				mockThrottleService.On("Check", ctx, "alice", "10.0.0.1").Return(service.ErrTooManyLoginAttempts)
+			},
+			expectedError: service.ErrTooManyLoginAttempts,
+		},
+		{
+			name:            "Error - Employee Not Found",
+			currentPassword: "old-password",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockThrottleService.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(nil, database.ErrEmployeeNotFound)
+			},
+			expectedError: service.ErrEmployeeNotFound,
+		},
+		{
+			name:            "Error - Update Failed",
+			currentPassword: "old-password",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockThrottleService.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", PasswordHash: passwordHash}, nil)
+				mockThrottleService.On("Check", ctx, "alice", "10.0.0.1").Return(nil)
+				mockThrottleService.On("RecordSuccess", ctx, "alice").Return()
+				mockEmployeeRepo.On("UpdatePassword", ctx, 1, testifyMock.Anything).Return(database.ErrDatabaseTransaction)
+			},
+			expectedError: service.ErrPasswordUpdateFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := authService.ChangePassword(ctx, 1, "10.0.0.1", tt.currentPassword, "new-password")
+
+			assert.Equal(t, tt.expectedError, err)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockThrottleService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssuePasswordReset(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{})
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Reset Token Issued",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").Return(&entity.Employee{ID: 2, Username: "alice"}, nil)
+				mockTokenRepo.On("CreatePasswordResetToken", ctx, 2, 1, testifyMock.Anything, time.Hour).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Employee Not Found",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").Return(nil, database.ErrEmployeeNotFound)
+			},
+			expectedError: service.ErrEmployeeNotFound,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTokenRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").Return(&entity.Employee{ID: 2, Username: "alice"}, nil)
+				mockTokenRepo.On("CreatePasswordResetToken", ctx, 2, 1, testifyMock.Anything, time.Hour).Return(database.ErrDatabaseInsertFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			response, err := authService.IssuePasswordReset(ctx, 1, "alice")
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.NotEmpty(t, response.ResetToken)
+				mockTokenRepo.AssertCalled(t, "CreatePasswordResetToken", ctx, 2, 1, hashHelper(response.ResetToken), time.Hour)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{})
+
+	newPasswordHash := testifyMock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+	})
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Password Reset",
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("ResetPassword", ctx, hashHelper("reset-token"), newPasswordHash).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Invalid Reset Token",
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("ResetPassword", ctx, hashHelper("reset-token"), newPasswordHash).Return(database.ErrPasswordResetTokenNotFound)
+			},
+			expectedError: service.ErrInvalidPasswordResetToken,
+		},
+		{
+			name: "Error - Update Failed",
+			mockSetup: func() {
+				mockTokenRepo.ExpectedCalls = nil
+				mockTokenRepo.On("ResetPassword", ctx, hashHelper("reset-token"), newPasswordHash).Return(database.ErrDatabaseUpdateFailed)
+			},
+			expectedError: service.ErrPasswordUpdateFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := authService.ResetPassword(ctx, "reset-token", "new-password")
+
+			assert.Equal(t, tt.expectedError, err)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -149,20 +769,35 @@ func hashPasswordHelper(password string) string {
 	return string(hash)
 }
 
-func generateToken(userID int, secret string) string {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour).Unix(),
-	})
-	tokenString, _ := token.SignedString([]byte(secret))
-	return tokenString
+func hmacKeys(secret string) *authservice.KeySet {
+	keys, _ := authservice.NewKeySet("", authservice.NewHMACKey(secret))
+	return keys
+}
+
+// bcryptHasher matches hashPasswordHelper, so logins in tests do not trigger a rehash.
+func bcryptHasher() *authservice.PasswordHasher {
+	hasher, _ := authservice.NewPasswordHasher(authservice.HashingParams{Algorithm: authservice.AlgorithmBcrypt, BcryptCost: bcrypt.DefaultCost})
+	return hasher
+}
+
+func hashHelper(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
-func generateExpiredToken(userID int, secret string) string {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+func generateToken(userID int, role, jti string, expiresAt time.Time, secret string) string {
+	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(-time.Hour).Unix(),
-	})
+		"exp":     expiresAt.Unix(),
+	}
+	if role != "" {
+		claims["role"] = role
+	}
+	if jti != "" {
+		claims["jti"] = jti
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, _ := token.SignedString([]byte(secret))
 	return tokenString
 }
@@ -0,0 +1,221 @@
+package authservice
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+)
+
+var (
+	errUnsupportedKey = errors.New("unsupported key type, expected RSA or Ed25519")
+	errInvalidPEM     = errors.New("failed to decode PEM block")
+)
+
+// SigningKey is a single JWT key identified by its kid. Keys without a private
+// part only verify tokens; they are kept around while tokens signed with them
+// are still valid after a rotation.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+}
+
+// LoadSigningKey reads a PEM encoded RSA or Ed25519 key pair. Either path may be
+// empty: the public key is derived from the private one, and a key loaded only
+// from a public key can verify but not sign.
+func LoadSigningKey(id, privateKeyPath, publicKeyPath string) (*SigningKey, error) {
+	key := &SigningKey{ID: id}
+
+	if privateKeyPath != "" {
+		privateKey, err := readPrivateKey(privateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		switch k := privateKey.(type) {
+		case *rsa.PrivateKey:
+			key.privateKey, key.publicKey = k, k.Public()
+		case ed25519.PrivateKey:
+			key.privateKey, key.publicKey = k, k.Public()
+		default:
+			return nil, fmt.Errorf("key %q: %w", id, errUnsupportedKey)
+		}
+	}
+
+	if publicKeyPath != "" {
+		publicKey, err := readPublicKey(publicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		key.publicKey = publicKey
+	}
+
+	switch key.publicKey.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	case nil:
+		return nil, fmt.Errorf("key %q: no private or public key given", id)
+	default:
+		return nil, fmt.Errorf("key %q: %w", id, errUnsupportedKey)
+	}
+
+	return key, nil
+}
+
+// NewHMACKey returns the legacy shared secret key. It is used only when no
+// asymmetric keys are configured and is never published in the JWKS.
+func NewHMACKey(secret string) *SigningKey {
+	return &SigningKey{
+		Method:     jwt.SigningMethodHS256,
+		privateKey: []byte(secret),
+		publicKey:  []byte(secret),
+	}
+}
+
+func (k *SigningKey) canSign() bool {
+	return k.privateKey != nil
+}
+
+// KeySet holds every key accepted for verification and the one used for signing.
+type KeySet struct {
+	signing *SigningKey
+	keys    map[string]*SigningKey
+	ordered []*SigningKey
+	methods []string
+}
+
+func NewKeySet(signingKeyID string, keys ...*SigningKey) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*SigningKey, len(keys))}
+
+	seenMethods := make(map[string]bool)
+	for _, key := range keys {
+		if _, ok := set.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		set.keys[key.ID] = key
+		set.ordered = append(set.ordered, key)
+
+		if !seenMethods[key.Method.Alg()] {
+			seenMethods[key.Method.Alg()] = true
+			set.methods = append(set.methods, key.Method.Alg())
+		}
+	}
+
+	signing, ok := set.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not configured", signingKeyID)
+	}
+	if !signing.canSign() {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKeyID)
+	}
+	set.signing = signing
+
+	return set, nil
+}
+
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	if s.signing.ID != "" {
+		token.Header["kid"] = s.signing.ID
+	}
+
+	return token.SignedString(s.signing.privateKey)
+}
+
+// keyFunc picks the verification key by kid and refuses tokens whose algorithm
+// differs from the one of that key.
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), kid)
+	}
+
+	return key.publicKey, nil
+}
+
+func (s *KeySet) jwks() dto.JWKS {
+	jwks := dto.JWKS{Keys: make([]dto.JWK, 0, len(s.keys))}
+	for _, key := range s.ordered {
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, dto.JWK{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, dto.JWK{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	return jwks
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errInvalidPEM
+	}
+
+	return block, nil
+}
+
+func readPrivateKey(path string) (crypto.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
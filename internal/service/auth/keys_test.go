@@ -0,0 +1,220 @@
+package authservice_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/auth"
+)
+
+func TestAsymmetricSigning(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaPrivatePath := writePrivateKey(t, dir, "rsa.pem", rsaKey)
+	rsaPublicPath := writePublicKey(t, dir, "rsa.pub", &rsaKey.PublicKey)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edPrivatePath := writePrivateKey(t, dir, "ed.pem", edKey)
+
+	tests := []struct {
+		name         string
+		signingKeyID string
+		keys         func() []*authservice.SigningKey
+	}{
+		{
+			name:         "RS256",
+			signingKeyID: "rsa-1",
+			keys: func() []*authservice.SigningKey {
+				key, err := authservice.LoadSigningKey("rsa-1", rsaPrivatePath, "")
+				require.NoError(t, err)
+				return []*authservice.SigningKey{key}
+			},
+		},
+		{
+			name:         "EdDSA",
+			signingKeyID: "ed-1",
+			keys: func() []*authservice.SigningKey {
+				key, err := authservice.LoadSigningKey("ed-1", edPrivatePath, "")
+				require.NoError(t, err)
+				return []*authservice.SigningKey{key}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := authservice.NewKeySet(tt.signingKeyID, tt.keys()...)
+			require.NoError(t, err)
+
+			authService, mockTokenRepo := newAuthServiceWithKeys(keys)
+			response := authorize(t, ctx, authService, mockTokenRepo)
+
+			token, _, err := jwt.NewParser().ParseUnverified(response.Token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.signingKeyID, token.Header["kid"])
+
+			claims, err := authService.ValidateToken(ctx, response.Token)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, claims.UserID)
+		})
+	}
+
+	t.Run("Rotated key still verifies", func(t *testing.T) {
+		oldKey, err := authservice.LoadSigningKey("rsa-1", rsaPrivatePath, "")
+		require.NoError(t, err)
+		oldKeys, err := authservice.NewKeySet("rsa-1", oldKey)
+		require.NoError(t, err)
+
+		oldService, mockTokenRepo := newAuthServiceWithKeys(oldKeys)
+		response := authorize(t, ctx, oldService, mockTokenRepo)
+
+		retiredKey, err := authservice.LoadSigningKey("rsa-1", "", rsaPublicPath)
+		require.NoError(t, err)
+		newKey, err := authservice.LoadSigningKey("ed-1", edPrivatePath, "")
+		require.NoError(t, err)
+		newKeys, err := authservice.NewKeySet("ed-1", newKey, retiredKey)
+		require.NoError(t, err)
+
+		newService, mockTokenRepo := newAuthServiceWithKeys(newKeys)
+		mockTokenRepo.On("IsAccessTokenRevoked", ctx, testifyMock.Anything, testifyMock.Anything, testifyMock.Anything).Return(false, nil)
+
+		claims, err := newService.ValidateToken(ctx, response.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, claims.UserID)
+	})
+
+	t.Run("Verification-only key cannot sign", func(t *testing.T) {
+		retiredKey, err := authservice.LoadSigningKey("rsa-1", "", rsaPublicPath)
+		require.NoError(t, err)
+
+		_, err = authservice.NewKeySet("rsa-1", retiredKey)
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects foreign algorithms and unknown keys", func(t *testing.T) {
+		key, err := authservice.LoadSigningKey("rsa-1", rsaPrivatePath, "")
+		require.NoError(t, err)
+		keys, err := authservice.NewKeySet("rsa-1", key)
+		require.NoError(t, err)
+		authService, _ := newAuthServiceWithKeys(keys)
+
+		publicPEM, err := os.ReadFile(rsaPublicPath)
+		require.NoError(t, err)
+
+		claims := jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()}
+
+		// HS256 signed with the public key must not pass as an RS256 token.
+		confused := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		confused.Header["kid"] = "rsa-1"
+		confusedString, err := confused.SignedString(publicPEM)
+		require.NoError(t, err)
+
+		unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+		unsigned.Header["kid"] = "rsa-1"
+		unsignedString, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		unknown.Header["kid"] = "rsa-2"
+		unknownString, err := unknown.SignedString(rsaKey)
+		require.NoError(t, err)
+
+		for _, tokenString := range []string{confusedString, unsignedString, unknownString} {
+			_, err := authService.ValidateToken(ctx, tokenString)
+			assert.Equal(t, service.ErrInvalidToken, err)
+		}
+	})
+}
+
+func TestJWKS(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaSigningKey, err := authservice.LoadSigningKey("rsa-1", writePrivateKey(t, dir, "rsa.pem", rsaKey), "")
+	require.NoError(t, err)
+	edSigningKey, err := authservice.LoadSigningKey("ed-1", writePrivateKey(t, dir, "ed.pem", edKey), "")
+	require.NoError(t, err)
+
+	keys, err := authservice.NewKeySet("ed-1", rsaSigningKey, edSigningKey)
+	require.NoError(t, err)
+	authService, _ := newAuthServiceWithKeys(keys)
+
+	jwks := authService.JWKS()
+
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+	assert.Equal(t, "rsa-1", jwks.Keys[0].Kid)
+	assert.Equal(t, "RS256", jwks.Keys[0].Alg)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+	assert.NotEmpty(t, jwks.Keys[0].N)
+	assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+	assert.Equal(t, "ed-1", jwks.Keys[1].Kid)
+	assert.Equal(t, "EdDSA", jwks.Keys[1].Alg)
+	assert.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+	assert.NotEmpty(t, jwks.Keys[1].X)
+
+	hmacService, _ := newAuthServiceWithKeys(hmacKeys("secret"))
+	assert.Empty(t, hmacService.JWKS().Keys)
+}
+
+func newAuthServiceWithKeys(keys *authservice.KeySet) (*authservice.AuthService, *mock.MockTokenRepository) {
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTokenRepo := new(mock.MockTokenRepository)
+	mockEmployeeRepo.On("GetEmployeeByUsername", testifyMock.Anything, "alice").
+		Return(&entity.Employee{ID: 1, Username: "alice", PasswordHash: hashPasswordHelper("password")}, nil)
+
+	return authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, keys, bcryptHasher(), time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{}), mockTokenRepo
+}
+
+func authorize(t *testing.T, ctx context.Context, authService *authservice.AuthService, mockTokenRepo *mock.MockTokenRepository) *dto.AuthResponse {
+	mockTokenRepo.On("CreateRefreshToken", ctx, 1, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).Return(nil)
+	mockTokenRepo.On("IsAccessTokenRevoked", ctx, testifyMock.Anything, testifyMock.Anything, testifyMock.Anything).Return(false, nil)
+
+	response, err := authService.AuthorizeUser(ctx, "alice", "password")
+	require.NoError(t, err)
+
+	return response
+}
+
+func writePrivateKey(t *testing.T, dir, name string, key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	return path
+}
+
+func writePublicKey(t *testing.T, dir, name string, key interface{}) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+	return path
+}
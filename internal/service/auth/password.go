@@ -0,0 +1,149 @@
+package authservice
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var errMalformedHash = errors.New("malformed password hash")
+
+// HashingParams selects the algorithm used for new password hashes and its
+// cost. Argon2id parameters are ignored for bcrypt and vice versa.
+type HashingParams struct {
+	Algorithm string
+	// Memory is the argon2id memory cost in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+	BcryptCost  int
+}
+
+// PasswordHasher produces PHC-formatted argon2id hashes or bcrypt hashes and
+// verifies both, so hashes of different algorithms can coexist.
+type PasswordHasher struct {
+	params HashingParams
+}
+
+func NewPasswordHasher(params HashingParams) (*PasswordHasher, error) {
+	switch params.Algorithm {
+	case AlgorithmArgon2id:
+		if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 ||
+			params.SaltLength == 0 || params.KeyLength == 0 {
+			return nil, errors.New("argon2id parameters must be positive")
+		}
+	case AlgorithmBcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm %q", params.Algorithm)
+	}
+
+	return &PasswordHasher{params: params}, nil
+}
+
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.params.Algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.params.BcryptCost)
+		return string(hash), err
+	}
+
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks the password against the stored hash. needsRehash is set when
+// the password matches but the hash was made with another algorithm or with
+// parameters that differ from the configured ones.
+func (h *PasswordHasher) Verify(password, encoded string) (ok bool, needsRehash bool) {
+	if strings.HasPrefix(encoded, "$argon2id$") {
+		hash, err := parseArgon2id(encoded)
+		if err != nil {
+			return false, false
+		}
+
+		key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+		if subtle.ConstantTimeCompare(key, hash.key) != 1 {
+			return false, false
+		}
+
+		return true, h.params.Algorithm != AlgorithmArgon2id ||
+			hash.memory != h.params.Memory ||
+			hash.iterations != h.params.Iterations ||
+			hash.parallelism != h.params.Parallelism ||
+			uint32(len(hash.salt)) != h.params.SaltLength ||
+			uint32(len(hash.key)) != h.params.KeyLength
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)); err != nil {
+		return false, false
+	}
+
+	if h.params.Algorithm != AlgorithmBcrypt {
+		return true, true
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return true, err != nil || cost != h.params.BcryptCost
+}
+
+type argon2idHash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// parseArgon2id decodes a hash in the form
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>.
+func parseArgon2id(encoded string) (*argon2idHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return nil, errMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, errMalformedHash
+	}
+
+	var hash argon2idHash
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism); err != nil {
+		return nil, errMalformedHash
+	}
+
+	var err error
+	if hash.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, errMalformedHash
+	}
+	if hash.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(hash.key) == 0 {
+		return nil, errMalformedHash
+	}
+
+	return &hash, nil
+}
@@ -0,0 +1,170 @@
+package authservice_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service/auth"
+)
+
+var testArgon2Params = authservice.HashingParams{
+	Algorithm:   authservice.AlgorithmArgon2id,
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestPasswordHasher(t *testing.T) {
+	argon2Hasher, err := authservice.NewPasswordHasher(testArgon2Params)
+	require.NoError(t, err)
+
+	strongerParams := testArgon2Params
+	strongerParams.Iterations = 2
+	strongerHasher, err := authservice.NewPasswordHasher(strongerParams)
+	require.NoError(t, err)
+
+	argon2Hash, err := argon2Hasher.Hash("password")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(argon2Hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	bcryptHash := hashPasswordHelper("password")
+
+	tests := []struct {
+		name                string
+		hasher              *authservice.PasswordHasher
+		password            string
+		hash                string
+		expectedOK          bool
+		expectedNeedsRehash bool
+	}{
+		{
+			name:                "Argon2id - Current Parameters",
+			hasher:              argon2Hasher,
+			password:            "password",
+			hash:                argon2Hash,
+			expectedOK:          true,
+			expectedNeedsRehash: false,
+		},
+		{
+			name:                "Argon2id - Wrong Password",
+			hasher:              argon2Hasher,
+			password:            "wrong",
+			hash:                argon2Hash,
+			expectedOK:          false,
+			expectedNeedsRehash: false,
+		},
+		{
+			name:                "Argon2id - Outdated Parameters",
+			hasher:              strongerHasher,
+			password:            "password",
+			hash:                argon2Hash,
+			expectedOK:          true,
+			expectedNeedsRehash: true,
+		},
+		{
+			name:                "Bcrypt - Legacy Hash",
+			hasher:              argon2Hasher,
+			password:            "password",
+			hash:                bcryptHash,
+			expectedOK:          true,
+			expectedNeedsRehash: true,
+		},
+		{
+			name:                "Bcrypt - Configured Algorithm",
+			hasher:              bcryptHasher(),
+			password:            "password",
+			hash:                bcryptHash,
+			expectedOK:          true,
+			expectedNeedsRehash: false,
+		},
+		{
+			name:                "Malformed Hash",
+			hasher:              argon2Hasher,
+			password:            "password",
+			hash:                "$argon2id$v=19$m=1024$salt",
+			expectedOK:          false,
+			expectedNeedsRehash: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash := tt.hasher.Verify(tt.password, tt.hash)
+
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedNeedsRehash, needsRehash)
+		})
+	}
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	_, err := authservice.NewPasswordHasher(authservice.HashingParams{Algorithm: "md5"})
+	assert.Error(t, err)
+
+	_, err = authservice.NewPasswordHasher(authservice.HashingParams{Algorithm: authservice.AlgorithmArgon2id})
+	assert.Error(t, err)
+
+	_, err = authservice.NewPasswordHasher(authservice.HashingParams{Algorithm: authservice.AlgorithmBcrypt, BcryptCost: 1})
+	assert.Error(t, err)
+}
+
+func TestRehashOnLogin(t *testing.T) {
+	ctx := context.Background()
+	hasher, err := authservice.NewPasswordHasher(testArgon2Params)
+	require.NoError(t, err)
+
+	legacyHash := hashPasswordHelper("password")
+
+	tests := []struct {
+		name      string
+		mockSetup func(mockEmployeeRepo *mock.MockEmployeeRepository)
+	}{
+		{
+			name: "Legacy Hash Upgraded",
+			mockSetup: func(mockEmployeeRepo *mock.MockEmployeeRepository) {
+				mockEmployeeRepo.On("UpdatePasswordHash", ctx, 1, legacyHash, testifyMock.MatchedBy(func(hash string) bool {
+					ok, needsRehash := hasher.Verify("password", hash)
+					return ok && !needsRehash
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Upgrade Failure Does Not Block Login",
+			mockSetup: func(mockEmployeeRepo *mock.MockEmployeeRepository) {
+				mockEmployeeRepo.On("UpdatePasswordHash", ctx, 1, legacyHash, testifyMock.Anything).
+					Return(database.ErrDatabaseUpdateFailed)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEmployeeRepo := new(mock.MockEmployeeRepository)
+			mockTokenRepo := new(mock.MockTokenRepository)
+			authService := authservice.NewAuthService(mockEmployeeRepo, mockTokenRepo, nil, hmacKeys("secret"), hasher, time.Hour, 24*time.Hour, time.Hour, 1000, authservice.RegistrationPolicy{})
+
+			mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").
+				Return(&entity.Employee{ID: 1, Username: "alice", PasswordHash: legacyHash}, nil)
+			mockTokenRepo.On("CreateRefreshToken", ctx, 1, testifyMock.Anything, testifyMock.Anything, 24*time.Hour).Return(nil)
+			tt.mockSetup(mockEmployeeRepo)
+
+			response, err := authService.AuthorizeUser(ctx, "alice", "password")
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, response.Token)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
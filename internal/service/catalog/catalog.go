@@ -0,0 +1,177 @@
+package catalogservice
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+const maxCategoryLength = 64
+
+type MerchCatalogService struct {
+	merchRepo     database.MerchRepository
+	promotionRepo database.PromotionRepository
+}
+
+func NewMerchCatalogService(merchRepo database.MerchRepository, promotionRepo database.PromotionRepository) *MerchCatalogService {
+	return &MerchCatalogService{
+		merchRepo:     merchRepo,
+		promotionRepo: promotionRepo,
+	}
+}
+
+func (s *MerchCatalogService) ListItems(ctx context.Context) ([]dto.MerchItem, error) {
+	items, err := s.merchRepo.ListItems(ctx)
+	if err != nil {
+		log.Printf("failed to list merch: %v", err)
+		return nil, service.ErrDatabaseError
+	}
+
+	promotions, err := s.promotionRepo.ListActivePromotions(ctx, "")
+	if err != nil {
+		log.Printf("failed to list active promotions: %v", err)
+		return nil, service.ErrDatabaseError
+	}
+
+	catalog := make([]dto.MerchItem, len(items))
+	for i, item := range items {
+		catalog[i] = mapMerchItemToDTO(item)
+		applyPromotion(&catalog[i], item, promotions)
+	}
+
+	return catalog, nil
+}
+
+func (s *MerchCatalogService) GetItem(ctx context.Context, name string) (*dto.MerchItem, error) {
+	item, err := s.merchRepo.GetItemByName(ctx, name)
+	if err != nil {
+		log.Printf("merch %q not found: %v", name, err)
+		return nil, service.ErrMerchNotFound
+	}
+
+	promotions, err := s.promotionRepo.ListActivePromotions(ctx, "")
+	if err != nil {
+		log.Printf("failed to list active promotions: %v", err)
+		return nil, service.ErrDatabaseError
+	}
+
+	result := mapMerchItemToDTO(item)
+	applyPromotion(&result, item, promotions)
+	return &result, nil
+}
+
+func (s *MerchCatalogService) CreateItem(ctx context.Context, name string, price int, stock *int, category string) (*dto.MerchItem, error) {
+	category = strings.TrimSpace(category)
+	if utf8.RuneCountInString(category) > maxCategoryLength {
+		return nil, service.ErrInvalidMerchCategory
+	}
+
+	_, err := s.merchRepo.CreateItem(ctx, entity.MerchItem{Name: name, Price: price, Category: category, Stock: stock})
+	if err != nil {
+		log.Printf("failed to create merch %q: %v", name, err)
+		switch err {
+		case database.ErrMerchAlreadyExists:
+			return nil, service.ErrMerchAlreadyExists
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	return &dto.MerchItem{Name: name, Price: price, Category: category, Stock: stock}, nil
+}
+
+func (s *MerchCatalogService) UpdatePrice(ctx context.Context, name string, price int) (*dto.MerchItem, error) {
+	item, err := s.merchRepo.UpdateItemPrice(ctx, name, price)
+	if err != nil {
+		log.Printf("failed to update price of merch %q: %v", name, err)
+		switch err {
+		case database.ErrMerchNotFound:
+			return nil, service.ErrMerchNotFound
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	result := mapMerchItemToDTO(item)
+	return &result, nil
+}
+
+// SetCategory moves the item to category; an empty category removes it from
+// its current one.
+func (s *MerchCatalogService) SetCategory(ctx context.Context, name, category string) error {
+	category = strings.TrimSpace(category)
+	if utf8.RuneCountInString(category) > maxCategoryLength {
+		return service.ErrInvalidMerchCategory
+	}
+
+	err := s.merchRepo.UpdateItemCategory(ctx, name, category)
+	if err != nil {
+		log.Printf("failed to set category of merch %q: %v", name, err)
+		switch err {
+		case database.ErrMerchNotFound:
+			return service.ErrMerchNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	return nil
+}
+
+func (s *MerchCatalogService) RetireItem(ctx context.Context, name string) error {
+	err := s.merchRepo.RetireItem(ctx, name)
+	if err != nil {
+		log.Printf("failed to retire merch %q: %v", name, err)
+		switch err {
+		case database.ErrMerchNotFound:
+			return service.ErrMerchNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	return nil
+}
+
+func (s *MerchCatalogService) Restock(ctx context.Context, name string, quantity int) (*dto.MerchItem, error) {
+	item, err := s.merchRepo.RestockItem(ctx, name, quantity)
+	if err != nil {
+		log.Printf("failed to restock merch %q: %v", name, err)
+		switch err {
+		case database.ErrMerchNotFound:
+			return nil, service.ErrMerchNotFound
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	result := mapMerchItemToDTO(item)
+	return &result, nil
+}
+
+func mapMerchItemToDTO(item *entity.MerchItem) dto.MerchItem {
+	return dto.MerchItem{
+		Name:     item.Name,
+		Price:    item.Price,
+		Category: item.Category,
+		Stock:    item.Stock,
+	}
+}
+
+// applyPromotion shows the price of the best promotion without a code. Promo
+// codes are not advertised in the catalog.
+func applyPromotion(result *dto.MerchItem, item *entity.MerchItem, promotions []*entity.Promotion) {
+	price, promotion := entity.BestPrice(*item, promotions)
+	if promotion == nil {
+		return
+	}
+
+	result.PromoPrice = &price
+	result.PromoEndsAt = &promotion.EndsAt
+}
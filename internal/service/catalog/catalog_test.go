@@ -0,0 +1,310 @@
+package catalogservice_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/catalog"
+)
+
+func TestListItems(t *testing.T) {
+	ctx := context.Background()
+	mockMerchRepo := new(mock.MockMerchRepository)
+	mockPromotionRepo := new(mock.MockPromotionRepository)
+	catalogService := catalogservice.NewMerchCatalogService(mockMerchRepo, mockPromotionRepo)
+
+	bookID := 1
+	endsAt := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	promoPrice := 45
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedItems []dto.MerchItem
+		expectedError error
+	}{
+		{
+			name: "Success - List Items",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("ListItems", ctx).
+					Return([]*entity.MerchItem{{ID: 1, Name: "book", Price: 50}, {ID: 2, Name: "cup", Price: 20, Category: "kitchen"}}, nil)
+				mockPromotionRepo.ExpectedCalls = nil
+				mockPromotionRepo.On("ListActivePromotions", ctx, "").
+					Return([]*entity.Promotion{{ID: 4, DiscountType: entity.DiscountPercent, Discount: 10, ItemID: &bookID, EndsAt: endsAt}}, nil)
+			},
+			expectedItems: []dto.MerchItem{
+				{Name: "book", Price: 50, PromoPrice: &promoPrice, PromoEndsAt: &endsAt},
+				{Name: "cup", Price: 20, Category: "kitchen"},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("ListItems", ctx).
+					Return(nil, database.ErrDatabaseQueryFailed)
+			},
+			expectedItems: nil,
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			items, err := catalogService.ListItems(ctx)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedItems, items)
+
+			mockMerchRepo.AssertExpectations(t)
+			mockPromotionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateItem(t *testing.T) {
+	ctx := context.Background()
+	mockMerchRepo := new(mock.MockMerchRepository)
+	catalogService := catalogservice.NewMerchCatalogService(mockMerchRepo, new(mock.MockPromotionRepository))
+
+	stock := 20
+
+	tests := []struct {
+		name          string
+		stock         *int
+		category      string
+		mockSetup     func()
+		expectedItem  *dto.MerchItem
+		expectedError error
+	}{
+		{
+			name: "Success - Item Created",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("CreateItem", ctx, entity.MerchItem{Name: "sticker", Price: 5}).
+					Return(11, nil)
+			},
+			expectedItem:  &dto.MerchItem{Name: "sticker", Price: 5},
+			expectedError: nil,
+		},
+		{
+			name:  "Success - Item Created With Stock",
+			stock: &stock,
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("CreateItem", ctx, entity.MerchItem{Name: "sticker", Price: 5, Stock: &stock}).
+					Return(11, nil)
+			},
+			expectedItem:  &dto.MerchItem{Name: "sticker", Price: 5, Stock: &stock},
+			expectedError: nil,
+		},
+		{
+			name:     "Success - Item Created In Category",
+			category: " stationery ",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("CreateItem", ctx, entity.MerchItem{Name: "sticker", Price: 5, Category: "stationery"}).
+					Return(11, nil)
+			},
+			expectedItem:  &dto.MerchItem{Name: "sticker", Price: 5, Category: "stationery"},
+			expectedError: nil,
+		},
+		{
+			name:          "Error - Category Too Long",
+			category:      strings.Repeat("c", 65),
+			mockSetup:     func() { mockMerchRepo.ExpectedCalls = nil },
+			expectedItem:  nil,
+			expectedError: service.ErrInvalidMerchCategory,
+		},
+		{
+			name: "Error - Item Already Exists",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("CreateItem", ctx, entity.MerchItem{Name: "sticker", Price: 5}).
+					Return(0, database.ErrMerchAlreadyExists)
+			},
+			expectedItem:  nil,
+			expectedError: service.ErrMerchAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			item, err := catalogService.CreateItem(ctx, "sticker", 5, tt.stock, tt.category)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedItem, item)
+
+			mockMerchRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdatePrice(t *testing.T) {
+	ctx := context.Background()
+	mockMerchRepo := new(mock.MockMerchRepository)
+	catalogService := catalogservice.NewMerchCatalogService(mockMerchRepo, new(mock.MockPromotionRepository))
+
+	bookStock := 4
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedItem  *dto.MerchItem
+		expectedError error
+	}{
+		{
+			name: "Success - Price Updated",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("UpdateItemPrice", ctx, "book", 70).
+					Return(&entity.MerchItem{ID: 3, Name: "book", Price: 70, Category: "office", Stock: &bookStock}, nil)
+			},
+			expectedItem:  &dto.MerchItem{Name: "book", Price: 70, Category: "office", Stock: &bookStock},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Item Not Found",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("UpdateItemPrice", ctx, "book", 70).
+					Return(nil, database.ErrMerchNotFound)
+			},
+			expectedItem:  nil,
+			expectedError: service.ErrMerchNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			item, err := catalogService.UpdatePrice(ctx, "book", 70)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedItem, item)
+
+			mockMerchRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetireItem(t *testing.T) {
+	ctx := context.Background()
+	mockMerchRepo := new(mock.MockMerchRepository)
+	catalogService := catalogservice.NewMerchCatalogService(mockMerchRepo, new(mock.MockPromotionRepository))
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Item Retired",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("RetireItem", ctx, "book").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Item Not Found",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("RetireItem", ctx, "book").Return(database.ErrMerchNotFound)
+			},
+			expectedError: service.ErrMerchNotFound,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("RetireItem", ctx, "book").Return(database.ErrDatabaseUpdateFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := catalogService.RetireItem(ctx, "book")
+
+			assert.Equal(t, tt.expectedError, err)
+
+			mockMerchRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestock(t *testing.T) {
+	ctx := context.Background()
+	mockMerchRepo := new(mock.MockMerchRepository)
+	catalogService := catalogservice.NewMerchCatalogService(mockMerchRepo, new(mock.MockPromotionRepository))
+
+	stock := 15
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedItem  *dto.MerchItem
+		expectedError error
+	}{
+		{
+			name: "Success - Item Restocked",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("RestockItem", ctx, "hoodie", 10).
+					Return(&entity.MerchItem{ID: 3, Name: "hoodie", Price: 300, Stock: &stock}, nil)
+			},
+			expectedItem:  &dto.MerchItem{Name: "hoodie", Price: 300, Stock: &stock},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Item Not Found",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("RestockItem", ctx, "hoodie", 10).Return(nil, database.ErrMerchNotFound)
+			},
+			expectedItem:  nil,
+			expectedError: service.ErrMerchNotFound,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockMerchRepo.On("RestockItem", ctx, "hoodie", 10).Return(nil, database.ErrDatabaseUpdateFailed)
+			},
+			expectedItem:  nil,
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			item, err := catalogService.Restock(ctx, "hoodie", 10)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedItem, item)
+
+			mockMerchRepo.AssertExpectations(t)
+		})
+	}
+}
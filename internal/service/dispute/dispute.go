@@ -0,0 +1,166 @@
+package disputeservice
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+const maxTextLength = 500
+
+// DisputeService lets senders dispute their transfers and admins settle the
+// disputes. An approved dispute is paid back with a reversal transaction; the
+// disputed transfer itself is never changed.
+type DisputeService struct {
+	disputeRepo database.DisputeRepository
+}
+
+func NewDisputeService(disputeRepo database.DisputeRepository) *DisputeService {
+	return &DisputeService{
+		disputeRepo: disputeRepo,
+	}
+}
+
+func (s *DisputeService) Open(ctx context.Context, userID, transactionID int, reason string) (*dto.Dispute, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxTextLength {
+		return nil, service.ErrInvalidDispute
+	}
+
+	disputeID, err := s.disputeRepo.CreateDispute(ctx, transactionID, userID, reason)
+	if err != nil {
+		switch err {
+		case database.ErrTransferNotFound:
+			return nil, service.ErrTransferNotFound
+		case database.ErrTransferNotDisputable:
+			return nil, service.ErrTransferNotDisputable
+		case database.ErrDisputeAlreadyOpen:
+			return nil, service.ErrDisputeAlreadyOpen
+		default:
+			log.Printf("failed to open dispute on transaction %d by user %d: %v", transactionID, userID, err)
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	return s.getDispute(ctx, disputeID)
+}
+
+func (s *DisputeService) List(ctx context.Context, userID int) ([]dto.Dispute, error) {
+	return s.listDisputes(ctx, entity.DisputeFilter{EmployeeID: userID})
+}
+
+func (s *DisputeService) ListAll(ctx context.Context, status string) ([]dto.Dispute, error) {
+	if status != "" && !slices.Contains(entity.DisputeStatuses, status) {
+		return nil, service.ErrInvalidDispute
+	}
+
+	return s.listDisputes(ctx, entity.DisputeFilter{Status: status})
+}
+
+// Approve reverses the disputed transfer. If the recipient has already spent
+// the coins the approval fails with service.ErrInsufficientFunds, unless force
+// is set to claw the coins back into a negative balance.
+func (s *DisputeService) Approve(ctx context.Context, adminID, disputeID int, note string, force bool) (*dto.Dispute, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxTextLength {
+		return nil, service.ErrInvalidDispute
+	}
+
+	err := s.disputeRepo.ApproveDispute(ctx, disputeID, adminID, note, force)
+	if err != nil {
+		log.Printf("failed to approve dispute %d by user %d: %v", disputeID, adminID, err)
+		switch err {
+		case database.ErrDisputeNotFound:
+			return nil, service.ErrDisputeNotFound
+		case database.ErrDisputeAlreadyResolved:
+			return nil, service.ErrDisputeAlreadyResolved
+		case database.ErrInsufficientFunds:
+			return nil, service.ErrInsufficientFunds
+		case database.ErrEmployeeNotFound:
+			return nil, service.ErrEmployeeNotFound
+		case database.ErrTransactionConflict:
+			return nil, service.ErrConcurrentUpdate
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	return s.getDispute(ctx, disputeID)
+}
+
+func (s *DisputeService) Reject(ctx context.Context, adminID, disputeID int, note string) (*dto.Dispute, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxTextLength {
+		return nil, service.ErrInvalidDispute
+	}
+
+	err := s.disputeRepo.RejectDispute(ctx, disputeID, adminID, note)
+	if err != nil {
+		switch err {
+		case database.ErrDisputeNotFound:
+			return nil, service.ErrDisputeNotFound
+		case database.ErrDisputeAlreadyResolved:
+			return nil, service.ErrDisputeAlreadyResolved
+		default:
+			log.Printf("failed to reject dispute %d by user %d: %v", disputeID, adminID, err)
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	return s.getDispute(ctx, disputeID)
+}
+
+func (s *DisputeService) getDispute(ctx context.Context, disputeID int) (*dto.Dispute, error) {
+	dispute, err := s.disputeRepo.GetDispute(ctx, disputeID)
+	if err != nil {
+		switch err {
+		case database.ErrDisputeNotFound:
+			return nil, service.ErrDisputeNotFound
+		default:
+			log.Printf("failed to get dispute %d: %v", disputeID, err)
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	return mapDisputeToDTO(dispute), nil
+}
+
+func (s *DisputeService) listDisputes(ctx context.Context, filter entity.DisputeFilter) ([]dto.Dispute, error) {
+	disputes, err := s.disputeRepo.ListDisputes(ctx, filter)
+	if err != nil {
+		log.Printf("failed to list disputes: %v", err)
+		return nil, service.ErrDatabaseError
+	}
+
+	result := make([]dto.Dispute, len(disputes))
+	for i, dispute := range disputes {
+		result[i] = *mapDisputeToDTO(dispute)
+	}
+
+	return result, nil
+}
+
+func mapDisputeToDTO(dispute *entity.Dispute) *dto.Dispute {
+	return &dto.Dispute{
+		ID:            dispute.ID,
+		TransactionID: dispute.TransactionID,
+		FromUser:      dispute.Sender,
+		ToUser:        dispute.Receiver,
+		Amount:        dispute.Amount,
+		Reason:        dispute.Reason,
+		Status:        dispute.Status,
+		ResolvedBy:    dispute.ResolvedBy,
+		Note:          dispute.Note,
+		ReversalID:    dispute.ReversalID,
+		Clawback:      dispute.Clawback,
+		CreatedAt:     dispute.CreatedAt,
+		ResolvedAt:    dispute.ResolvedAt,
+	}
+}
@@ -0,0 +1,197 @@
+package disputeservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/dispute"
+)
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	mockDisputeRepo := new(mock.MockDisputeRepository)
+	disputeService := disputeservice.NewDisputeService(mockDisputeRepo)
+
+	dispute := &entity.Dispute{
+		ID: 4, TransactionID: 7, SenderID: 1, Sender: "alice", ReceiverID: 2, Receiver: "bob", Amount: 500,
+		Reason: "wrong bob", Status: entity.DisputeOpen, CreatedAt: time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name          string
+		reason        string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "Success - Dispute Opened",
+			reason: "  wrong bob ",
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+				mockDisputeRepo.On("CreateDispute", ctx, 7, 1, "wrong bob").Return(4, nil)
+				mockDisputeRepo.On("GetDispute", ctx, 4).Return(dispute, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Error - Blank Reason",
+			reason: "   ",
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidDispute,
+		},
+		{
+			name:   "Error - Transfer Of Another Employee",
+			reason: "wrong bob",
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+				mockDisputeRepo.On("CreateDispute", ctx, 7, 1, "wrong bob").Return(0, database.ErrTransferNotFound)
+			},
+			expectedError: service.ErrTransferNotFound,
+		},
+		{
+			name:   "Error - Already Reversed",
+			reason: "wrong bob",
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+				mockDisputeRepo.On("CreateDispute", ctx, 7, 1, "wrong bob").Return(0, database.ErrTransferNotDisputable)
+			},
+			expectedError: service.ErrTransferNotDisputable,
+		},
+		{
+			name:   "Error - Already Open",
+			reason: "wrong bob",
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+				mockDisputeRepo.On("CreateDispute", ctx, 7, 1, "wrong bob").Return(0, database.ErrDisputeAlreadyOpen)
+			},
+			expectedError: service.ErrDisputeAlreadyOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			result, err := disputeService.Open(ctx, 1, 7, tt.reason)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, 4, result.ID)
+				assert.Equal(t, "alice", result.FromUser)
+				assert.Equal(t, "bob", result.ToUser)
+				assert.Equal(t, entity.DisputeOpen, result.Status)
+			}
+
+			mockDisputeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApprove(t *testing.T) {
+	ctx := context.Background()
+	mockDisputeRepo := new(mock.MockDisputeRepository)
+	disputeService := disputeservice.NewDisputeService(mockDisputeRepo)
+
+	resolvedAt := time.Date(2024, 6, 3, 13, 0, 0, 0, time.UTC)
+	approved := &entity.Dispute{
+		ID: 4, TransactionID: 7, Sender: "alice", Receiver: "bob", Amount: 500, Status: entity.DisputeApproved,
+		ResolvedBy: "admin", ReversalID: 9, Clawback: true, ResolvedAt: &resolvedAt,
+	}
+
+	tests := []struct {
+		name          string
+		force         bool
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:  "Success - Forced Clawback",
+			force: true,
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+				mockDisputeRepo.On("ApproveDispute", ctx, 4, 10, "sent by mistake", true).Return(nil)
+				mockDisputeRepo.On("GetDispute", ctx, 4).Return(approved, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "Error - Coins Already Spent",
+			force: false,
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+				mockDisputeRepo.On("ApproveDispute", ctx, 4, 10, "sent by mistake", false).Return(database.ErrInsufficientFunds)
+			},
+			expectedError: service.ErrInsufficientFunds,
+		},
+		{
+			name:  "Error - Already Resolved",
+			force: false,
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+				mockDisputeRepo.On("ApproveDispute", ctx, 4, 10, "sent by mistake", false).Return(database.ErrDisputeAlreadyResolved)
+			},
+			expectedError: service.ErrDisputeAlreadyResolved,
+		},
+		{
+			name:  "Error - Concurrent Update",
+			force: false,
+			mockSetup: func() {
+				mockDisputeRepo.ExpectedCalls = nil
+				mockDisputeRepo.On("ApproveDispute", ctx, 4, 10, "sent by mistake", false).Return(database.ErrTransactionConflict)
+			},
+			expectedError: service.ErrConcurrentUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			result, err := disputeService.Approve(ctx, 10, 4, " sent by mistake ", tt.force)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, entity.DisputeApproved, result.Status)
+				assert.Equal(t, 9, result.ReversalID)
+				assert.True(t, result.Clawback)
+			}
+
+			mockDisputeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRejectAndList(t *testing.T) {
+	ctx := context.Background()
+	mockDisputeRepo := new(mock.MockDisputeRepository)
+	disputeService := disputeservice.NewDisputeService(mockDisputeRepo)
+
+	mockDisputeRepo.On("RejectDispute", ctx, 4, 10, "").Return(database.ErrDisputeNotFound).Once()
+	_, err := disputeService.Reject(ctx, 10, 4, "")
+	assert.Equal(t, service.ErrDisputeNotFound, err)
+
+	_, err = disputeService.ListAll(ctx, "pending")
+	assert.Equal(t, service.ErrInvalidDispute, err)
+
+	mockDisputeRepo.On("ListDisputes", ctx, entity.DisputeFilter{Status: entity.DisputeOpen}).Return([]*entity.Dispute{
+		{ID: 4, Sender: "alice", Receiver: "bob", Amount: 500, Status: entity.DisputeOpen},
+	}, nil).Once()
+	disputes, err := disputeService.ListAll(ctx, entity.DisputeOpen)
+	assert.NoError(t, err)
+	assert.Len(t, disputes, 1)
+
+	mockDisputeRepo.On("ListDisputes", ctx, entity.DisputeFilter{EmployeeID: 2}).Return([]*entity.Dispute{}, nil).Once()
+	disputes, err = disputeService.List(ctx, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, disputes)
+
+	mockDisputeRepo.AssertExpectations(t)
+}
@@ -3,6 +3,8 @@ package employeeservice
 import (
 	"context"
 	"log"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/vit6556/avito-internship-assignment/internal/database"
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
@@ -10,6 +12,9 @@ import (
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
+// maxOfficeLength matches the size of employees.office.
+const maxOfficeLength = 64
+
 type EmployeeService struct {
 	employeeRepo    database.EmployeeRepository
 	merchRepo       database.MerchRepository
@@ -50,6 +55,47 @@ func (s *EmployeeService) GetEmployeeInfo(ctx context.Context, id int) (*dto.Emp
 	}, nil
 }
 
+func (s *EmployeeService) SetRole(ctx context.Context, username, role string) error {
+	switch role {
+	case entity.RoleEmployee, entity.RoleHR, entity.RoleAdmin, entity.RoleOfficeManager:
+	default:
+		return service.ErrInvalidRole
+	}
+
+	err := s.employeeRepo.UpdateEmployeeRole(ctx, username, role)
+	if err != nil {
+		log.Printf("failed to set role %q for employee %q: %v", role, username, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return service.ErrEmployeeNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	return nil
+}
+
+func (s *EmployeeService) SetOffice(ctx context.Context, username, office string) error {
+	office = strings.TrimSpace(office)
+	if utf8.RuneCountInString(office) > maxOfficeLength {
+		return service.ErrInvalidOffice
+	}
+
+	err := s.employeeRepo.UpdateEmployeeOffice(ctx, username, office)
+	if err != nil {
+		log.Printf("failed to set office %q for employee %q: %v", office, username, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return service.ErrEmployeeNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	return nil
+}
+
 func mapInventoryToDTO(items []*entity.InventoryItem) []*dto.InventoryItem {
 	purchases := make([]*dto.InventoryItem, len(items))
 
@@ -57,6 +103,7 @@ func mapInventoryToDTO(items []*entity.InventoryItem) []*dto.InventoryItem {
 		purchases[i] = &dto.InventoryItem{
 			Type:     item.Type,
 			Quantity: item.Quantity,
+			Spent:    item.Spent,
 		}
 	}
 
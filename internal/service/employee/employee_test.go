@@ -2,9 +2,11 @@ package employeeservice_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
 	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	"github.com/vit6556/avito-internship-assignment/internal/entity"
@@ -36,8 +38,8 @@ func TestGetEmployeeInfo(t *testing.T) {
 				Sent:     []entity.CoinTransaction{{User: "charlie", Amount: 20}},
 			},
 			inventory: []*entity.InventoryItem{
-				{Type: "book", Quantity: 2},
-				{Type: "powerbank", Quantity: 1},
+				{Type: "book", Quantity: 2, Spent: 90},
+				{Type: "powerbank", Quantity: 1, Spent: 200},
 			},
 			mockSetup: func() {
 				mockEmployeeRepo.ExpectedCalls = nil
@@ -53,16 +55,16 @@ func TestGetEmployeeInfo(t *testing.T) {
 					}, nil)
 				mockMerchRepo.On("GetUserPurchases", ctx, 1).
 					Return([]*entity.InventoryItem{
-						{Type: "book", Quantity: 2},
-						{Type: "powerbank", Quantity: 1},
+						{Type: "book", Quantity: 2, Spent: 90},
+						{Type: "powerbank", Quantity: 1, Spent: 200},
 					}, nil)
 			},
 			expectedError: nil,
 			expectedData: &dto.EmployeeInfoResponse{
 				Coins: 100,
 				Inventory: []*dto.InventoryItem{
-					{Type: "book", Quantity: 2},
-					{Type: "powerbank", Quantity: 1},
+					{Type: "book", Quantity: 2, Spent: 90},
+					{Type: "powerbank", Quantity: 1, Spent: 200},
 				},
 				CoinHistory: &dto.CoinHistory{
 					Received: []dto.CoinTransaction{{User: "bob", Amount: 50}},
@@ -154,3 +156,76 @@ func TestGetEmployeeInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestSetRole(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockMerchRepo := new(mock.MockMerchRepository)
+	mockTransactionRepo := new(mock.MockTransactionRepository)
+	employeeService := employeeservice.NewEmployeeService(mockEmployeeRepo, mockMerchRepo, mockTransactionRepo)
+
+	tests := []struct {
+		name          string
+		role          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Role Updated",
+			role: entity.RoleHR,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("UpdateEmployeeRole", ctx, "alice", entity.RoleHR).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "Error - Unknown Role",
+			role:          "overlord",
+			mockSetup:     func() { mockEmployeeRepo.ExpectedCalls = nil },
+			expectedError: service.ErrInvalidRole,
+		},
+		{
+			name: "Error - Employee Not Found",
+			role: entity.RoleAdmin,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("UpdateEmployeeRole", ctx, "alice", entity.RoleAdmin).Return(database.ErrEmployeeNotFound)
+			},
+			expectedError: service.ErrEmployeeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := employeeService.SetRole(ctx, "alice", tt.role)
+
+			assert.Equal(t, tt.expectedError, err)
+
+			mockEmployeeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetOffice(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockMerchRepo := new(mock.MockMerchRepository)
+	mockTransactionRepo := new(mock.MockTransactionRepository)
+	employeeService := employeeservice.NewEmployeeService(mockEmployeeRepo, mockMerchRepo, mockTransactionRepo)
+
+	mockEmployeeRepo.On("UpdateEmployeeOffice", ctx, "alice", "msk").Return(nil).Once()
+	err := employeeService.SetOffice(ctx, "alice", "  msk ")
+	assert.NoError(t, err)
+
+	err = employeeService.SetOffice(ctx, "alice", strings.Repeat("o", 65))
+	assert.Equal(t, service.ErrInvalidOffice, err)
+
+	mockEmployeeRepo.On("UpdateEmployeeOffice", ctx, "bob", "").Return(database.ErrEmployeeNotFound).Once()
+	err = employeeService.SetOffice(ctx, "bob", "")
+	assert.Equal(t, service.ErrEmployeeNotFound, err)
+
+	mockEmployeeRepo.AssertExpectations(t)
+}
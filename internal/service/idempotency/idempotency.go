@@ -0,0 +1,74 @@
+package idempotencyservice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+type IdempotencyService struct {
+	idempotencyRepo database.IdempotencyRepository
+	ttl             time.Duration
+}
+
+func NewIdempotencyService(idempotencyRepo database.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+	}
+}
+
+// Begin reserves the key for a new request and returns nil, or returns the
+// stored record if the same request has already been completed.
+func (s *IdempotencyService) Begin(ctx context.Context, userID int, key, fingerprint string) (*entity.IdempotencyRecord, error) {
+	reserved, err := s.idempotencyRepo.ReserveKey(ctx, userID, key, fingerprint, s.ttl)
+	if err != nil {
+		log.Printf("failed to reserve idempotency key %q for user %d: %v", key, userID, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	if reserved {
+		return nil, nil
+	}
+
+	record, err := s.idempotencyRepo.GetKey(ctx, userID, key)
+	if err != nil {
+		log.Printf("failed to get idempotency key %q for user %d: %v", key, userID, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, service.ErrIdempotencyKeyMismatch
+	}
+
+	if !record.Completed {
+		return nil, service.ErrIdempotencyKeyInProgress
+	}
+
+	return record, nil
+}
+
+func (s *IdempotencyService) Complete(ctx context.Context, userID int, key string, status int, body []byte) error {
+	err := s.idempotencyRepo.SaveResponse(ctx, userID, key, status, body)
+	if err != nil {
+		log.Printf("failed to save response for idempotency key %q of user %d: %v", key, userID, err)
+		return service.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// Release forgets the key so that a failed request can be retried with it.
+func (s *IdempotencyService) Release(ctx context.Context, userID int, key string) error {
+	err := s.idempotencyRepo.DeleteKey(ctx, userID, key)
+	if err != nil {
+		log.Printf("failed to release idempotency key %q of user %d: %v", key, userID, err)
+		return service.ErrDatabaseError
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package idempotencyservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/idempotency"
+)
+
+func TestBegin(t *testing.T) {
+	ctx := context.Background()
+	mockIdempotencyRepo := new(mock.MockIdempotencyRepository)
+	idempotencyService := idempotencyservice.NewIdempotencyService(mockIdempotencyRepo, time.Hour)
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedRecord *entity.IdempotencyRecord
+		expectedError  error
+	}{
+		{
+			name: "Success - New Key Reserved",
+			mockSetup: func() {
+				mockIdempotencyRepo.ExpectedCalls = nil
+				mockIdempotencyRepo.On("ReserveKey", ctx, 1, "key", "fingerprint", time.Hour).
+					Return(true, nil)
+			},
+			expectedRecord: nil,
+			expectedError:  nil,
+		},
+		{
+			name: "Success - Completed Request Replayed",
+			mockSetup: func() {
+				mockIdempotencyRepo.ExpectedCalls = nil
+				mockIdempotencyRepo.On("ReserveKey", ctx, 1, "key", "fingerprint", time.Hour).
+					Return(false, nil)
+				mockIdempotencyRepo.On("GetKey", ctx, 1, "key").
+					Return(&entity.IdempotencyRecord{UserID: 1, Key: "key", Fingerprint: "fingerprint", Completed: true, ResponseStatus: 200, ResponseBody: []byte(`{}`)}, nil)
+			},
+			expectedRecord: &entity.IdempotencyRecord{UserID: 1, Key: "key", Fingerprint: "fingerprint", Completed: true, ResponseStatus: 200, ResponseBody: []byte(`{}`)},
+			expectedError:  nil,
+		},
+		{
+			name: "Error - Fingerprint Mismatch",
+			mockSetup: func() {
+				mockIdempotencyRepo.ExpectedCalls = nil
+				mockIdempotencyRepo.On("ReserveKey", ctx, 1, "key", "fingerprint", time.Hour).
+					Return(false, nil)
+				mockIdempotencyRepo.On("GetKey", ctx, 1, "key").
+					Return(&entity.IdempotencyRecord{UserID: 1, Key: "key", Fingerprint: "other", Completed: true}, nil)
+			},
+			expectedRecord: nil,
+			expectedError:  service.ErrIdempotencyKeyMismatch,
+		},
+		{
+			name: "Error - Request In Progress",
+			mockSetup: func() {
+				mockIdempotencyRepo.ExpectedCalls = nil
+				mockIdempotencyRepo.On("ReserveKey", ctx, 1, "key", "fingerprint", time.Hour).
+					Return(false, nil)
+				mockIdempotencyRepo.On("GetKey", ctx, 1, "key").
+					Return(&entity.IdempotencyRecord{UserID: 1, Key: "key", Fingerprint: "fingerprint"}, nil)
+			},
+			expectedRecord: nil,
+			expectedError:  service.ErrIdempotencyKeyInProgress,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockIdempotencyRepo.ExpectedCalls = nil
+				mockIdempotencyRepo.On("ReserveKey", ctx, 1, "key", "fingerprint", time.Hour).
+					Return(false, database.ErrDatabaseInsertFailed)
+			},
+			expectedRecord: nil,
+			expectedError:  service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			record, err := idempotencyService.Begin(ctx, 1, "key", "fingerprint")
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedRecord, record)
+
+			mockIdempotencyRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -3,20 +3,30 @@ package merchservice
 import (
 	"context"
 	"log"
+	"slices"
 
 	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
+const (
+	maxCartLines    = 50
+	maxLineQuantity = 100
+)
+
 type MerchService struct {
-	employeeRepo database.EmployeeRepository
-	merchRepo    database.MerchRepository
+	employeeRepo  database.EmployeeRepository
+	merchRepo     database.MerchRepository
+	promotionRepo database.PromotionRepository
 }
 
-func NewMerchService(employeeRepo database.EmployeeRepository, merchRepo database.MerchRepository) *MerchService {
+func NewMerchService(employeeRepo database.EmployeeRepository, merchRepo database.MerchRepository, promotionRepo database.PromotionRepository) *MerchService {
 	return &MerchService{
-		employeeRepo: employeeRepo,
-		merchRepo:    merchRepo,
+		employeeRepo:  employeeRepo,
+		merchRepo:     merchRepo,
+		promotionRepo: promotionRepo,
 	}
 }
 
@@ -33,7 +43,16 @@ func (s *MerchService) BuyItem(ctx context.Context, userID int, itemName string)
 		return service.ErrEmployeeNotFound
 	}
 
-	if user.Balance < item.Price {
+	if item.Stock != nil && *item.Stock < 1 {
+		return service.ErrOutOfStock
+	}
+
+	promotions, err := s.activePromotions(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	if price, _ := entity.BestPrice(*item, promotions); user.Balance < price {
 		return service.ErrInsufficientFunds
 	}
 
@@ -47,6 +66,10 @@ func (s *MerchService) BuyItem(ctx context.Context, userID int, itemName string)
 			return service.ErrEmployeeNotFound
 		case database.ErrInsufficientFunds:
 			return service.ErrInsufficientFunds
+		case database.ErrOutOfStock:
+			return service.ErrOutOfStock
+		case database.ErrTransactionConflict:
+			return service.ErrConcurrentUpdate
 		default:
 			return service.ErrDatabaseError
 		}
@@ -54,3 +77,143 @@ func (s *MerchService) BuyItem(ctx context.Context, userID int, itemName string)
 
 	return nil
 }
+
+// Checkout places the cart as one order. Each line is charged the best price
+// under the running promotions; promoCode, if set, must lower at least one of
+// them.
+func (s *MerchService) Checkout(ctx context.Context, userID int, cart []entity.OrderLine, promoCode string) (*dto.OrderResponse, error) {
+	if len(cart) == 0 || len(cart) > maxCartLines {
+		return nil, service.ErrInvalidCart
+	}
+
+	// Repeated items are merged into one line, keeping the order of first appearance.
+	lines := make([]entity.OrderLine, 0, len(cart))
+	lineIndex := make(map[int]int, len(cart))
+	items := make(map[int]*entity.MerchItem, len(cart))
+	for _, line := range cart {
+		if line.Quantity <= 0 || line.Quantity > maxLineQuantity {
+			return nil, service.ErrInvalidCart
+		}
+
+		item, err := s.merchRepo.GetItemByName(ctx, line.Item)
+		if err != nil {
+			log.Printf("merch %q not found: %v", line.Item, err)
+			return nil, service.ErrMerchNotFound
+		}
+
+		if i, ok := lineIndex[item.ID]; ok {
+			lines[i].Quantity += line.Quantity
+			if lines[i].Quantity > maxLineQuantity {
+				return nil, service.ErrInvalidCart
+			}
+		} else {
+			lineIndex[item.ID] = len(lines)
+			items[item.ID] = item
+			lines = append(lines, entity.OrderLine{
+				ItemID:    item.ID,
+				Item:      item.Name,
+				Quantity:  line.Quantity,
+				UnitPrice: item.Price,
+			})
+		}
+	}
+
+	for _, line := range lines {
+		if stock := items[line.ItemID].Stock; stock != nil && *stock < line.Quantity {
+			return nil, service.ErrOutOfStock
+		}
+	}
+
+	promoCode = normalizePromoCode(promoCode)
+	promotions, err := s.activePromotions(ctx, promoCode)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	codeUsed := false
+	for _, line := range lines {
+		price, promotion := entity.BestPrice(*items[line.ItemID], promotions)
+		if promoCode != "" && promotion != nil && promotion.Code == promoCode {
+			codeUsed = true
+		}
+		total += price * line.Quantity
+	}
+
+	if promoCode != "" && !codeUsed {
+		return nil, service.ErrPromoCodeNotApplicable
+	}
+
+	user, err := s.employeeRepo.GetEmployeeByID(ctx, userID)
+	if err != nil {
+		log.Printf("employee %d not found: %v", userID, err)
+		return nil, service.ErrEmployeeNotFound
+	}
+
+	if user.Balance < total {
+		return nil, service.ErrInsufficientFunds
+	}
+
+	order, err := s.merchRepo.PlaceOrder(ctx, userID, lines, promoCode)
+	if err != nil {
+		log.Printf("failed to place order for employee %d: %v", userID, err)
+		switch err {
+		case database.ErrPromoCodeNotFound:
+			return nil, service.ErrInvalidPromoCode
+		case database.ErrPromoCodeNotApplicable:
+			return nil, service.ErrPromoCodeNotApplicable
+		case database.ErrPromoCodeExhausted:
+			return nil, service.ErrPromoCodeExhausted
+		case database.ErrMerchNotFound:
+			return nil, service.ErrMerchNotFound
+		case database.ErrEmployeeNotFound:
+			return nil, service.ErrEmployeeNotFound
+		case database.ErrInsufficientFunds:
+			return nil, service.ErrInsufficientFunds
+		case database.ErrOutOfStock:
+			return nil, service.ErrOutOfStock
+		case database.ErrTransactionConflict:
+			return nil, service.ErrConcurrentUpdate
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	response := &dto.OrderResponse{
+		OrderID: order.ID,
+		Total:   order.Total,
+		Lines:   make([]dto.OrderLine, len(order.Lines)),
+	}
+	for i, line := range order.Lines {
+		response.Lines[i] = dto.OrderLine{
+			Item:      line.Item,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			Total:     line.Total,
+			ListPrice: line.ListPrice,
+		}
+		if line.Promotion != nil {
+			response.Lines[i].Promotion = line.Promotion.Name
+		}
+	}
+
+	return response, nil
+}
+
+// activePromotions returns the running promotions that apply to an order
+// placed with promoCode. The code must belong to one of them.
+func (s *MerchService) activePromotions(ctx context.Context, promoCode string) ([]*entity.Promotion, error) {
+	promotions, err := s.promotionRepo.ListActivePromotions(ctx, promoCode)
+	if err != nil {
+		log.Printf("failed to list active promotions: %v", err)
+		return nil, service.ErrDatabaseError
+	}
+
+	if promoCode != "" && !slices.ContainsFunc(promotions, func(promotion *entity.Promotion) bool {
+		return promotion.Code == promoCode
+	}) {
+		return nil, service.ErrInvalidPromoCode
+	}
+
+	return promotions, nil
+}
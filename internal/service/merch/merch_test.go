@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vit6556/avito-internship-assignment/internal/database"
 	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 	"github.com/vit6556/avito-internship-assignment/internal/service/merch"
@@ -16,7 +17,12 @@ func TestBuyItem(t *testing.T) {
 	ctx := context.Background()
 	mockEmployeeRepo := new(mock.MockEmployeeRepository)
 	mockMerchRepo := new(mock.MockMerchRepository)
-	merchService := merchservice.NewMerchService(mockEmployeeRepo, mockMerchRepo)
+	mockPromotionRepo := new(mock.MockPromotionRepository)
+	merchService := merchservice.NewMerchService(mockEmployeeRepo, mockMerchRepo, mockPromotionRepo)
+
+	mockPromotionRepo.On("ListActivePromotions", ctx, "").Return([]*entity.Promotion{}, nil)
+
+	noStock := 0
 
 	tests := []struct {
 		name          string
@@ -85,6 +91,38 @@ func TestBuyItem(t *testing.T) {
 			},
 			expectedError: service.ErrInsufficientFunds,
 		},
+		{
+			name: "Error - Out Of Stock",
+			user: &entity.Employee{ID: 1, Username: "alice", Balance: 100},
+			item: &entity.MerchItem{ID: 1, Name: "book", Price: 50, Stock: &noStock},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").
+					Return(&entity.MerchItem{ID: 1, Name: "book", Price: 50, Stock: &noStock}, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+			},
+			expectedError: service.ErrOutOfStock,
+		},
+		{
+			name: "Error - Sold Out During Purchase",
+			user: &entity.Employee{ID: 1, Username: "alice", Balance: 100},
+			item: &entity.MerchItem{ID: 1, Name: "book", Price: 50},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").
+					Return(&entity.MerchItem{ID: 1, Name: "book", Price: 50}, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockMerchRepo.On("BuyItem", ctx, 1, 1).
+					Return(database.ErrOutOfStock)
+			},
+			expectedError: service.ErrOutOfStock,
+		},
 		{
 			name: "Error - Database Error on Purchase",
 			user: &entity.Employee{ID: 1, Username: "alice", Balance: 100},
@@ -131,3 +169,343 @@ func TestBuyItem(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckout(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockMerchRepo := new(mock.MockMerchRepository)
+	mockPromotionRepo := new(mock.MockPromotionRepository)
+	merchService := merchservice.NewMerchService(mockEmployeeRepo, mockMerchRepo, mockPromotionRepo)
+
+	mockPromotionRepo.On("ListActivePromotions", ctx, "").Return([]*entity.Promotion{}, nil)
+
+	stock := 3
+	book := &entity.MerchItem{ID: 1, Name: "book", Price: 50}
+	pen := &entity.MerchItem{ID: 2, Name: "pen", Price: 10}
+	hoodie := &entity.MerchItem{ID: 3, Name: "hoodie", Price: 30, Stock: &stock}
+
+	tests := []struct {
+		name             string
+		cart             []entity.OrderLine
+		mockSetup        func()
+		expectedResponse *dto.OrderResponse
+		expectedError    error
+	}{
+		{
+			name: "Success - Duplicate Lines Merged",
+			cart: []entity.OrderLine{
+				{Item: "book", Quantity: 1},
+				{Item: "pen", Quantity: 2},
+				{Item: "book", Quantity: 1},
+			},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").Return(book, nil)
+				mockMerchRepo.On("GetItemByName", ctx, "pen").Return(pen, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 200}, nil)
+				mockMerchRepo.On("PlaceOrder", ctx, 1, []entity.OrderLine{
+					{ItemID: 1, Item: "book", Quantity: 2, UnitPrice: 50},
+					{ItemID: 2, Item: "pen", Quantity: 2, UnitPrice: 10},
+				}, "").Return(&entity.Order{
+					ID:         7,
+					EmployeeID: 1,
+					Total:      120,
+					Lines: []entity.OrderLine{
+						{ItemID: 1, Item: "book", Quantity: 2, UnitPrice: 50, Total: 100},
+						{ItemID: 2, Item: "pen", Quantity: 2, UnitPrice: 10, Total: 20},
+					},
+				}, nil)
+			},
+			expectedResponse: &dto.OrderResponse{
+				OrderID: 7,
+				Total:   120,
+				Lines: []dto.OrderLine{
+					{Item: "book", Quantity: 2, UnitPrice: 50, Total: 100},
+					{Item: "pen", Quantity: 2, UnitPrice: 10, Total: 20},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Empty Cart",
+			cart: nil,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidCart,
+		},
+		{
+			name: "Error - Invalid Quantity",
+			cart: []entity.OrderLine{{Item: "book", Quantity: 0}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidCart,
+		},
+		{
+			name: "Error - Merged Quantity Too Large",
+			cart: []entity.OrderLine{{Item: "pen", Quantity: 60}, {Item: "pen", Quantity: 60}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "pen").Return(pen, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidCart,
+		},
+		{
+			name: "Error - Merch Not Found",
+			cart: []entity.OrderLine{{Item: "unknown", Quantity: 1}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "unknown").Return(nil, database.ErrMerchNotFound)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrMerchNotFound,
+		},
+		{
+			name: "Error - Insufficient Funds",
+			cart: []entity.OrderLine{{Item: "book", Quantity: 3}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").Return(book, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInsufficientFunds,
+		},
+		{
+			name: "Error - Not Enough Stock",
+			cart: []entity.OrderLine{{Item: "hoodie", Quantity: 2}, {Item: "hoodie", Quantity: 2}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "hoodie").Return(hoodie, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrOutOfStock,
+		},
+		{
+			name: "Error - Sold Out During Checkout",
+			cart: []entity.OrderLine{{Item: "hoodie", Quantity: 3}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "hoodie").Return(hoodie, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockMerchRepo.On("PlaceOrder", ctx, 1, []entity.OrderLine{{ItemID: 3, Item: "hoodie", Quantity: 3, UnitPrice: 30}}, "").
+					Return(nil, database.ErrOutOfStock)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrOutOfStock,
+		},
+		{
+			name: "Error - Concurrent Update",
+			cart: []entity.OrderLine{{Item: "book", Quantity: 1}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").Return(book, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockMerchRepo.On("PlaceOrder", ctx, 1, []entity.OrderLine{{ItemID: 1, Item: "book", Quantity: 1, UnitPrice: 50}}, "").
+					Return(nil, database.ErrTransactionConflict)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrConcurrentUpdate,
+		},
+		{
+			name: "Error - Database Error",
+			cart: []entity.OrderLine{{Item: "book", Quantity: 1}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").Return(book, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockMerchRepo.On("PlaceOrder", ctx, 1, []entity.OrderLine{{ItemID: 1, Item: "book", Quantity: 1, UnitPrice: 50}}, "").
+					Return(nil, database.ErrDatabaseInsertFailed)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			response, err := merchService.Checkout(ctx, 1, tt.cart, "")
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedResponse, response)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockMerchRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckoutWithPromotions(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockMerchRepo := new(mock.MockMerchRepository)
+	mockPromotionRepo := new(mock.MockPromotionRepository)
+	merchService := merchservice.NewMerchService(mockEmployeeRepo, mockMerchRepo, mockPromotionRepo)
+
+	book := &entity.MerchItem{ID: 1, Name: "book", Price: 50, Category: "office"}
+	hoody := &entity.MerchItem{ID: 3, Name: "hoody", Price: 300, Category: "clothes"}
+
+	hoodyID := 3
+	hoodySale := &entity.Promotion{ID: 1, Name: "Hoody sale", DiscountType: entity.DiscountFixed, Discount: 100, ItemID: &hoodyID}
+	officeCode := &entity.Promotion{ID: 2, Name: "Back to office", DiscountType: entity.DiscountPercent, Discount: 20, Category: "office", Code: "OFFICE20"}
+
+	tests := []struct {
+		name             string
+		cart             []entity.OrderLine
+		promoCode        string
+		mockSetup        func()
+		expectedResponse *dto.OrderResponse
+		expectedError    error
+	}{
+		{
+			name:      "Success - Best Price Charged",
+			cart:      []entity.OrderLine{{Item: "hoody", Quantity: 1}, {Item: "book", Quantity: 2}},
+			promoCode: " office20 ",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "hoody").Return(hoody, nil)
+				mockMerchRepo.On("GetItemByName", ctx, "book").Return(book, nil)
+				mockPromotionRepo.On("ListActivePromotions", ctx, "OFFICE20").Return([]*entity.Promotion{officeCode, hoodySale}, nil)
+				// 200 + 2*40 fits the balance only with both discounts.
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 280}, nil)
+				mockMerchRepo.On("PlaceOrder", ctx, 1, []entity.OrderLine{
+					{ItemID: 3, Item: "hoody", Quantity: 1, UnitPrice: 300},
+					{ItemID: 1, Item: "book", Quantity: 2, UnitPrice: 50},
+				}, "OFFICE20").Return(&entity.Order{
+					ID:         9,
+					EmployeeID: 1,
+					Total:      280,
+					Lines: []entity.OrderLine{
+						{ItemID: 3, Item: "hoody", Quantity: 1, UnitPrice: 200, Total: 200, ListPrice: 300, Promotion: hoodySale},
+						{ItemID: 1, Item: "book", Quantity: 2, UnitPrice: 40, Total: 80, ListPrice: 50, Promotion: officeCode},
+					},
+				}, nil)
+			},
+			expectedResponse: &dto.OrderResponse{
+				OrderID: 9,
+				Total:   280,
+				Lines: []dto.OrderLine{
+					{Item: "hoody", Quantity: 1, UnitPrice: 200, Total: 200, ListPrice: 300, Promotion: "Hoody sale"},
+					{Item: "book", Quantity: 2, UnitPrice: 40, Total: 80, ListPrice: 50, Promotion: "Back to office"},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "Error - Unknown Promo Code",
+			cart:      []entity.OrderLine{{Item: "book", Quantity: 1}},
+			promoCode: "WINTER",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").Return(book, nil)
+				mockPromotionRepo.On("ListActivePromotions", ctx, "WINTER").Return([]*entity.Promotion{hoodySale}, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidPromoCode,
+		},
+		{
+			name:      "Error - Promo Code Does Not Apply",
+			cart:      []entity.OrderLine{{Item: "hoody", Quantity: 1}},
+			promoCode: "OFFICE20",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "hoody").Return(hoody, nil)
+				mockPromotionRepo.On("ListActivePromotions", ctx, "OFFICE20").Return([]*entity.Promotion{officeCode, hoodySale}, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrPromoCodeNotApplicable,
+		},
+		{
+			name:      "Error - Promo Code Used Up",
+			cart:      []entity.OrderLine{{Item: "book", Quantity: 1}},
+			promoCode: "OFFICE20",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").Return(book, nil)
+				mockPromotionRepo.On("ListActivePromotions", ctx, "OFFICE20").Return([]*entity.Promotion{officeCode}, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockMerchRepo.On("PlaceOrder", ctx, 1, []entity.OrderLine{{ItemID: 1, Item: "book", Quantity: 1, UnitPrice: 50}}, "OFFICE20").
+					Return(nil, database.ErrPromoCodeExhausted)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrPromoCodeExhausted,
+		},
+		{
+			name:      "Error - Promo Code Ended Before Order Placed",
+			cart:      []entity.OrderLine{{Item: "book", Quantity: 1}},
+			promoCode: "OFFICE20",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "book").Return(book, nil)
+				mockPromotionRepo.On("ListActivePromotions", ctx, "OFFICE20").Return([]*entity.Promotion{officeCode}, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockMerchRepo.On("PlaceOrder", ctx, 1, []entity.OrderLine{{ItemID: 1, Item: "book", Quantity: 1, UnitPrice: 50}}, "OFFICE20").
+					Return(nil, database.ErrPromoCodeNotFound)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidPromoCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			response, err := merchService.Checkout(ctx, 1, tt.cart, tt.promoCode)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedResponse, response)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockMerchRepo.AssertExpectations(t)
+			mockPromotionRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package merchservice
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+// OrderService moves merch orders through their lifecycle. Employees can
+// cancel their own orders for a short time after placing them; later only an
+// admin can refund an order. Office managers prepare placed orders and hand
+// them over, which fulfils them.
+type OrderService struct {
+	orderRepo    database.OrderRepository
+	cancelWindow time.Duration
+}
+
+func NewOrderService(orderRepo database.OrderRepository, cancelWindow time.Duration) *OrderService {
+	return &OrderService{
+		orderRepo:    orderRepo,
+		cancelWindow: cancelWindow,
+	}
+}
+
+func (s *OrderService) ListOrders(ctx context.Context, userID int) ([]dto.Order, error) {
+	return s.listOrders(ctx, entity.OrderFilter{EmployeeID: userID})
+}
+
+// ListAll returns the orders of all employees, for the people who fulfil them.
+func (s *OrderService) ListAll(ctx context.Context, filter entity.OrderFilter) ([]dto.Order, error) {
+	return s.listOrders(ctx, filter)
+}
+
+func (s *OrderService) listOrders(ctx context.Context, filter entity.OrderFilter) ([]dto.Order, error) {
+	orders, err := s.orderRepo.ListOrders(ctx, filter)
+	if err != nil {
+		log.Printf("failed to list orders: %v", err)
+		return nil, service.ErrDatabaseError
+	}
+
+	now := time.Now().UTC()
+	result := make([]dto.Order, len(orders))
+	for i, order := range orders {
+		result[i] = *s.mapOrderToDTO(order, now)
+	}
+
+	return result, nil
+}
+
+func (s *OrderService) Cancel(ctx context.Context, userID, orderID int) (*dto.Order, error) {
+	order, err := s.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, mapOrderError(err)
+	}
+
+	if order.EmployeeID != userID {
+		return nil, service.ErrOrderNotFound
+	}
+
+	// Once the office manager has started preparing the order it can only be refunded.
+	if order.Status != entity.OrderPlaced || order.FulfilmentStatus != entity.FulfilmentNew {
+		return nil, service.ErrOrderTransition
+	}
+
+	if !time.Now().UTC().Before(order.CreatedAt.Add(s.cancelWindow)) {
+		return nil, service.ErrCancelWindowEnded
+	}
+
+	err = s.orderRepo.CancelOrder(ctx, orderID, s.cancelWindow)
+	if err != nil {
+		log.Printf("failed to cancel order %d: %v", orderID, err)
+		return nil, mapOrderError(err)
+	}
+
+	return s.getOrder(ctx, orderID)
+}
+
+// Advance moves a placed order to the next fulfilment step. If step is set,
+// it must be that next step, so that a repeated request does not skip one.
+func (s *OrderService) Advance(ctx context.Context, orderID int, step string) (*dto.Order, error) {
+	order, err := s.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, mapOrderError(err)
+	}
+
+	next := slices.Index(entity.FulfilmentSteps, order.FulfilmentStatus) + 1
+	if order.Status != entity.OrderPlaced || next == 0 || next == len(entity.FulfilmentSteps) {
+		return nil, service.ErrOrderTransition
+	}
+
+	if step == "" {
+		step = entity.FulfilmentSteps[next]
+	}
+	if step != entity.FulfilmentSteps[next] {
+		return nil, service.ErrOrderTransition
+	}
+
+	err = s.orderRepo.AdvanceFulfilment(ctx, orderID, step)
+	if err != nil {
+		log.Printf("failed to advance fulfilment of order %d to %q: %v", orderID, step, err)
+		return nil, mapOrderError(err)
+	}
+
+	return s.getOrder(ctx, orderID)
+}
+
+// Refund credits the order back to the employee and returns its items to
+// stock, whether or not the order has been fulfilled.
+func (s *OrderService) Refund(ctx context.Context, orderID int) (*dto.Order, error) {
+	return s.setStatus(ctx, orderID, entity.OrderRefunded)
+}
+
+func (s *OrderService) setStatus(ctx context.Context, orderID int, status string) (*dto.Order, error) {
+	err := s.orderRepo.SetOrderStatus(ctx, orderID, status)
+	if err != nil {
+		log.Printf("failed to set status of order %d to %q: %v", orderID, status, err)
+		return nil, mapOrderError(err)
+	}
+
+	return s.getOrder(ctx, orderID)
+}
+
+func (s *OrderService) getOrder(ctx context.Context, orderID int) (*dto.Order, error) {
+	order, err := s.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, mapOrderError(err)
+	}
+
+	return s.mapOrderToDTO(order, time.Now().UTC()), nil
+}
+
+func mapOrderError(err error) error {
+	switch err {
+	case database.ErrOrderNotFound:
+		return service.ErrOrderNotFound
+	case database.ErrOrderTransition:
+		return service.ErrOrderTransition
+	case database.ErrCancelWindowEnded:
+		return service.ErrCancelWindowEnded
+	case database.ErrTransactionConflict:
+		return service.ErrConcurrentUpdate
+	default:
+		return service.ErrDatabaseError
+	}
+}
+
+func (s *OrderService) mapOrderToDTO(order *entity.Order, now time.Time) *dto.Order {
+	result := &dto.Order{
+		ID:                  order.ID,
+		Employee:            order.Employee,
+		Office:              order.Office,
+		Status:              order.Status,
+		FulfilmentStatus:    order.FulfilmentStatus,
+		Total:               order.Total,
+		Items:               make([]dto.OrderItem, len(order.Lines)),
+		CreatedAt:           order.CreatedAt,
+		StatusChangedAt:     order.StatusChangedAt,
+		FulfilmentChangedAt: order.FulfilmentChangedAt,
+	}
+	for i, line := range order.Lines {
+		result.Items[i] = dto.OrderItem{Item: line.Item, Quantity: line.Quantity, UnitPrice: line.UnitPrice, Total: line.Total}
+	}
+
+	if cancellableUntil := order.CreatedAt.Add(s.cancelWindow); order.Status == entity.OrderPlaced && order.FulfilmentStatus == entity.FulfilmentNew && now.Before(cancellableUntil) {
+		result.CancellableUntil = &cancellableUntil
+	}
+
+	return result
+}
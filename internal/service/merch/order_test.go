@@ -0,0 +1,264 @@
+package merchservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/merch"
+)
+
+func TestCancelOrder(t *testing.T) {
+	ctx := context.Background()
+	mockOrderRepo := new(mock.MockOrderRepository)
+	orderService := merchservice.NewOrderService(mockOrderRepo, time.Hour)
+
+	order := func(status string, placedAgo time.Duration) *entity.Order {
+		return &entity.Order{
+			ID: 5, EmployeeID: 1, Employee: "alice", Total: 580, Status: status, FulfilmentStatus: entity.FulfilmentNew,
+			Lines:     []entity.OrderLine{{ItemID: 3, Item: "hoody", Quantity: 2}},
+			CreatedAt: time.Now().UTC().Add(-placedAgo),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		userID        int
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "Success - Order Cancelled",
+			userID: 1,
+			mockSetup: func() {
+				cancelled := order(entity.OrderCancelled, 10*time.Minute)
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, 10*time.Minute), nil).Once()
+				mockOrderRepo.On("CancelOrder", ctx, 5, time.Hour).Return(nil)
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(cancelled, nil).Once()
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Error - Order Of Another Employee",
+			userID: 2,
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, 10*time.Minute), nil)
+			},
+			expectedError: service.ErrOrderNotFound,
+		},
+		{
+			name:   "Error - Window Ended",
+			userID: 1,
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, 2*time.Hour), nil)
+			},
+			expectedError: service.ErrCancelWindowEnded,
+		},
+		{
+			name:   "Error - Already Fulfilled",
+			userID: 1,
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderFulfilled, 10*time.Minute), nil)
+			},
+			expectedError: service.ErrOrderTransition,
+		},
+		{
+			name:   "Error - Already Packed",
+			userID: 1,
+			mockSetup: func() {
+				packed := order(entity.OrderPlaced, 10*time.Minute)
+				packed.FulfilmentStatus = entity.FulfilmentPacked
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(packed, nil)
+			},
+			expectedError: service.ErrOrderTransition,
+		},
+		{
+			name:   "Error - Fulfilled Concurrently",
+			userID: 1,
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, 10*time.Minute), nil)
+				mockOrderRepo.On("CancelOrder", ctx, 5, time.Hour).Return(database.ErrOrderTransition)
+			},
+			expectedError: service.ErrOrderTransition,
+		},
+		{
+			name:   "Error - Window Ended Concurrently",
+			userID: 1,
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, 10*time.Minute), nil)
+				mockOrderRepo.On("CancelOrder", ctx, 5, time.Hour).Return(database.ErrCancelWindowEnded)
+			},
+			expectedError: service.ErrCancelWindowEnded,
+		},
+		{
+			name:   "Error - Not Found",
+			userID: 1,
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(nil, database.ErrOrderNotFound)
+			},
+			expectedError: service.ErrOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			result, err := orderService.Cancel(ctx, tt.userID, 5)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, entity.OrderCancelled, result.Status)
+				assert.Nil(t, result.CancellableUntil)
+			}
+
+			mockOrderRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRefundAndListOrders(t *testing.T) {
+	ctx := context.Background()
+	mockOrderRepo := new(mock.MockOrderRepository)
+	orderService := merchservice.NewOrderService(mockOrderRepo, time.Hour)
+
+	now := time.Now().UTC()
+	mockOrderRepo.On("ListOrders", ctx, entity.OrderFilter{EmployeeID: 1}).Return([]*entity.Order{
+		{ID: 6, EmployeeID: 1, Total: 10, Status: entity.OrderPlaced, FulfilmentStatus: entity.FulfilmentNew, CreatedAt: now.Add(-time.Minute),
+			Lines: []entity.OrderLine{{Item: "pen", Quantity: 1}}},
+		{ID: 5, EmployeeID: 1, Total: 580, Status: entity.OrderPlaced, FulfilmentStatus: entity.FulfilmentNew, CreatedAt: now.Add(-2 * time.Hour),
+			Lines: []entity.OrderLine{{Item: "hoody", Quantity: 2, UnitPrice: 290, Total: 580}}},
+	}, nil).Once()
+
+	orders, err := orderService.ListOrders(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+	assert.Equal(t, "pen", orders[0].Items[0].Item)
+	assert.Equal(t, dto.OrderItem{Item: "hoody", Quantity: 2, UnitPrice: 290, Total: 580}, orders[1].Items[0])
+	assert.NotNil(t, orders[0].CancellableUntil)
+	assert.Nil(t, orders[1].CancellableUntil)
+
+	mockOrderRepo.On("SetOrderStatus", ctx, 5, entity.OrderRefunded).Return(database.ErrOrderTransition).Once()
+	_, err = orderService.Refund(ctx, 5)
+	assert.Equal(t, service.ErrOrderTransition, err)
+
+	mockOrderRepo.On("SetOrderStatus", ctx, 6, entity.OrderRefunded).Return(nil).Once()
+	mockOrderRepo.On("GetOrder", ctx, 6).Return(&entity.Order{ID: 6, EmployeeID: 1, Total: 10, Status: entity.OrderRefunded}, nil).Once()
+	refunded, err := orderService.Refund(ctx, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderRefunded, refunded.Status)
+
+	mockOrderRepo.AssertExpectations(t)
+}
+
+func TestAdvanceOrder(t *testing.T) {
+	ctx := context.Background()
+	mockOrderRepo := new(mock.MockOrderRepository)
+	orderService := merchservice.NewOrderService(mockOrderRepo, time.Hour)
+
+	order := func(status, fulfilment string) *entity.Order {
+		return &entity.Order{ID: 5, EmployeeID: 1, Employee: "alice", Office: "msk", Total: 580, Status: status, FulfilmentStatus: fulfilment}
+	}
+
+	tests := []struct {
+		name           string
+		step           string
+		mockSetup      func()
+		expectedStatus string
+		expectedStep   string
+		expectedError  error
+	}{
+		{
+			name: "Success - Next Step",
+			step: "",
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, entity.FulfilmentNew), nil).Once()
+				mockOrderRepo.On("AdvanceFulfilment", ctx, 5, entity.FulfilmentPacked).Return(nil)
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, entity.FulfilmentPacked), nil).Once()
+			},
+			expectedStatus: entity.OrderPlaced,
+			expectedStep:   entity.FulfilmentPacked,
+		},
+		{
+			name: "Success - Handed Over",
+			step: entity.FulfilmentHandedOver,
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, entity.FulfilmentReadyForPickup), nil).Once()
+				mockOrderRepo.On("AdvanceFulfilment", ctx, 5, entity.FulfilmentHandedOver).Return(nil)
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderFulfilled, entity.FulfilmentHandedOver), nil).Once()
+			},
+			expectedStatus: entity.OrderFulfilled,
+			expectedStep:   entity.FulfilmentHandedOver,
+		},
+		{
+			name: "Error - Step Repeated",
+			step: entity.FulfilmentPacked,
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, entity.FulfilmentPacked), nil)
+			},
+			expectedError: service.ErrOrderTransition,
+		},
+		{
+			name: "Error - Cancelled Order",
+			step: "",
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderCancelled, entity.FulfilmentNew), nil)
+			},
+			expectedError: service.ErrOrderTransition,
+		},
+		{
+			name: "Error - Concurrent Update",
+			step: "",
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderPlaced, entity.FulfilmentNew), nil)
+				mockOrderRepo.On("AdvanceFulfilment", ctx, 5, entity.FulfilmentPacked).Return(database.ErrTransactionConflict)
+			},
+			expectedError: service.ErrConcurrentUpdate,
+		},
+		{
+			name: "Error - Already Handed Over",
+			step: "",
+			mockSetup: func() {
+				mockOrderRepo.ExpectedCalls = nil
+				mockOrderRepo.On("GetOrder", ctx, 5).Return(order(entity.OrderFulfilled, entity.FulfilmentHandedOver), nil)
+			},
+			expectedError: service.ErrOrderTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			result, err := orderService.Advance(ctx, 5, tt.step)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedStatus, result.Status)
+				assert.Equal(t, tt.expectedStep, result.FulfilmentStatus)
+				assert.Equal(t, "msk", result.Office)
+			}
+
+			mockOrderRepo.AssertExpectations(t)
+		})
+	}
+}
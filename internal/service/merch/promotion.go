@@ -0,0 +1,137 @@
+package merchservice
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+// PromotionService lets admins run time-limited discounts on merch.
+type PromotionService struct {
+	merchRepo     database.MerchRepository
+	promotionRepo database.PromotionRepository
+}
+
+func NewPromotionService(merchRepo database.MerchRepository, promotionRepo database.PromotionRepository) *PromotionService {
+	return &PromotionService{
+		merchRepo:     merchRepo,
+		promotionRepo: promotionRepo,
+	}
+}
+
+// Create starts a promotion. promotion.Item is the name of the discounted
+// item; promo codes are case-insensitive and stored upper-case.
+func (s *PromotionService) Create(ctx context.Context, adminID int, promotion entity.Promotion) (*dto.Promotion, error) {
+	now := time.Now().UTC()
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	promotion.Category = strings.TrimSpace(promotion.Category)
+	promotion.Code = normalizePromoCode(promotion.Code)
+	if promotion.StartsAt.IsZero() {
+		promotion.StartsAt = now
+	}
+	promotion.StartsAt = promotion.StartsAt.UTC()
+	promotion.EndsAt = promotion.EndsAt.UTC()
+
+	switch {
+	case promotion.Name == "",
+		promotion.Discount <= 0,
+		promotion.DiscountType != entity.DiscountPercent && promotion.DiscountType != entity.DiscountFixed,
+		promotion.DiscountType == entity.DiscountPercent && promotion.Discount > 100,
+		promotion.Item != "" && promotion.Category != "",
+		!promotion.EndsAt.After(promotion.StartsAt),
+		!promotion.EndsAt.After(now),
+		promotion.Code == "" && (promotion.MaxUses != nil || promotion.MaxUsesPerEmployee != nil):
+		return nil, service.ErrInvalidPromotion
+	}
+
+	if promotion.Item != "" {
+		item, err := s.merchRepo.GetItemByName(ctx, promotion.Item)
+		if err != nil {
+			log.Printf("merch %q not found: %v", promotion.Item, err)
+			return nil, service.ErrMerchNotFound
+		}
+		promotion.ItemID = &item.ID
+	}
+
+	promotionID, err := s.promotionRepo.CreatePromotion(ctx, promotion, adminID)
+	if err != nil {
+		log.Printf("failed to create promotion %q: %v", promotion.Name, err)
+		switch err {
+		case database.ErrPromoCodeAlreadyExists:
+			return nil, service.ErrPromoCodeAlreadyExists
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	created, err := s.promotionRepo.GetPromotion(ctx, promotionID)
+	if err != nil {
+		log.Printf("failed to get promotion %d: %v", promotionID, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	result := mapPromotionToDTO(created, time.Now().UTC())
+	return &result, nil
+}
+
+func (s *PromotionService) List(ctx context.Context) ([]dto.Promotion, error) {
+	promotions, err := s.promotionRepo.ListPromotions(ctx)
+	if err != nil {
+		log.Printf("failed to list promotions: %v", err)
+		return nil, service.ErrDatabaseError
+	}
+
+	now := time.Now().UTC()
+	result := make([]dto.Promotion, len(promotions))
+	for i, promotion := range promotions {
+		result[i] = mapPromotionToDTO(promotion, now)
+	}
+
+	return result, nil
+}
+
+// Disable ends a promotion early. Orders already placed keep their prices.
+func (s *PromotionService) Disable(ctx context.Context, promotionID int) error {
+	err := s.promotionRepo.DisablePromotion(ctx, promotionID)
+	if err != nil {
+		log.Printf("failed to disable promotion %d: %v", promotionID, err)
+		switch err {
+		case database.ErrPromotionNotFound:
+			return service.ErrPromotionNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	return nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func mapPromotionToDTO(promotion *entity.Promotion, now time.Time) dto.Promotion {
+	return dto.Promotion{
+		ID:                 promotion.ID,
+		Name:               promotion.Name,
+		DiscountType:       promotion.DiscountType,
+		Discount:           promotion.Discount,
+		Item:               promotion.Item,
+		Category:           promotion.Category,
+		Code:               promotion.Code,
+		Status:             promotion.Status(now),
+		StartsAt:           promotion.StartsAt,
+		EndsAt:             promotion.EndsAt,
+		MaxUses:            promotion.MaxUses,
+		MaxUsesPerEmployee: promotion.MaxUsesPerEmployee,
+		Uses:               promotion.Uses,
+		CreatedAt:          promotion.CreatedAt,
+		DisabledAt:         promotion.DisabledAt,
+	}
+}
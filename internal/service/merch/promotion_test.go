@@ -0,0 +1,168 @@
+package merchservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/merch"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	ctx := context.Background()
+	mockMerchRepo := new(mock.MockMerchRepository)
+	mockPromotionRepo := new(mock.MockPromotionRepository)
+	promotionService := merchservice.NewPromotionService(mockMerchRepo, mockPromotionRepo)
+
+	startsAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	endsAt := startsAt.Add(7 * 24 * time.Hour)
+	maxUses := 100
+	hoodyID := 3
+
+	tests := []struct {
+		name           string
+		promotion      entity.Promotion
+		mockSetup      func()
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			name: "Success - Item Promotion With Code",
+			promotion: entity.Promotion{
+				Name: " Hoody sale ", DiscountType: entity.DiscountPercent, Discount: 25, Item: "hoody", Code: "hoody25",
+				StartsAt: startsAt, EndsAt: endsAt, MaxUses: &maxUses,
+			},
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "hoody").Return(&entity.MerchItem{ID: 3, Name: "hoody", Price: 300}, nil)
+				mockPromotionRepo.On("CreatePromotion", ctx, entity.Promotion{
+					Name: "Hoody sale", DiscountType: entity.DiscountPercent, Discount: 25, ItemID: &hoodyID, Item: "hoody", Code: "HOODY25",
+					StartsAt: startsAt, EndsAt: endsAt, MaxUses: &maxUses,
+				}, 10).Return(4, nil)
+				mockPromotionRepo.On("GetPromotion", ctx, 4).Return(&entity.Promotion{
+					ID: 4, Name: "Hoody sale", DiscountType: entity.DiscountPercent, Discount: 25, ItemID: &hoodyID, Item: "hoody", Code: "HOODY25",
+					StartsAt: startsAt, EndsAt: endsAt, MaxUses: &maxUses,
+				}, nil)
+			},
+			expectedStatus: entity.PromotionScheduled,
+			expectedError:  nil,
+		},
+		{
+			name:      "Error - Percent Over 100",
+			promotion: entity.Promotion{Name: "Free", DiscountType: entity.DiscountPercent, Discount: 150, StartsAt: startsAt, EndsAt: endsAt},
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidPromotion,
+		},
+		{
+			name:      "Error - Ends Before Start",
+			promotion: entity.Promotion{Name: "Sale", DiscountType: entity.DiscountFixed, Discount: 10, StartsAt: endsAt, EndsAt: startsAt},
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidPromotion,
+		},
+		{
+			name: "Error - Limit Without Code",
+			promotion: entity.Promotion{
+				Name: "Sale", DiscountType: entity.DiscountFixed, Discount: 10, StartsAt: startsAt, EndsAt: endsAt, MaxUses: &maxUses,
+			},
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidPromotion,
+		},
+		{
+			name: "Error - Item And Category",
+			promotion: entity.Promotion{
+				Name: "Sale", DiscountType: entity.DiscountFixed, Discount: 10, Item: "hoody", Category: "clothes", StartsAt: startsAt, EndsAt: endsAt,
+			},
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidPromotion,
+		},
+		{
+			name:      "Error - Merch Not Found",
+			promotion: entity.Promotion{Name: "Sale", DiscountType: entity.DiscountFixed, Discount: 10, Item: "unknown", StartsAt: startsAt, EndsAt: endsAt},
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+
+				mockMerchRepo.On("GetItemByName", ctx, "unknown").Return(nil, database.ErrMerchNotFound)
+			},
+			expectedError: service.ErrMerchNotFound,
+		},
+		{
+			name:      "Error - Code Already Exists",
+			promotion: entity.Promotion{Name: "Sale", DiscountType: entity.DiscountFixed, Discount: 10, Code: "SALE", StartsAt: startsAt, EndsAt: endsAt},
+			mockSetup: func() {
+				mockMerchRepo.ExpectedCalls = nil
+				mockPromotionRepo.ExpectedCalls = nil
+
+				mockPromotionRepo.On("CreatePromotion", ctx, entity.Promotion{
+					Name: "Sale", DiscountType: entity.DiscountFixed, Discount: 10, Code: "SALE", StartsAt: startsAt, EndsAt: endsAt,
+				}, 10).Return(0, database.ErrPromoCodeAlreadyExists)
+			},
+			expectedError: service.ErrPromoCodeAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			result, err := promotionService.Create(ctx, 10, tt.promotion)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedStatus, result.Status)
+				assert.Equal(t, "HOODY25", result.Code)
+			}
+
+			mockMerchRepo.AssertExpectations(t)
+			mockPromotionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListAndDisablePromotions(t *testing.T) {
+	ctx := context.Background()
+	mockMerchRepo := new(mock.MockMerchRepository)
+	mockPromotionRepo := new(mock.MockPromotionRepository)
+	promotionService := merchservice.NewPromotionService(mockMerchRepo, mockPromotionRepo)
+
+	now := time.Now().UTC()
+	disabledAt := now.Add(-time.Minute)
+	mockPromotionRepo.On("ListPromotions", ctx).Return([]*entity.Promotion{
+		{ID: 2, Name: "Back to office", DiscountType: entity.DiscountPercent, Discount: 20, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Uses: 3},
+		{ID: 1, Name: "Hoody sale", DiscountType: entity.DiscountFixed, Discount: 100, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), DisabledAt: &disabledAt},
+	}, nil).Once()
+
+	promotions, err := promotionService.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, promotions, 2)
+	assert.Equal(t, entity.PromotionActive, promotions[0].Status)
+	assert.Equal(t, 3, promotions[0].Uses)
+	assert.Equal(t, entity.PromotionDisabled, promotions[1].Status)
+
+	mockPromotionRepo.On("DisablePromotion", ctx, 2).Return(nil).Once()
+	assert.NoError(t, promotionService.Disable(ctx, 2))
+
+	mockPromotionRepo.On("DisablePromotion", ctx, 1).Return(database.ErrPromotionNotFound).Once()
+	assert.Equal(t, service.ErrPromotionNotFound, promotionService.Disable(ctx, 1))
+
+	mockPromotionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) Create(ctx context.Context, creatorID int, username, name string, scopes []string, expiresAt *time.Time) (*dto.CreatedAPIKey, error) {
+	args := m.Called(ctx, creatorID, username, name, scopes, expiresAt)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.CreatedAPIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyService) List(ctx context.Context) ([]dto.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyService) Revoke(ctx context.Context, keyID int) error {
+	args := m.Called(ctx, keyID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*entity.APIKey, error) {
+	args := m.Called(ctx, rawKey)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -4,18 +4,79 @@ import (
 	"context"
 
 	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 )
 
 type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) AuthorizeUser(ctx context.Context, username, password string) (string, error) {
+func (m *MockAuthService) AuthorizeUser(ctx context.Context, username, password string) (*dto.AuthResponse, error) {
 	args := m.Called(ctx, username, password)
-	return args.String(0), args.Error(1)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.AuthResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) AuthorizeExternal(ctx context.Context, username string) (*dto.AuthResponse, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.AuthResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) Register(ctx context.Context, username, password, inviteCode string) (*dto.AuthResponse, error) {
+	args := m.Called(ctx, username, password, inviteCode)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.AuthResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthResponse, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.AuthResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, claims *entity.TokenClaims, refreshToken string) error {
+	args := m.Called(ctx, claims, refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ValidateToken(ctx context.Context, tokenString string) (*entity.TokenClaims, error) {
+	args := m.Called(ctx, tokenString)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.TokenClaims), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) JWKS() dto.JWKS {
+	args := m.Called()
+	return args.Get(0).(dto.JWKS)
+}
+
+func (m *MockAuthService) ChangePassword(ctx context.Context, userID int, clientIP, currentPassword, newPassword string) error {
+	args := m.Called(ctx, userID, clientIP, currentPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) IssuePasswordReset(ctx context.Context, issuerID int, username string) (*dto.PasswordResetResponse, error) {
+	args := m.Called(ctx, issuerID, username)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.PasswordResetResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func (m *MockAuthService) ValidateToken(tokenString string) (int, error) {
-	args := m.Called(tokenString)
-	return args.Int(0), args.Error(1)
+func (m *MockAuthService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	args := m.Called(ctx, resetToken, newPassword)
+	return args.Error(0)
 }
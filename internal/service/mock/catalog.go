@@ -0,0 +1,62 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+)
+
+type MockMerchCatalogService struct {
+	mock.Mock
+}
+
+func (m *MockMerchCatalogService) ListItems(ctx context.Context) ([]dto.MerchItem, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.MerchItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMerchCatalogService) GetItem(ctx context.Context, name string) (*dto.MerchItem, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.MerchItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMerchCatalogService) CreateItem(ctx context.Context, name string, price int, stock *int, category string) (*dto.MerchItem, error) {
+	args := m.Called(ctx, name, price, stock, category)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.MerchItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMerchCatalogService) UpdatePrice(ctx context.Context, name string, price int) (*dto.MerchItem, error) {
+	args := m.Called(ctx, name, price)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.MerchItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMerchCatalogService) SetCategory(ctx context.Context, name, category string) error {
+	args := m.Called(ctx, name, category)
+	return args.Error(0)
+}
+
+func (m *MockMerchCatalogService) RetireItem(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockMerchCatalogService) Restock(ctx context.Context, name string, quantity int) (*dto.MerchItem, error) {
+	args := m.Called(ctx, name, quantity)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.MerchItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
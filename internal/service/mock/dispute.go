@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+)
+
+type MockDisputeService struct {
+	mock.Mock
+}
+
+func (m *MockDisputeService) Open(ctx context.Context, userID, transactionID int, reason string) (*dto.Dispute, error) {
+	args := m.Called(ctx, userID, transactionID, reason)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Dispute), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDisputeService) List(ctx context.Context, userID int) ([]dto.Dispute, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.Dispute), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDisputeService) ListAll(ctx context.Context, status string) ([]dto.Dispute, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.Dispute), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDisputeService) Approve(ctx context.Context, adminID, disputeID int, note string, force bool) (*dto.Dispute, error) {
+	args := m.Called(ctx, adminID, disputeID, note, force)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Dispute), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDisputeService) Reject(ctx context.Context, adminID, disputeID int, note string) (*dto.Dispute, error) {
+	args := m.Called(ctx, adminID, disputeID, note)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Dispute), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
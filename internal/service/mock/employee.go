@@ -18,3 +18,13 @@ func (m *MockEmployeeService) GetEmployeeInfo(ctx context.Context, id int) (*dto
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockEmployeeService) SetRole(ctx context.Context, username, role string) error {
+	args := m.Called(ctx, username, role)
+	return args.Error(0)
+}
+
+func (m *MockEmployeeService) SetOffice(ctx context.Context, username, office string) error {
+	args := m.Called(ctx, username, office)
+	return args.Error(0)
+}
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockEscrowService struct {
+	mock.Mock
+}
+
+func (m *MockEscrowService) Hold(ctx context.Context, senderID int, toUser string, amount int, note entity.TransferNote) (*dto.PendingTransfer, error) {
+	args := m.Called(ctx, senderID, toUser, amount, note)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEscrowService) ListPending(ctx context.Context, userID int) (*dto.PendingTransfersResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.PendingTransfersResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEscrowService) Accept(ctx context.Context, userID, transferID int) (*dto.PendingTransfer, error) {
+	args := m.Called(ctx, userID, transferID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEscrowService) Decline(ctx context.Context, userID, transferID int) (*dto.PendingTransfer, error) {
+	args := m.Called(ctx, userID, transferID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.PendingTransfer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyService) Begin(ctx context.Context, userID int, key, fingerprint string) (*entity.IdempotencyRecord, error) {
+	args := m.Called(ctx, userID, key, fingerprint)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entity.IdempotencyRecord), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIdempotencyService) Complete(ctx context.Context, userID int, key string, status int, body []byte) error {
+	args := m.Called(ctx, userID, key, status, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) Release(ctx context.Context, userID int, key string) error {
+	args := m.Called(ctx, userID, key)
+	return args.Error(0)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 
 	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 )
 
 type MockMerchService struct {
@@ -14,3 +17,11 @@ func (m *MockMerchService) BuyItem(ctx context.Context, userID int, itemName str
 	args := m.Called(ctx, userID, itemName)
 	return args.Error(0)
 }
+
+func (m *MockMerchService) Checkout(ctx context.Context, userID int, cart []entity.OrderLine, promoCode string) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, userID, cart, promoCode)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.OrderResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
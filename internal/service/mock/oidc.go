@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+)
+
+type MockOIDCService struct {
+	mock.Mock
+}
+
+func (m *MockOIDCService) AuthCodeURL(ctx context.Context) (string, string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockOIDCService) Exchange(ctx context.Context, code, state string) (*dto.AuthResponse, error) {
+	args := m.Called(ctx, code, state)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.AuthResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,54 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockOrderService struct {
+	mock.Mock
+}
+
+func (m *MockOrderService) ListOrders(ctx context.Context, userID int) ([]dto.Order, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderService) ListAll(ctx context.Context, filter entity.OrderFilter) ([]dto.Order, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderService) Cancel(ctx context.Context, userID, orderID int) (*dto.Order, error) {
+	args := m.Called(ctx, userID, orderID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderService) Advance(ctx context.Context, orderID int, step string) (*dto.Order, error) {
+	args := m.Called(ctx, orderID, step)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderService) Refund(ctx context.Context, orderID int) (*dto.Order, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockPromotionService struct {
+	mock.Mock
+}
+
+func (m *MockPromotionService) Create(ctx context.Context, adminID int, promotion entity.Promotion) (*dto.Promotion, error) {
+	args := m.Called(ctx, adminID, promotion)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Promotion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPromotionService) List(ctx context.Context) ([]dto.Promotion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.Promotion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPromotionService) Disable(ctx context.Context, promotionID int) error {
+	args := m.Called(ctx, promotionID)
+	return args.Error(0)
+}
@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+)
+
+type MockScheduleService struct {
+	mock.Mock
+}
+
+func (m *MockScheduleService) Create(ctx context.Context, ownerID int, definition entity.ScheduleDefinition) (*dto.Schedule, error) {
+	args := m.Called(ctx, ownerID, definition)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Schedule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockScheduleService) List(ctx context.Context, ownerID int) ([]dto.Schedule, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.Schedule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockScheduleService) Get(ctx context.Context, ownerID, scheduleID int) (*dto.Schedule, error) {
+	args := m.Called(ctx, ownerID, scheduleID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Schedule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockScheduleService) Update(ctx context.Context, ownerID, scheduleID int, definition entity.ScheduleDefinition) (*dto.Schedule, error) {
+	args := m.Called(ctx, ownerID, scheduleID, definition)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.Schedule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockScheduleService) Delete(ctx context.Context, ownerID, scheduleID int) error {
+	args := m.Called(ctx, ownerID, scheduleID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleService) ListRuns(ctx context.Context, ownerID, scheduleID, limit int) ([]dto.ScheduleRun, error) {
+	args := m.Called(ctx, ownerID, scheduleID, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]dto.ScheduleRun), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginThrottleService struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleService) Check(ctx context.Context, username, clientIP string) error {
+	args := m.Called(ctx, username, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleService) RecordFailure(ctx context.Context, username, clientIP string) {
+	m.Called(ctx, username, clientIP)
+}
+
+func (m *MockLoginThrottleService) RecordSuccess(ctx context.Context, username string) {
+	m.Called(ctx, username)
+}
+
+func (m *MockLoginThrottleService) Unlock(ctx context.Context, username string) error {
+	args := m.Called(ctx, username)
+	return args.Error(0)
+}
@@ -4,13 +4,31 @@ import (
 	"context"
 
 	"github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 )
 
 type MockTransactionService struct {
 	mock.Mock
 }
 
-func (m *MockTransactionService) SendCoins(ctx context.Context, senderID int, toUser string, amount int) error {
-	args := m.Called(ctx, senderID, toUser, amount)
+func (m *MockTransactionService) SendCoins(ctx context.Context, senderID int, toUser string, amount int, note entity.TransferNote) error {
+	args := m.Called(ctx, senderID, toUser, amount, note)
 	return args.Error(0)
 }
+
+func (m *MockTransactionService) GetHistory(ctx context.Context, userID int, filter entity.TransferFilter, cursor string) (*dto.HistoryResponse, error) {
+	args := m.Called(ctx, userID, filter, cursor)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.HistoryResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTransactionService) IssueCoins(ctx context.Context, issuerID int, reason string, grants []entity.CoinGrant) (*dto.GrantResponse, error) {
+	args := m.Called(ctx, issuerID, reason, grants)
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.GrantResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,242 @@
+package oidcservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+// maxUsernameLength matches the size of employees.username.
+const maxUsernameLength = 32
+
+// errCodeRejected is returned when the provider refuses to redeem the code,
+// for example because it has expired or was already used.
+var errCodeRejected = errors.New("authorization code rejected")
+
+var idTokenMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// UsernameClaim names the ID token claim that holds the employee username.
+	UsernameClaim string
+	StateTTL      time.Duration
+}
+
+// OIDCService signs employees in through an OpenID Connect provider using the
+// authorization code flow with PKCE.
+type OIDCService struct {
+	authService service.AuthService
+	stateRepo   database.OIDCStateRepository
+	provider    *provider
+	config      Config
+	httpClient  *http.Client
+}
+
+func NewOIDCService(authService service.AuthService, stateRepo database.OIDCStateRepository, config Config, httpClient *http.Client) *OIDCService {
+	return &OIDCService{
+		authService: authService,
+		stateRepo:   stateRepo,
+		provider:    newProvider(config.Issuer, httpClient),
+		config:      config,
+		httpClient:  httpClient,
+	}
+}
+
+func randomToken(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// AuthCodeURL starts a login and returns the provider URL to redirect the
+// employee to, along with the login state that the callback must come back with.
+func (s *OIDCService) AuthCodeURL(ctx context.Context) (string, string, error) {
+	discovery, err := s.provider.metadata(ctx)
+	if err != nil {
+		log.Printf("failed to discover oidc provider %q: %v", s.config.Issuer, err)
+		return "", "", service.ErrIdentityProviderUnavailable
+	}
+
+	var loginState entity.OIDCLoginState
+	for _, value := range []*string{&loginState.State, &loginState.Nonce, &loginState.CodeVerifier} {
+		if *value, err = randomToken(32); err != nil {
+			log.Println("failed to generate oidc login state:", err)
+			return "", "", service.ErrAuthenticationFailed
+		}
+	}
+
+	if err := s.stateRepo.SaveState(ctx, loginState, s.config.StateTTL); err != nil {
+		log.Printf("failed to save oidc login state: %v", err)
+		return "", "", service.ErrDatabaseError
+	}
+
+	challenge := sha256.Sum256([]byte(loginState.CodeVerifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.config.ClientID},
+		"redirect_uri":          {s.config.RedirectURL},
+		"scope":                 {strings.Join(s.config.Scopes, " ")},
+		"state":                 {loginState.State},
+		"nonce":                 {loginState.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), loginState.State, nil
+}
+
+// Exchange completes a login: it redeems the authorization code, validates the
+// ID token and issues the service's own tokens for the mapped employee.
+func (s *OIDCService) Exchange(ctx context.Context, code, state string) (*dto.AuthResponse, error) {
+	loginState, err := s.stateRepo.ConsumeState(ctx, state)
+	if err != nil {
+		switch err {
+		case database.ErrOIDCStateNotFound:
+			return nil, service.ErrInvalidLoginState
+		default:
+			log.Printf("failed to get oidc login state: %v", err)
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	rawIDToken, err := s.redeemCode(ctx, code, loginState.CodeVerifier)
+	if err != nil {
+		log.Printf("failed to redeem oidc authorization code: %v", err)
+		if errors.Is(err, errCodeRejected) {
+			return nil, service.ErrInvalidLoginState
+		}
+		return nil, service.ErrIdentityProviderUnavailable
+	}
+
+	claims, err := s.verifyIDToken(ctx, rawIDToken, loginState.Nonce)
+	if err != nil {
+		log.Printf("invalid oidc id token: %v", err)
+		return nil, service.ErrInvalidIDToken
+	}
+
+	username, _ := claims[s.config.UsernameClaim].(string)
+	if username == "" || len(username) > maxUsernameLength {
+		log.Printf("oidc id token has no usable %q claim", s.config.UsernameClaim)
+		return nil, service.ErrInvalidIDToken
+	}
+
+	return s.authService.AuthorizeExternal(ctx, username)
+}
+
+func (s *OIDCService) redeemCode(ctx context.Context, code, codeVerifier string) (string, error) {
+	discovery, err := s.provider.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {s.config.RedirectURL},
+		"client_id":     {s.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusBadRequest {
+		return "", fmt.Errorf("%w: %s %s", errCodeRejected, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s: %s", resp.Status, tokenResponse.Error)
+	}
+	if tokenResponse.IDToken == "" {
+		return "", errors.New("token response has no id_token")
+	}
+
+	return tokenResponse.IDToken, nil
+}
+
+func (s *OIDCService) verifyIDToken(ctx context.Context, rawIDToken, nonce string) (jwt.MapClaims, error) {
+	discovery, err := s.provider.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.Parse(rawIDToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.provider.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods(idTokenMethods),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(s.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("unexpected claims type")
+	}
+
+	tokenNonce, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, errors.New("nonce mismatch")
+	}
+
+	// A token issued to several audiences must name this client as the authorized party.
+	if azp, ok := claims["azp"].(string); ok && azp != s.config.ClientID {
+		return nil, errors.New("token was issued to another client")
+	}
+
+	return claims, nil
+}
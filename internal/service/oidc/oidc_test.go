@@ -0,0 +1,276 @@
+package oidcservice_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	databaseMock "github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	serviceMock "github.com/vit6556/avito-internship-assignment/internal/service/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/service/oidc"
+)
+
+// mockProvider is a minimal OpenID provider: it serves discovery, JWKS and a
+// token endpoint that checks the PKCE verifier and returns a signed ID token.
+type mockProvider struct {
+	server        *httptest.Server
+	key           *rsa.PrivateKey
+	codeChallenge string
+	nonce         string
+	// claims lets a test case tamper with the ID token before it is signed.
+	claims func(claims jwt.MapClaims)
+	// signingKey overrides the key the ID token is signed with.
+	signingKey *rsa.PrivateKey
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &mockProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "provider-key",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		clientID, clientSecret, _ := r.BasicAuth()
+		verifier := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if r.PostFormValue("code") != "valid-code" ||
+			base64.RawURLEncoding.EncodeToString(verifier[:]) != p.codeChallenge ||
+			clientID != "shop" || clientSecret != "client-secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":                p.server.URL,
+			"sub":                "0f9a1c",
+			"aud":                "shop",
+			"exp":                time.Now().Add(time.Minute).Unix(),
+			"iat":                time.Now().Unix(),
+			"nonce":              p.nonce,
+			"preferred_username": "alice",
+		}
+		if p.claims != nil {
+			p.claims(claims)
+		}
+
+		signingKey := p.key
+		if p.signingKey != nil {
+			signingKey = p.signingKey
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "provider-key"
+		idToken, _ := token.SignedString(signingKey)
+
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "provider-access-token", "id_token": idToken})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func newOIDCService(p *mockProvider) (*oidcservice.OIDCService, *serviceMock.MockAuthService, *databaseMock.MockOIDCStateRepository) {
+	mockAuthService := new(serviceMock.MockAuthService)
+	mockStateRepo := new(databaseMock.MockOIDCStateRepository)
+
+	oidcService := oidcservice.NewOIDCService(mockAuthService, mockStateRepo, oidcservice.Config{
+		Issuer:        p.server.URL,
+		ClientID:      "shop",
+		ClientSecret:  "client-secret",
+		RedirectURL:   "https://shop.example.com/api/auth/oidc/callback",
+		Scopes:        []string{"openid", "profile"},
+		UsernameClaim: "preferred_username",
+		StateTTL:      10 * time.Minute,
+	}, p.server.Client())
+
+	return oidcService, mockAuthService, mockStateRepo
+}
+
+// startLogin runs AuthCodeURL and lets the provider remember the PKCE
+// challenge and nonce from the authorization request.
+func startLogin(t *testing.T, ctx context.Context, p *mockProvider, oidcService *oidcservice.OIDCService, mockStateRepo *databaseMock.MockOIDCStateRepository) entity.OIDCLoginState {
+	var loginState entity.OIDCLoginState
+	mockStateRepo.On("SaveState", ctx, testifyMock.Anything, 10*time.Minute).
+		Run(func(args testifyMock.Arguments) { loginState = args.Get(1).(entity.OIDCLoginState) }).
+		Return(nil).Once()
+
+	authURL, state, err := oidcService.AuthCodeURL(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, loginState.State, state)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+
+	assert.Equal(t, p.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "shop", query.Get("client_id"))
+	assert.Equal(t, "openid profile", query.Get("scope"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, loginState.State, query.Get("state"))
+	assert.Equal(t, loginState.Nonce, query.Get("nonce"))
+	assert.NotEqual(t, loginState.CodeVerifier, query.Get("code_challenge"))
+
+	p.codeChallenge = query.Get("code_challenge")
+	p.nonce = query.Get("nonce")
+
+	return loginState
+}
+
+func TestExchange(t *testing.T) {
+	ctx := context.Background()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		code          string
+		claims        func(claims jwt.MapClaims)
+		signingKey    *rsa.PrivateKey
+		authorized    bool
+		expectedError error
+	}{
+		{
+			name:          "Success - Employee Signed In",
+			code:          "valid-code",
+			authorized:    true,
+			expectedError: nil,
+		},
+		{
+			name:          "Error - Code Rejected",
+			code:          "expired-code",
+			expectedError: service.ErrInvalidLoginState,
+		},
+		{
+			name:          "Error - Nonce Mismatch",
+			code:          "valid-code",
+			claims:        func(claims jwt.MapClaims) { claims["nonce"] = "replayed" },
+			expectedError: service.ErrInvalidIDToken,
+		},
+		{
+			name:          "Error - Wrong Audience",
+			code:          "valid-code",
+			claims:        func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+			expectedError: service.ErrInvalidIDToken,
+		},
+		{
+			name:          "Error - Wrong Issuer",
+			code:          "valid-code",
+			claims:        func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+			expectedError: service.ErrInvalidIDToken,
+		},
+		{
+			name:          "Error - Expired Token",
+			code:          "valid-code",
+			claims:        func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+			expectedError: service.ErrInvalidIDToken,
+		},
+		{
+			name:          "Error - Forged Signature",
+			code:          "valid-code",
+			signingKey:    otherKey,
+			expectedError: service.ErrInvalidIDToken,
+		},
+		{
+			name:          "Error - Missing Username Claim",
+			code:          "valid-code",
+			claims:        func(claims jwt.MapClaims) { delete(claims, "preferred_username") },
+			expectedError: service.ErrInvalidIDToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newMockProvider(t)
+			p.claims = tt.claims
+			p.signingKey = tt.signingKey
+
+			oidcService, mockAuthService, mockStateRepo := newOIDCService(p)
+			loginState := startLogin(t, ctx, p, oidcService, mockStateRepo)
+
+			mockStateRepo.On("ConsumeState", ctx, loginState.State).Return(&loginState, nil).Once()
+			if tt.authorized {
+				mockAuthService.On("AuthorizeExternal", ctx, "alice").
+					Return(&dto.AuthResponse{Token: "token", RefreshToken: "refresh", ExpiresIn: 900}, nil).Once()
+			}
+
+			response, err := oidcService.Exchange(ctx, tt.code, loginState.State)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "token", response.Token)
+			}
+
+			mockAuthService.AssertExpectations(t)
+			mockStateRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExchangeInvalidState(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(t)
+	oidcService, mockAuthService, mockStateRepo := newOIDCService(p)
+
+	mockStateRepo.On("ConsumeState", ctx, "unknown").Return(nil, database.ErrOIDCStateNotFound).Once()
+
+	_, err := oidcService.Exchange(ctx, "valid-code", "unknown")
+
+	assert.Equal(t, service.ErrInvalidLoginState, err)
+	mockAuthService.AssertExpectations(t)
+	mockStateRepo.AssertExpectations(t)
+}
+
+func TestAuthCodeURLProviderUnavailable(t *testing.T) {
+	ctx := context.Background()
+	p := newMockProvider(t)
+	oidcService, _, mockStateRepo := newOIDCService(p)
+	p.server.Close()
+
+	_, _, err := oidcService.AuthCodeURL(ctx)
+
+	assert.Equal(t, service.ErrIdentityProviderUnavailable, err)
+	mockStateRepo.AssertExpectations(t)
+}
@@ -0,0 +1,92 @@
+package scheduleservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "Every Minute", expr: "* * * * *"},
+		{name: "Lists, Ranges And Steps", expr: "0,30 9-17/2 1 */3 1-5"},
+		{name: "Sunday As Seven", expr: "0 0 * * 7"},
+		{name: "Too Few Fields", expr: "0 9 * *", wantErr: true},
+		{name: "Out Of Range", expr: "0 24 * * *", wantErr: true},
+		{name: "Reversed Range", expr: "0 17-9 * * *", wantErr: true},
+		{name: "Zero Step", expr: "*/0 * * * *", wantErr: true},
+		{name: "Not A Number", expr: "0 9 * * mon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCron(tt.expr)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	after := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name     string
+		expr     string
+		location *time.Location
+		expected time.Time
+	}{
+		{
+			name:     "Next Minute",
+			expr:     "* * * * *",
+			location: time.UTC,
+			expected: time.Date(2024, 6, 3, 9, 1, 0, 0, time.UTC),
+		},
+		{
+			name:     "Same Time Next Week",
+			expr:     "0 9 * * 1",
+			location: time.UTC,
+			expected: time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Evaluated In Location",
+			expr:     "0 9 * * *",
+			location: moscow,
+			expected: time.Date(2024, 6, 4, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Day Of Month Or Day Of Week",
+			expr:     "0 0 15 * 5",
+			location: time.UTC,
+			expected: time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Step Starting At Value",
+			expr:     "5/20 * * * *",
+			location: time.UTC,
+			expected: time.Date(2024, 6, 3, 9, 5, 0, 0, time.UTC),
+		},
+		{
+			name:     "Never Fires",
+			expr:     "0 0 30 2 *",
+			location: time.UTC,
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := parseCron(tt.expr)
+			require.NoError(t, err)
+
+			next := spec.next(after, tt.location)
+			assert.True(t, tt.expected.Equal(next), "expected %s, got %s", tt.expected, next)
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package scheduleservice
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+const (
+	maxRecipients    = 100
+	maxMessageLength = 200
+	minInterval      = time.Minute
+
+	defaultRunsLimit = 50
+	maxRunsLimit     = 200
+
+	dueBatchSize = 100
+)
+
+// ScheduleService manages scheduled transfers and runs the due ones through
+// the transaction service, so they follow the same rules as manual transfers.
+type ScheduleService struct {
+	employeeRepo       database.EmployeeRepository
+	scheduleRepo       database.ScheduleRepository
+	transactionService service.TransactionService
+	location           *time.Location
+}
+
+func NewScheduleService(employeeRepo database.EmployeeRepository, scheduleRepo database.ScheduleRepository, transactionService service.TransactionService, location *time.Location) *ScheduleService {
+	return &ScheduleService{
+		employeeRepo:       employeeRepo,
+		scheduleRepo:       scheduleRepo,
+		transactionService: transactionService,
+		location:           location,
+	}
+}
+
+func (s *ScheduleService) Create(ctx context.Context, ownerID int, definition entity.ScheduleDefinition) (*dto.Schedule, error) {
+	now := time.Now().UTC()
+	schedule, err := s.buildSchedule(ctx, ownerID, definition, now)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.ID, err = s.scheduleRepo.CreateSchedule(ctx, *schedule)
+	if err != nil {
+		log.Printf("failed to create scheduled transfer for user %d: %v", ownerID, err)
+		return nil, service.ErrDatabaseError
+	}
+	schedule.CreatedAt = now
+
+	return mapScheduleToDTO(schedule), nil
+}
+
+func (s *ScheduleService) List(ctx context.Context, ownerID int) ([]dto.Schedule, error) {
+	schedules, err := s.scheduleRepo.ListSchedules(ctx, ownerID)
+	if err != nil {
+		log.Printf("failed to list scheduled transfers of user %d: %v", ownerID, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	result := make([]dto.Schedule, len(schedules))
+	for i, schedule := range schedules {
+		result[i] = *mapScheduleToDTO(schedule)
+	}
+
+	return result, nil
+}
+
+func (s *ScheduleService) Get(ctx context.Context, ownerID, scheduleID int) (*dto.Schedule, error) {
+	schedule, err := s.getOwnSchedule(ctx, ownerID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapScheduleToDTO(schedule), nil
+}
+
+// Update replaces the definition of a schedule. The next run is computed
+// again from the current time.
+func (s *ScheduleService) Update(ctx context.Context, ownerID, scheduleID int, definition entity.ScheduleDefinition) (*dto.Schedule, error) {
+	existing, err := s.getOwnSchedule(ctx, ownerID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.buildSchedule(ctx, ownerID, definition, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	schedule.ID = existing.ID
+	schedule.CreatedAt = existing.CreatedAt
+
+	err = s.scheduleRepo.UpdateSchedule(ctx, *schedule)
+	if err != nil {
+		log.Printf("failed to update scheduled transfer %d: %v", scheduleID, err)
+		switch err {
+		case database.ErrScheduleNotFound:
+			return nil, service.ErrScheduleNotFound
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	return mapScheduleToDTO(schedule), nil
+}
+
+func (s *ScheduleService) Delete(ctx context.Context, ownerID, scheduleID int) error {
+	if _, err := s.getOwnSchedule(ctx, ownerID, scheduleID); err != nil {
+		return err
+	}
+
+	err := s.scheduleRepo.DeleteSchedule(ctx, scheduleID)
+	if err != nil {
+		log.Printf("failed to delete scheduled transfer %d: %v", scheduleID, err)
+		switch err {
+		case database.ErrScheduleNotFound:
+			return service.ErrScheduleNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	return nil
+}
+
+func (s *ScheduleService) ListRuns(ctx context.Context, ownerID, scheduleID, limit int) ([]dto.ScheduleRun, error) {
+	if _, err := s.getOwnSchedule(ctx, ownerID, scheduleID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultRunsLimit
+	}
+	if limit > maxRunsLimit {
+		limit = maxRunsLimit
+	}
+
+	runs, err := s.scheduleRepo.ListScheduleRuns(ctx, scheduleID, limit)
+	if err != nil {
+		log.Printf("failed to list runs of scheduled transfer %d: %v", scheduleID, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	result := make([]dto.ScheduleRun, len(runs))
+	for i, run := range runs {
+		result[i] = dto.ScheduleRun{
+			ID:           run.ID,
+			Recipient:    run.Recipient,
+			Amount:       run.Amount,
+			Status:       run.Status,
+			Error:        run.Error,
+			ScheduledFor: run.ScheduledFor,
+			Timestamp:    run.Timestamp,
+		}
+	}
+
+	return result, nil
+}
+
+// RunDue runs the schedules that are due at now and returns how many it ran.
+// Each schedule is moved to its next run before its transfers are made, so a
+// run is never repeated; runs missed while no worker was up are not caught up.
+func (s *ScheduleService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.scheduleRepo.ListDueSchedules(ctx, now, dueBatchSize)
+	if err != nil {
+		log.Printf("failed to list due scheduled transfers: %v", err)
+		return 0, service.ErrDatabaseError
+	}
+
+	ran := 0
+	for _, schedule := range due {
+		nextRunAt := s.nextRun(schedule, now)
+		if nextRunAt.IsZero() {
+			log.Printf("scheduled transfer %d has no next run", schedule.ID)
+			continue
+		}
+
+		claimed, err := s.scheduleRepo.AdvanceSchedule(ctx, schedule.ID, schedule.NextRunAt, nextRunAt)
+		if err != nil {
+			log.Printf("failed to claim scheduled transfer %d: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		s.runSchedule(ctx, schedule)
+		ran++
+	}
+
+	return ran, nil
+}
+
+func (s *ScheduleService) runSchedule(ctx context.Context, schedule *entity.ScheduledTransfer) {
+	for _, recipient := range schedule.Recipients {
+		run := entity.ScheduleRun{
+			ScheduleID:   schedule.ID,
+			RecipientID:  recipient.EmployeeID,
+			Amount:       schedule.Amount,
+			Status:       entity.ScheduleRunSucceeded,
+			ScheduledFor: schedule.NextRunAt,
+		}
+
+		err := s.transactionService.SendCoins(ctx, schedule.OwnerID, recipient.Username, schedule.Amount, schedule.Note)
+		if err != nil {
+			log.Printf("scheduled transfer %d to %q failed: %v", schedule.ID, recipient.Username, err)
+			run.Status = entity.ScheduleRunFailed
+			run.Error = err.Error()
+		}
+
+		if err := s.scheduleRepo.CreateScheduleRun(ctx, run); err != nil {
+			log.Printf("failed to record run of scheduled transfer %d: %v", schedule.ID, err)
+		}
+	}
+}
+
+func (s *ScheduleService) getOwnSchedule(ctx context.Context, ownerID, scheduleID int) (*entity.ScheduledTransfer, error) {
+	schedule, err := s.scheduleRepo.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		switch err {
+		case database.ErrScheduleNotFound:
+			return nil, service.ErrScheduleNotFound
+		default:
+			log.Printf("failed to get scheduled transfer %d: %v", scheduleID, err)
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	if schedule.OwnerID != ownerID {
+		return nil, service.ErrScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+func (s *ScheduleService) buildSchedule(ctx context.Context, ownerID int, definition entity.ScheduleDefinition, now time.Time) (*entity.ScheduledTransfer, error) {
+	if definition.Amount <= 0 || len(definition.Recipients) == 0 || len(definition.Recipients) > maxRecipients {
+		return nil, service.ErrInvalidSchedule
+	}
+
+	if (definition.Cron == "") == (definition.Interval == 0) {
+		return nil, service.ErrInvalidSchedule
+	}
+	if definition.Cron != "" {
+		if _, err := parseCron(definition.Cron); err != nil {
+			return nil, service.ErrInvalidSchedule
+		}
+	}
+	if definition.Cron == "" && definition.Interval < minInterval {
+		return nil, service.ErrInvalidSchedule
+	}
+
+	if utf8.RuneCountInString(definition.Note.Message) > maxMessageLength {
+		return nil, service.ErrInvalidMessage
+	}
+	if definition.Note.Category != "" && !slices.Contains(entity.TransferCategories, definition.Note.Category) {
+		return nil, service.ErrInvalidCategory
+	}
+
+	schedule := &entity.ScheduledTransfer{
+		OwnerID:    ownerID,
+		Name:       definition.Name,
+		Recipients: make([]entity.ScheduleRecipient, 0, len(definition.Recipients)),
+		Amount:     definition.Amount,
+		Note:       definition.Note,
+		Cron:       definition.Cron,
+		Interval:   definition.Interval,
+		Active:     definition.Active,
+	}
+
+	seen := make(map[int]struct{}, len(definition.Recipients))
+	for _, username := range definition.Recipients {
+		employee, err := s.employeeRepo.GetEmployeeByUsername(ctx, username)
+		if err != nil {
+			log.Printf("schedule recipient %q not found: %v", username, err)
+			return nil, service.ErrEmployeeNotFound
+		}
+
+		if employee.ID == ownerID {
+			return nil, service.ErrSelfTransaction
+		}
+
+		if _, ok := seen[employee.ID]; ok {
+			continue
+		}
+		seen[employee.ID] = struct{}{}
+		schedule.Recipients = append(schedule.Recipients, entity.ScheduleRecipient{EmployeeID: employee.ID, Username: employee.Username})
+	}
+
+	schedule.NextRunAt = s.nextRun(schedule, now)
+	if schedule.NextRunAt.IsZero() {
+		// The cron expression can never fire, for example on February 30th.
+		return nil, service.ErrInvalidSchedule
+	}
+
+	return schedule, nil
+}
+
+func (s *ScheduleService) nextRun(schedule *entity.ScheduledTransfer, after time.Time) time.Time {
+	if schedule.Cron == "" {
+		return after.Add(schedule.Interval).UTC().Truncate(time.Second)
+	}
+
+	spec, err := parseCron(schedule.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+
+	next := spec.next(after, s.location)
+	if next.IsZero() {
+		return next
+	}
+	return next.UTC()
+}
+
+func mapScheduleToDTO(schedule *entity.ScheduledTransfer) *dto.Schedule {
+	recipients := make([]string, len(schedule.Recipients))
+	for i, recipient := range schedule.Recipients {
+		recipients[i] = recipient.Username
+	}
+
+	result := &dto.Schedule{
+		ID:         schedule.ID,
+		Name:       schedule.Name,
+		Recipients: recipients,
+		Amount:     schedule.Amount,
+		Message:    schedule.Note.Message,
+		Category:   schedule.Note.Category,
+		Cron:       schedule.Cron,
+		Active:     schedule.Active,
+		NextRunAt:  schedule.NextRunAt,
+		CreatedAt:  schedule.CreatedAt,
+	}
+	if schedule.Interval > 0 {
+		result.Interval = schedule.Interval.String()
+	}
+
+	return result
+}
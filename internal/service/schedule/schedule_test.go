@@ -0,0 +1,303 @@
+package scheduleservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	serviceMock "github.com/vit6556/avito-internship-assignment/internal/service/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/service/schedule"
+)
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockScheduleRepo := new(mock.MockScheduleRepository)
+	mockTransactionService := new(serviceMock.MockTransactionService)
+	scheduleService := scheduleservice.NewScheduleService(mockEmployeeRepo, mockScheduleRepo, mockTransactionService, time.UTC)
+
+	tests := []struct {
+		name          string
+		definition    entity.ScheduleDefinition
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Cron Schedule",
+			definition: entity.ScheduleDefinition{
+				Recipients: []string{"bob", "carol", "bob"},
+				Amount:     10,
+				Note:       entity.TransferNote{Message: "weekly thanks", Category: entity.TransferCategoryThanks},
+				Cron:       "0 9 * * 1",
+				Active:     true,
+			},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "carol").Return(&entity.Employee{ID: 3, Username: "carol"}, nil)
+				mockScheduleRepo.On("CreateSchedule", ctx, testifyMock.MatchedBy(func(schedule entity.ScheduledTransfer) bool {
+					return schedule.OwnerID == 1 && len(schedule.Recipients) == 2 &&
+						schedule.NextRunAt.Weekday() == time.Monday && schedule.NextRunAt.Hour() == 9
+				})).Return(4, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:       "Error - Both Cron And Interval",
+			definition: entity.ScheduleDefinition{Recipients: []string{"bob"}, Amount: 10, Cron: "0 9 * * 1", Interval: time.Hour},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidSchedule,
+		},
+		{
+			name:       "Error - Invalid Cron",
+			definition: entity.ScheduleDefinition{Recipients: []string{"bob"}, Amount: 10, Cron: "61 * * * *"},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidSchedule,
+		},
+		{
+			name:       "Error - Cron Never Fires",
+			definition: entity.ScheduleDefinition{Recipients: []string{"bob"}, Amount: 10, Cron: "0 0 30 2 *"},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+			},
+			expectedError: service.ErrInvalidSchedule,
+		},
+		{
+			name:       "Error - Interval Too Short",
+			definition: entity.ScheduleDefinition{Recipients: []string{"bob"}, Amount: 10, Interval: 30 * time.Second},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidSchedule,
+		},
+		{
+			name: "Error - Invalid Category",
+			definition: entity.ScheduleDefinition{
+				Recipients: []string{"bob"},
+				Amount:     10,
+				Note:       entity.TransferNote{Category: "salary"},
+				Interval:   time.Hour,
+			},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidCategory,
+		},
+		{
+			name:       "Error - Recipient Not Found",
+			definition: entity.ScheduleDefinition{Recipients: []string{"ghost"}, Amount: 10, Interval: time.Hour},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "ghost").Return(nil, database.ErrEmployeeNotFound)
+			},
+			expectedError: service.ErrEmployeeNotFound,
+		},
+		{
+			name:       "Error - Owner Among Recipients",
+			definition: entity.ScheduleDefinition{Recipients: []string{"alice"}, Amount: 10, Interval: time.Hour},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").Return(&entity.Employee{ID: 1, Username: "alice"}, nil)
+			},
+			expectedError: service.ErrSelfTransaction,
+		},
+		{
+			name:       "Error - Database Error",
+			definition: entity.ScheduleDefinition{Recipients: []string{"bob"}, Amount: 10, Interval: time.Hour},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockScheduleRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+				mockScheduleRepo.On("CreateSchedule", ctx, testifyMock.Anything).Return(0, database.ErrDatabaseInsertFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			schedule, err := scheduleService.Create(ctx, 1, tt.definition)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, 4, schedule.ID)
+				assert.Equal(t, []string{"bob", "carol"}, schedule.Recipients)
+				assert.True(t, schedule.NextRunAt.After(time.Now()))
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockScheduleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOwnership(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockScheduleRepo := new(mock.MockScheduleRepository)
+	mockTransactionService := new(serviceMock.MockTransactionService)
+	scheduleService := scheduleservice.NewScheduleService(mockEmployeeRepo, mockScheduleRepo, mockTransactionService, time.UTC)
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Own Schedule Deleted",
+			mockSetup: func() {
+				mockScheduleRepo.ExpectedCalls = nil
+				mockScheduleRepo.On("GetSchedule", ctx, 4).Return(&entity.ScheduledTransfer{ID: 4, OwnerID: 1}, nil)
+				mockScheduleRepo.On("DeleteSchedule", ctx, 4).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Schedule Of Another Employee",
+			mockSetup: func() {
+				mockScheduleRepo.ExpectedCalls = nil
+				mockScheduleRepo.On("GetSchedule", ctx, 4).Return(&entity.ScheduledTransfer{ID: 4, OwnerID: 2}, nil)
+			},
+			expectedError: service.ErrScheduleNotFound,
+		},
+		{
+			name: "Error - Schedule Not Found",
+			mockSetup: func() {
+				mockScheduleRepo.ExpectedCalls = nil
+				mockScheduleRepo.On("GetSchedule", ctx, 4).Return(nil, database.ErrScheduleNotFound)
+			},
+			expectedError: service.ErrScheduleNotFound,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockScheduleRepo.ExpectedCalls = nil
+				mockScheduleRepo.On("GetSchedule", ctx, 4).Return(nil, database.ErrDatabaseQueryFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := scheduleService.Delete(ctx, 1, 4)
+
+			assert.Equal(t, tt.expectedError, err)
+			mockScheduleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockScheduleRepo := new(mock.MockScheduleRepository)
+	mockTransactionService := new(serviceMock.MockTransactionService)
+	scheduleService := scheduleservice.NewScheduleService(mockEmployeeRepo, mockScheduleRepo, mockTransactionService, time.UTC)
+
+	now := time.Date(2024, 6, 3, 9, 0, 30, 0, time.UTC)
+	dueAt := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	note := entity.TransferNote{Message: "weekly thanks"}
+
+	newSchedule := func() *entity.ScheduledTransfer {
+		return &entity.ScheduledTransfer{
+			ID:      4,
+			OwnerID: 1,
+			Recipients: []entity.ScheduleRecipient{
+				{EmployeeID: 2, Username: "bob"},
+				{EmployeeID: 3, Username: "carol"},
+			},
+			Amount:    10,
+			Note:      note,
+			Cron:      "0 9 * * 1",
+			Active:    true,
+			NextRunAt: dueAt,
+		}
+	}
+	nextRunAt := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedRan   int
+		expectedError error
+	}{
+		{
+			name: "Success - Transfers Made And Recorded",
+			mockSetup: func() {
+				mockScheduleRepo.ExpectedCalls = nil
+				mockTransactionService.ExpectedCalls = nil
+				mockScheduleRepo.On("ListDueSchedules", ctx, now, 100).Return([]*entity.ScheduledTransfer{newSchedule()}, nil)
+				mockScheduleRepo.On("AdvanceSchedule", ctx, 4, dueAt, nextRunAt).Return(true, nil)
+				mockTransactionService.On("SendCoins", ctx, 1, "bob", 10, note).Return(nil).Once()
+				mockTransactionService.On("SendCoins", ctx, 1, "carol", 10, note).Return(service.ErrInsufficientFunds).Once()
+				mockScheduleRepo.On("CreateScheduleRun", ctx, entity.ScheduleRun{
+					ScheduleID: 4, RecipientID: 2, Amount: 10, Status: entity.ScheduleRunSucceeded, ScheduledFor: dueAt,
+				}).Return(nil).Once()
+				mockScheduleRepo.On("CreateScheduleRun", ctx, entity.ScheduleRun{
+					ScheduleID: 4, RecipientID: 3, Amount: 10, Status: entity.ScheduleRunFailed,
+					Error: service.ErrInsufficientFunds.Error(), ScheduledFor: dueAt,
+				}).Return(nil).Once()
+			},
+			expectedRan:   1,
+			expectedError: nil,
+		},
+		{
+			name: "Success - Claimed By Another Instance",
+			mockSetup: func() {
+				mockScheduleRepo.ExpectedCalls = nil
+				mockTransactionService.ExpectedCalls = nil
+				mockScheduleRepo.On("ListDueSchedules", ctx, now, 100).Return([]*entity.ScheduledTransfer{newSchedule()}, nil)
+				mockScheduleRepo.On("AdvanceSchedule", ctx, 4, dueAt, nextRunAt).Return(false, nil)
+			},
+			expectedRan:   0,
+			expectedError: nil,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockScheduleRepo.ExpectedCalls = nil
+				mockTransactionService.ExpectedCalls = nil
+				mockScheduleRepo.On("ListDueSchedules", ctx, now, 100).Return(nil, database.ErrDatabaseQueryFailed)
+			},
+			expectedRan:   0,
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			ran, err := scheduleService.RunDue(ctx, now)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedRan, ran)
+			mockScheduleRepo.AssertExpectations(t)
+			mockTransactionService.AssertExpectations(t)
+		})
+	}
+}
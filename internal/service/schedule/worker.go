@@ -0,0 +1,63 @@
+package scheduleservice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+)
+
+// Worker polls for due scheduled transfers. Every instance of the service
+// runs a worker, but only the one holding the leader lock runs transfers.
+type Worker struct {
+	scheduleService *ScheduleService
+	lock            database.LeaderLock
+	pollInterval    time.Duration
+}
+
+func NewWorker(scheduleService *ScheduleService, lock database.LeaderLock, pollInterval time.Duration) *Worker {
+	return &Worker{
+		scheduleService: scheduleService,
+		lock:            lock,
+		pollInterval:    pollInterval,
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := w.lock.Release(releaseCtx); err != nil {
+				log.Printf("failed to release scheduler leadership: %v", err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) poll(ctx context.Context) {
+	leader, err := w.lock.TryAcquire(ctx)
+	if err != nil || !leader {
+		return
+	}
+
+	// A batch that has started is finished even if shutdown begins meanwhile,
+	// so no claimed run is left without its transfers.
+	ran, err := w.scheduleService.RunDue(context.WithoutCancel(ctx), time.Now().UTC())
+	if err != nil {
+		log.Printf("failed to run scheduled transfers: %v", err)
+		return
+	}
+	if ran > 0 {
+		log.Printf("ran %d scheduled transfers", ran)
+	}
+}
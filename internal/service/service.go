@@ -3,39 +3,182 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 )
 
 var (
-	ErrInvalidCredentials   = errors.New("invalid username or password")
-	ErrInvalidToken         = errors.New("invalid or expired token")
-	ErrAuthenticationFailed = errors.New("authentication failed")
+	ErrInvalidCredentials     = errors.New("invalid username or password")
+	ErrInvalidToken           = errors.New("invalid or expired token")
+	ErrAuthenticationFailed   = errors.New("authentication failed")
+	ErrInvalidRefreshToken    = errors.New("invalid or expired refresh token")
+	ErrRegistrationNotAllowed = errors.New("registration is not allowed")
+	ErrTooManyLoginAttempts   = errors.New("too many failed login attempts")
+
+	ErrInvalidPasswordResetToken = errors.New("invalid or expired password reset token")
+	ErrPasswordUpdateFailed      = errors.New("failed to update password")
+
+	ErrIdentityProviderUnavailable = errors.New("identity provider unavailable")
+	ErrInvalidLoginState           = errors.New("invalid or expired login state")
+	ErrInvalidIDToken              = errors.New("invalid id token")
+
+	ErrInvalidAPIKey        = errors.New("invalid or expired api key")
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrAPIKeyCreationFailed = errors.New("failed to create api key")
+	ErrInvalidScope         = errors.New("invalid api key scope")
+	ErrInvalidExpiry        = errors.New("expiry must be in the future")
 
 	ErrDatabaseError = errors.New("database operation failed")
 
-	ErrMerchNotFound = errors.New("merch not found")
+	ErrMerchNotFound        = errors.New("merch not found")
+	ErrMerchAlreadyExists   = errors.New("merch already exists")
+	ErrInvalidMerchCategory = errors.New("invalid merch category")
+
+	ErrPromotionNotFound      = errors.New("promotion not found")
+	ErrInvalidPromotion       = errors.New("invalid promotion")
+	ErrPromoCodeAlreadyExists = errors.New("promo code already exists")
+	ErrInvalidPromoCode       = errors.New("invalid or expired promo code")
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to the order")
+	ErrPromoCodeExhausted     = errors.New("promo code usage limit reached")
 
 	ErrEmployeeCreationFailed = errors.New("failed to create employee")
 	ErrEmployeeNotFound       = errors.New("employee not found")
+	ErrEmployeeAlreadyExists  = errors.New("employee already exists")
+	ErrInvalidRole            = errors.New("invalid role")
+	ErrInvalidOffice          = errors.New("invalid office")
 
 	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrOutOfStock        = errors.New("merch out of stock")
 	ErrSelfTransaction   = errors.New("sender and receiver cannot be the same user")
+	ErrInvalidCursor     = errors.New("invalid pagination cursor")
+	ErrInvalidGrant      = errors.New("invalid coin grant")
+	ErrInvalidCart       = errors.New("invalid cart")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrOrderTransition   = errors.New("order status does not allow this change")
+	ErrCancelWindowEnded = errors.New("order cancellation window has ended")
+	ErrInvalidCategory   = errors.New("invalid transfer category")
+	ErrInvalidMessage    = errors.New("invalid transfer message")
+	ErrConcurrentUpdate  = errors.New("operation conflicted with a concurrent update")
+
+	ErrScheduleNotFound = errors.New("scheduled transfer not found")
+	ErrInvalidSchedule  = errors.New("invalid transfer schedule")
+
+	ErrPendingTransferNotFound = errors.New("pending transfer not found")
+	ErrPendingTransferResolved = errors.New("pending transfer already resolved")
+	ErrPendingTransferExpired  = errors.New("pending transfer expired")
+
+	ErrTransferNotFound       = errors.New("transfer not found")
+	ErrTransferNotDisputable  = errors.New("transfer cannot be disputed")
+	ErrDisputeNotFound        = errors.New("dispute not found")
+	ErrDisputeAlreadyOpen     = errors.New("transfer already has an open or approved dispute")
+	ErrDisputeAlreadyResolved = errors.New("dispute already resolved")
+	ErrInvalidDispute         = errors.New("invalid dispute")
+
+	ErrIdempotencyKeyMismatch   = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
 )
 
 type AuthService interface {
-	AuthorizeUser(ctx context.Context, username, password string) (string, error)
-	ValidateToken(tokenString string) (int, error)
+	AuthorizeUser(ctx context.Context, username, password string) (*dto.AuthResponse, error)
+	AuthorizeExternal(ctx context.Context, username string) (*dto.AuthResponse, error)
+	Register(ctx context.Context, username, password, inviteCode string) (*dto.AuthResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthResponse, error)
+	Logout(ctx context.Context, claims *entity.TokenClaims, refreshToken string) error
+	ValidateToken(ctx context.Context, tokenString string) (*entity.TokenClaims, error)
+	JWKS() dto.JWKS
+	ChangePassword(ctx context.Context, userID int, clientIP, currentPassword, newPassword string) error
+	IssuePasswordReset(ctx context.Context, issuerID int, username string) (*dto.PasswordResetResponse, error)
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, creatorID int, username, name string, scopes []string, expiresAt *time.Time) (*dto.CreatedAPIKey, error)
+	List(ctx context.Context) ([]dto.APIKey, error)
+	Revoke(ctx context.Context, keyID int) error
+	Authenticate(ctx context.Context, rawKey string) (*entity.APIKey, error)
+}
+
+type OIDCService interface {
+	AuthCodeURL(ctx context.Context) (string, string, error)
+	Exchange(ctx context.Context, code, state string) (*dto.AuthResponse, error)
+}
+
+type LoginThrottleService interface {
+	Check(ctx context.Context, username, clientIP string) error
+	RecordFailure(ctx context.Context, username, clientIP string)
+	RecordSuccess(ctx context.Context, username string)
+	Unlock(ctx context.Context, username string) error
 }
 
 type EmployeeService interface {
 	GetEmployeeInfo(ctx context.Context, id int) (*dto.EmployeeInfoResponse, error)
+	SetRole(ctx context.Context, username, role string) error
+	SetOffice(ctx context.Context, username, office string) error
 }
 
 type MerchService interface {
 	BuyItem(ctx context.Context, userID int, itemName string) error
+	Checkout(ctx context.Context, userID int, cart []entity.OrderLine, promoCode string) (*dto.OrderResponse, error)
+}
+
+type PromotionService interface {
+	Create(ctx context.Context, adminID int, promotion entity.Promotion) (*dto.Promotion, error)
+	List(ctx context.Context) ([]dto.Promotion, error)
+	Disable(ctx context.Context, promotionID int) error
+}
+
+type OrderService interface {
+	ListOrders(ctx context.Context, userID int) ([]dto.Order, error)
+	ListAll(ctx context.Context, filter entity.OrderFilter) ([]dto.Order, error)
+	Cancel(ctx context.Context, userID, orderID int) (*dto.Order, error)
+	Advance(ctx context.Context, orderID int, step string) (*dto.Order, error)
+	Refund(ctx context.Context, orderID int) (*dto.Order, error)
+}
+
+type MerchCatalogService interface {
+	ListItems(ctx context.Context) ([]dto.MerchItem, error)
+	GetItem(ctx context.Context, name string) (*dto.MerchItem, error)
+	CreateItem(ctx context.Context, name string, price int, stock *int, category string) (*dto.MerchItem, error)
+	UpdatePrice(ctx context.Context, name string, price int) (*dto.MerchItem, error)
+	SetCategory(ctx context.Context, name, category string) error
+	RetireItem(ctx context.Context, name string) error
+	Restock(ctx context.Context, name string, quantity int) (*dto.MerchItem, error)
 }
 
 type TransactionService interface {
-	SendCoins(ctx context.Context, senderID int, toUser string, amount int) error
+	SendCoins(ctx context.Context, senderID int, toUser string, amount int, note entity.TransferNote) error
+	GetHistory(ctx context.Context, userID int, filter entity.TransferFilter, cursor string) (*dto.HistoryResponse, error)
+	IssueCoins(ctx context.Context, issuerID int, reason string, grants []entity.CoinGrant) (*dto.GrantResponse, error)
+}
+
+type EscrowService interface {
+	Hold(ctx context.Context, senderID int, toUser string, amount int, note entity.TransferNote) (*dto.PendingTransfer, error)
+	ListPending(ctx context.Context, userID int) (*dto.PendingTransfersResponse, error)
+	Accept(ctx context.Context, userID, transferID int) (*dto.PendingTransfer, error)
+	Decline(ctx context.Context, userID, transferID int) (*dto.PendingTransfer, error)
+}
+
+type DisputeService interface {
+	Open(ctx context.Context, userID, transactionID int, reason string) (*dto.Dispute, error)
+	List(ctx context.Context, userID int) ([]dto.Dispute, error)
+	ListAll(ctx context.Context, status string) ([]dto.Dispute, error)
+	Approve(ctx context.Context, adminID, disputeID int, note string, force bool) (*dto.Dispute, error)
+	Reject(ctx context.Context, adminID, disputeID int, note string) (*dto.Dispute, error)
+}
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, userID int, key, fingerprint string) (*entity.IdempotencyRecord, error)
+	Complete(ctx context.Context, userID int, key string, status int, body []byte) error
+	Release(ctx context.Context, userID int, key string) error
+}
+
+type ScheduleService interface {
+	Create(ctx context.Context, ownerID int, definition entity.ScheduleDefinition) (*dto.Schedule, error)
+	List(ctx context.Context, ownerID int) ([]dto.Schedule, error)
+	Get(ctx context.Context, ownerID, scheduleID int) (*dto.Schedule, error)
+	Update(ctx context.Context, ownerID, scheduleID int, definition entity.ScheduleDefinition) (*dto.Schedule, error)
+	Delete(ctx context.Context, ownerID, scheduleID int) error
+	ListRuns(ctx context.Context, ownerID, scheduleID, limit int) ([]dto.ScheduleRun, error)
 }
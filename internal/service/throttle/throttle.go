@@ -0,0 +1,117 @@
+package throttleservice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+// Policy describes when failed logins lead to a lockout. Once a key reaches
+// its failure limit it is locked for BaseLockout, and every further failure
+// doubles the lockout up to MaxLockout.
+type Policy struct {
+	MaxFailures      int
+	MaxFailuresPerIP int
+	Window           time.Duration
+	BaseLockout      time.Duration
+	MaxLockout       time.Duration
+}
+
+type LoginThrottleService struct {
+	employeeRepo database.EmployeeRepository
+	throttleRepo database.LoginThrottleRepository
+	policy       Policy
+}
+
+func NewLoginThrottleService(employeeRepo database.EmployeeRepository, throttleRepo database.LoginThrottleRepository, policy Policy) *LoginThrottleService {
+	return &LoginThrottleService{
+		employeeRepo: employeeRepo,
+		throttleRepo: throttleRepo,
+		policy:       policy,
+	}
+}
+
+// Check returns ErrTooManyLoginAttempts while either the username or the
+// client address is locked out.
+func (s *LoginThrottleService) Check(ctx context.Context, username, clientIP string) error {
+	keys := []struct{ scope, key string }{
+		{entity.LoginScopeUsername, username},
+		{entity.LoginScopeIP, clientIP},
+	}
+
+	for _, k := range keys {
+		lockout, err := s.throttleRepo.GetLockout(ctx, k.scope, k.key)
+		if err != nil {
+			log.Printf("failed to check login lockout for %s %q: %v", k.scope, k.key, err)
+			return service.ErrDatabaseError
+		}
+
+		if lockout > 0 {
+			return service.ErrTooManyLoginAttempts
+		}
+	}
+
+	return nil
+}
+
+func (s *LoginThrottleService) RecordFailure(ctx context.Context, username, clientIP string) {
+	s.recordFailure(ctx, entity.LoginScopeUsername, username, s.policy.MaxFailures)
+	s.recordFailure(ctx, entity.LoginScopeIP, clientIP, s.policy.MaxFailuresPerIP)
+}
+
+// RecordSuccess clears the failures of the username. Failures of the client
+// address are kept so that a valid login cannot be used to reset them.
+func (s *LoginThrottleService) RecordSuccess(ctx context.Context, username string) {
+	if err := s.throttleRepo.Reset(ctx, entity.LoginScopeUsername, username); err != nil {
+		log.Printf("failed to reset login failures of %q: %v", username, err)
+	}
+}
+
+func (s *LoginThrottleService) Unlock(ctx context.Context, username string) error {
+	_, err := s.employeeRepo.GetEmployeeByUsername(ctx, username)
+	if err != nil {
+		log.Printf("employee %q not found: %v", username, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return service.ErrEmployeeNotFound
+		default:
+			return service.ErrDatabaseError
+		}
+	}
+
+	if err := s.throttleRepo.Reset(ctx, entity.LoginScopeUsername, username); err != nil {
+		log.Printf("failed to unlock employee %q: %v", username, err)
+		return service.ErrDatabaseError
+	}
+
+	return nil
+}
+
+func (s *LoginThrottleService) recordFailure(ctx context.Context, scope, key string, maxFailures int) {
+	failures, err := s.throttleRepo.RecordFailure(ctx, scope, key, s.policy.Window)
+	if err != nil {
+		log.Printf("failed to record login failure for %s %q: %v", scope, key, err)
+		return
+	}
+
+	if failures < maxFailures {
+		return
+	}
+
+	if err := s.throttleRepo.Lock(ctx, scope, key, s.lockoutFor(failures-maxFailures)); err != nil {
+		log.Printf("failed to lock %s %q: %v", scope, key, err)
+	}
+}
+
+func (s *LoginThrottleService) lockoutFor(extraFailures int) time.Duration {
+	lockout := s.policy.BaseLockout
+	for i := 0; i < extraFailures && lockout < s.policy.MaxLockout; i++ {
+		lockout *= 2
+	}
+
+	return min(lockout, s.policy.MaxLockout)
+}
@@ -0,0 +1,224 @@
+package throttleservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/throttle"
+)
+
+var testPolicy = throttleservice.Policy{
+	MaxFailures:      3,
+	MaxFailuresPerIP: 10,
+	Window:           15 * time.Minute,
+	BaseLockout:      time.Minute,
+	MaxLockout:       10 * time.Minute,
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockThrottleRepo := new(mock.MockLoginThrottleRepository)
+	throttleService := throttleservice.NewLoginThrottleService(mockEmployeeRepo, mockThrottleRepo, testPolicy)
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Not Locked",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("GetLockout", ctx, entity.LoginScopeUsername, "user").Return(time.Duration(0), nil)
+				mockThrottleRepo.On("GetLockout", ctx, entity.LoginScopeIP, "10.0.0.1").Return(time.Duration(0), nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Username Locked",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("GetLockout", ctx, entity.LoginScopeUsername, "user").Return(time.Minute, nil)
+			},
+			expectedError: service.ErrTooManyLoginAttempts,
+		},
+		{
+			name: "Error - Address Locked",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("GetLockout", ctx, entity.LoginScopeUsername, "user").Return(time.Duration(0), nil)
+				mockThrottleRepo.On("GetLockout", ctx, entity.LoginScopeIP, "10.0.0.1").Return(time.Minute, nil)
+			},
+			expectedError: service.ErrTooManyLoginAttempts,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("GetLockout", ctx, entity.LoginScopeUsername, "user").Return(time.Duration(0), database.ErrDatabaseQueryFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := throttleService.Check(ctx, "user", "10.0.0.1")
+			assert.Equal(t, tt.expectedError, err)
+
+			mockThrottleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecordFailure(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockThrottleRepo := new(mock.MockLoginThrottleRepository)
+	throttleService := throttleservice.NewLoginThrottleService(mockEmployeeRepo, mockThrottleRepo, testPolicy)
+
+	tests := []struct {
+		name      string
+		mockSetup func()
+	}{
+		{
+			name: "Below Limit",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeUsername, "user", 15*time.Minute).Return(2, nil)
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeIP, "10.0.0.1", 15*time.Minute).Return(2, nil)
+			},
+		},
+		{
+			name: "Limit Reached - Base Lockout",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeUsername, "user", 15*time.Minute).Return(3, nil)
+				mockThrottleRepo.On("Lock", ctx, entity.LoginScopeUsername, "user", time.Minute).Return(nil)
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeIP, "10.0.0.1", 15*time.Minute).Return(3, nil)
+			},
+		},
+		{
+			name: "Beyond Limit - Lockout Doubles",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeUsername, "user", 15*time.Minute).Return(5, nil)
+				mockThrottleRepo.On("Lock", ctx, entity.LoginScopeUsername, "user", 4*time.Minute).Return(nil)
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeIP, "10.0.0.1", 15*time.Minute).Return(5, nil)
+			},
+		},
+		{
+			name: "Beyond Limit - Lockout Capped",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeUsername, "user", 15*time.Minute).Return(20, nil)
+				mockThrottleRepo.On("Lock", ctx, entity.LoginScopeUsername, "user", 10*time.Minute).Return(nil)
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeIP, "10.0.0.1", 15*time.Minute).Return(10, nil)
+				mockThrottleRepo.On("Lock", ctx, entity.LoginScopeIP, "10.0.0.1", time.Minute).Return(nil)
+			},
+		},
+		{
+			name: "Database Error - Address Still Recorded",
+			mockSetup: func() {
+				mockThrottleRepo.ExpectedCalls = nil
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeUsername, "user", 15*time.Minute).Return(0, database.ErrDatabaseQueryFailed)
+				mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeIP, "10.0.0.1", 15*time.Minute).Return(1, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			throttleService.RecordFailure(ctx, "user", "10.0.0.1")
+
+			mockThrottleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepeatedLockoutsGrow(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockThrottleRepo := new(mock.MockLoginThrottleRepository)
+	throttleService := throttleservice.NewLoginThrottleService(mockEmployeeRepo, mockThrottleRepo, testPolicy)
+
+	// Each step is the first failure after the previous lockout has ended;
+	// the count carries over, so every lockout is longer than the last.
+	lockouts := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}
+	for i, lockout := range lockouts {
+		failures := testPolicy.MaxFailures + i
+		mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeUsername, "user", 15*time.Minute).Return(failures, nil).Once()
+		mockThrottleRepo.On("Lock", ctx, entity.LoginScopeUsername, "user", lockout).Return(nil).Once()
+		mockThrottleRepo.On("RecordFailure", ctx, entity.LoginScopeIP, "10.0.0.1", 15*time.Minute).Return(1, nil).Once()
+
+		throttleService.RecordFailure(ctx, "user", "10.0.0.1")
+	}
+
+	mockThrottleRepo.AssertExpectations(t)
+}
+
+func TestUnlock(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockThrottleRepo := new(mock.MockLoginThrottleRepository)
+	throttleService := throttleservice.NewLoginThrottleService(mockEmployeeRepo, mockThrottleRepo, testPolicy)
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "Success - Employee Unlocked",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockThrottleRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "user").Return(&entity.Employee{ID: 1, Username: "user"}, nil)
+				mockThrottleRepo.On("Reset", ctx, entity.LoginScopeUsername, "user").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Employee Not Found",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockThrottleRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "user").Return(nil, database.ErrEmployeeNotFound)
+			},
+			expectedError: service.ErrEmployeeNotFound,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockThrottleRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "user").Return(&entity.Employee{ID: 1, Username: "user"}, nil)
+				mockThrottleRepo.On("Reset", ctx, entity.LoginScopeUsername, "user").Return(database.ErrDatabaseQueryFailed)
+			},
+			expectedError: service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := throttleService.Unlock(ctx, "user")
+			assert.Equal(t, tt.expectedError, err)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockThrottleRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package transactionservice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+)
+
+const expiredBatchSize = 100
+
+// EscrowService makes transfers that the recipient has to accept. The coins
+// are held in the escrow ledger account until the transfer is resolved.
+type EscrowService struct {
+	employeeRepo database.EmployeeRepository
+	escrowRepo   database.EscrowRepository
+	ttl          time.Duration
+}
+
+func NewEscrowService(employeeRepo database.EmployeeRepository, escrowRepo database.EscrowRepository, ttl time.Duration) *EscrowService {
+	return &EscrowService{
+		employeeRepo: employeeRepo,
+		escrowRepo:   escrowRepo,
+		ttl:          ttl,
+	}
+}
+
+func (s *EscrowService) Hold(ctx context.Context, senderID int, toUser string, amount int, note entity.TransferNote) (*dto.PendingTransfer, error) {
+	note, err := validateNote(note)
+	if err != nil {
+		return nil, err
+	}
+
+	receiver, err := s.employeeRepo.GetEmployeeByUsername(ctx, toUser)
+	if err != nil {
+		log.Printf("recipient %q not found: %v", toUser, err)
+		return nil, service.ErrEmployeeNotFound
+	}
+
+	sender, err := s.employeeRepo.GetEmployeeByID(ctx, senderID)
+	if err != nil {
+		log.Printf("sender %d not found: %v", senderID, err)
+		return nil, service.ErrEmployeeNotFound
+	}
+
+	if sender.ID == receiver.ID {
+		return nil, service.ErrSelfTransaction
+	}
+
+	if sender.Balance < amount {
+		return nil, service.ErrInsufficientFunds
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.ttl).Truncate(time.Second)
+	transferID, err := s.escrowRepo.HoldCoins(ctx, senderID, receiver.ID, amount, note, expiresAt)
+	if err != nil {
+		log.Printf("failed to hold coins: user %d -> %d, amount: %d, error: %v", senderID, receiver.ID, amount, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return nil, service.ErrEmployeeNotFound
+		case database.ErrInsufficientFunds:
+			return nil, service.ErrInsufficientFunds
+		case database.ErrTransactionConflict:
+			return nil, service.ErrConcurrentUpdate
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	return mapPendingTransferToDTO(&entity.PendingTransfer{
+		ID:         transferID,
+		SenderID:   sender.ID,
+		Sender:     sender.Username,
+		ReceiverID: receiver.ID,
+		Receiver:   receiver.Username,
+		Amount:     amount,
+		Note:       note,
+		Status:     entity.PendingTransferPending,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	}), nil
+}
+
+func (s *EscrowService) ListPending(ctx context.Context, userID int) (*dto.PendingTransfersResponse, error) {
+	transfers, err := s.escrowRepo.ListPendingTransfers(ctx, userID)
+	if err != nil {
+		log.Printf("failed to list pending transfers of user %d: %v", userID, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	response := &dto.PendingTransfersResponse{
+		Incoming: make([]dto.PendingTransfer, 0),
+		Outgoing: make([]dto.PendingTransfer, 0),
+	}
+	for _, transfer := range transfers {
+		if transfer.SenderID == userID {
+			response.Outgoing = append(response.Outgoing, *mapPendingTransferToDTO(transfer))
+		} else {
+			response.Incoming = append(response.Incoming, *mapPendingTransferToDTO(transfer))
+		}
+	}
+
+	return response, nil
+}
+
+// Accept pays a pending transfer out to its recipient. Expired transfers can
+// no longer be accepted even if the sweeper has not refunded them yet.
+func (s *EscrowService) Accept(ctx context.Context, userID, transferID int) (*dto.PendingTransfer, error) {
+	return s.resolve(ctx, userID, transferID, entity.PendingTransferAccepted)
+}
+
+func (s *EscrowService) Decline(ctx context.Context, userID, transferID int) (*dto.PendingTransfer, error) {
+	return s.resolve(ctx, userID, transferID, entity.PendingTransferDeclined)
+}
+
+func (s *EscrowService) resolve(ctx context.Context, userID, transferID int, status string) (*dto.PendingTransfer, error) {
+	transfer, err := s.escrowRepo.GetPendingTransfer(ctx, transferID)
+	if err != nil {
+		switch err {
+		case database.ErrPendingTransferNotFound:
+			return nil, service.ErrPendingTransferNotFound
+		default:
+			log.Printf("failed to get pending transfer %d: %v", transferID, err)
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	if transfer.ReceiverID != userID {
+		return nil, service.ErrPendingTransferNotFound
+	}
+
+	if transfer.Status != entity.PendingTransferPending {
+		return nil, service.ErrPendingTransferResolved
+	}
+
+	now := time.Now().UTC()
+	if status == entity.PendingTransferAccepted && !now.Before(transfer.ExpiresAt) {
+		return nil, service.ErrPendingTransferExpired
+	}
+
+	err = s.escrowRepo.ResolvePendingTransfer(ctx, transferID, status)
+	if err != nil {
+		log.Printf("failed to resolve pending transfer %d as %s: %v", transferID, status, err)
+		switch err {
+		case database.ErrPendingTransferNotFound:
+			return nil, service.ErrPendingTransferNotFound
+		case database.ErrPendingTransferResolved:
+			return nil, service.ErrPendingTransferResolved
+		case database.ErrTransactionConflict:
+			return nil, service.ErrConcurrentUpdate
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	transfer.Status = status
+	transfer.ResolvedAt = &now
+
+	return mapPendingTransferToDTO(transfer), nil
+}
+
+// ExpireDue refunds the pending transfers that expired by now and returns how
+// many it refunded. Transfers resolved meanwhile, by their recipient or by
+// another instance, are skipped.
+func (s *EscrowService) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	transferIDs, err := s.escrowRepo.ListExpiredTransfers(ctx, now, expiredBatchSize)
+	if err != nil {
+		log.Printf("failed to list expired pending transfers: %v", err)
+		return 0, service.ErrDatabaseError
+	}
+
+	expired := 0
+	for _, transferID := range transferIDs {
+		err := s.escrowRepo.ResolvePendingTransfer(ctx, transferID, entity.PendingTransferExpired)
+		if err != nil {
+			if err != database.ErrPendingTransferResolved {
+				log.Printf("failed to refund expired pending transfer %d: %v", transferID, err)
+			}
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func mapPendingTransferToDTO(transfer *entity.PendingTransfer) *dto.PendingTransfer {
+	return &dto.PendingTransfer{
+		ID:         transfer.ID,
+		FromUser:   transfer.Sender,
+		ToUser:     transfer.Receiver,
+		Amount:     transfer.Amount,
+		Message:    transfer.Note.Message,
+		Category:   transfer.Note.Category,
+		Status:     transfer.Status,
+		ExpiresAt:  transfer.ExpiresAt,
+		CreatedAt:  transfer.CreatedAt,
+		ResolvedAt: transfer.ResolvedAt,
+	}
+}
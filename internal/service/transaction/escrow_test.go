@@ -0,0 +1,275 @@
+package transactionservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service"
+	"github.com/vit6556/avito-internship-assignment/internal/service/transaction"
+)
+
+func TestHold(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockEscrowRepo := new(mock.MockEscrowRepository)
+	escrowService := transactionservice.NewEscrowService(mockEmployeeRepo, mockEscrowRepo, 72*time.Hour)
+
+	alice := &entity.Employee{ID: 1, Username: "alice", Balance: 1000}
+	bob := &entity.Employee{ID: 2, Username: "bob", Balance: 100}
+
+	tests := []struct {
+		name          string
+		toUser        string
+		amount        int
+		note          entity.TransferNote
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "Success - Coins Held",
+			toUser: "bob",
+			amount: 500,
+			note:   entity.TransferNote{Message: "  for the\tlaunch ", Category: entity.TransferCategoryBonus},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").Return(bob, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(alice, nil)
+				mockEscrowRepo.On("HoldCoins", ctx, 1, 2, 500, entity.TransferNote{Message: "for the launch", Category: entity.TransferCategoryBonus},
+					testifyMock.MatchedBy(func(expiresAt time.Time) bool {
+						return expiresAt.After(time.Now().Add(71*time.Hour)) && expiresAt.Location() == time.UTC
+					})).Return(3, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Error - Invalid Category",
+			toUser: "bob",
+			amount: 500,
+			note:   entity.TransferNote{Category: "salary"},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEscrowRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidCategory,
+		},
+		{
+			name:   "Error - Self Transfer",
+			toUser: "alice",
+			amount: 500,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").Return(alice, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(alice, nil)
+			},
+			expectedError: service.ErrSelfTransaction,
+		},
+		{
+			name:   "Error - Insufficient Funds",
+			toUser: "bob",
+			amount: 5000,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").Return(bob, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(alice, nil)
+			},
+			expectedError: service.ErrInsufficientFunds,
+		},
+		{
+			name:   "Error - Balance Spent Concurrently",
+			toUser: "bob",
+			amount: 500,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").Return(bob, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(alice, nil)
+				mockEscrowRepo.On("HoldCoins", ctx, 1, 2, 500, entity.TransferNote{}, testifyMock.Anything).
+					Return(0, database.ErrInsufficientFunds)
+			},
+			expectedError: service.ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			transfer, err := escrowService.Hold(ctx, 1, tt.toUser, tt.amount, tt.note)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, 3, transfer.ID)
+				assert.Equal(t, "alice", transfer.FromUser)
+				assert.Equal(t, "bob", transfer.ToUser)
+				assert.Equal(t, "for the launch", transfer.Message)
+				assert.Equal(t, entity.PendingTransferPending, transfer.Status)
+			}
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockEscrowRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolvePendingTransfer(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockEscrowRepo := new(mock.MockEscrowRepository)
+	escrowService := transactionservice.NewEscrowService(mockEmployeeRepo, mockEscrowRepo, 72*time.Hour)
+
+	pending := func(expiresAt time.Time) *entity.PendingTransfer {
+		return &entity.PendingTransfer{
+			ID: 3, SenderID: 1, Sender: "alice", ReceiverID: 2, Receiver: "bob", Amount: 500,
+			Status: entity.PendingTransferPending, ExpiresAt: expiresAt,
+		}
+	}
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		accept         bool
+		userID         int
+		mockSetup      func()
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			name:   "Success - Accepted",
+			accept: true,
+			userID: 2,
+			mockSetup: func() {
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEscrowRepo.On("GetPendingTransfer", ctx, 3).Return(pending(future), nil)
+				mockEscrowRepo.On("ResolvePendingTransfer", ctx, 3, entity.PendingTransferAccepted).Return(nil)
+			},
+			expectedStatus: entity.PendingTransferAccepted,
+			expectedError:  nil,
+		},
+		{
+			name:   "Success - Declined After Expiry",
+			accept: false,
+			userID: 2,
+			mockSetup: func() {
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEscrowRepo.On("GetPendingTransfer", ctx, 3).Return(pending(past), nil)
+				mockEscrowRepo.On("ResolvePendingTransfer", ctx, 3, entity.PendingTransferDeclined).Return(nil)
+			},
+			expectedStatus: entity.PendingTransferDeclined,
+			expectedError:  nil,
+		},
+		{
+			name:   "Error - Accepted By Sender",
+			accept: true,
+			userID: 1,
+			mockSetup: func() {
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEscrowRepo.On("GetPendingTransfer", ctx, 3).Return(pending(future), nil)
+			},
+			expectedError: service.ErrPendingTransferNotFound,
+		},
+		{
+			name:   "Error - Expired",
+			accept: true,
+			userID: 2,
+			mockSetup: func() {
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEscrowRepo.On("GetPendingTransfer", ctx, 3).Return(pending(past), nil)
+			},
+			expectedError: service.ErrPendingTransferExpired,
+		},
+		{
+			name:   "Error - Already Resolved",
+			accept: true,
+			userID: 2,
+			mockSetup: func() {
+				transfer := pending(future)
+				transfer.Status = entity.PendingTransferDeclined
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEscrowRepo.On("GetPendingTransfer", ctx, 3).Return(transfer, nil)
+			},
+			expectedError: service.ErrPendingTransferResolved,
+		},
+		{
+			name:   "Error - Resolved Concurrently",
+			accept: true,
+			userID: 2,
+			mockSetup: func() {
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEscrowRepo.On("GetPendingTransfer", ctx, 3).Return(pending(future), nil)
+				mockEscrowRepo.On("ResolvePendingTransfer", ctx, 3, entity.PendingTransferAccepted).Return(database.ErrPendingTransferResolved)
+			},
+			expectedError: service.ErrPendingTransferResolved,
+		},
+		{
+			name:   "Error - Not Found",
+			accept: true,
+			userID: 2,
+			mockSetup: func() {
+				mockEscrowRepo.ExpectedCalls = nil
+				mockEscrowRepo.On("GetPendingTransfer", ctx, 3).Return(nil, database.ErrPendingTransferNotFound)
+			},
+			expectedError: service.ErrPendingTransferNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resolve := escrowService.Decline
+			if tt.accept {
+				resolve = escrowService.Accept
+			}
+			transfer, err := resolve(ctx, tt.userID, 3)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedStatus, transfer.Status)
+				assert.NotNil(t, transfer.ResolvedAt)
+			}
+
+			mockEscrowRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListPendingAndExpireDue(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockEscrowRepo := new(mock.MockEscrowRepository)
+	escrowService := transactionservice.NewEscrowService(mockEmployeeRepo, mockEscrowRepo, 72*time.Hour)
+
+	mockEscrowRepo.On("ListPendingTransfers", ctx, 1).Return([]*entity.PendingTransfer{
+		{ID: 3, SenderID: 1, Sender: "alice", ReceiverID: 2, Receiver: "bob", Amount: 500, Status: entity.PendingTransferPending},
+		{ID: 4, SenderID: 5, Sender: "carol", ReceiverID: 1, Receiver: "alice", Amount: 50, Status: entity.PendingTransferPending},
+	}, nil).Once()
+
+	pending, err := escrowService.ListPending(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, pending.Outgoing, 1)
+	assert.Equal(t, "bob", pending.Outgoing[0].ToUser)
+	assert.Len(t, pending.Incoming, 1)
+	assert.Equal(t, "carol", pending.Incoming[0].FromUser)
+
+	now := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	mockEscrowRepo.On("ListExpiredTransfers", ctx, now, 100).Return([]int{3, 4, 6}, nil).Once()
+	mockEscrowRepo.On("ResolvePendingTransfer", ctx, 3, entity.PendingTransferExpired).Return(nil).Once()
+	mockEscrowRepo.On("ResolvePendingTransfer", ctx, 4, entity.PendingTransferExpired).Return(database.ErrPendingTransferResolved).Once()
+	mockEscrowRepo.On("ResolvePendingTransfer", ctx, 6, entity.PendingTransferExpired).Return(nil).Once()
+
+	expired, err := escrowService.ExpireDue(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, expired)
+
+	mockEscrowRepo.AssertExpectations(t)
+}
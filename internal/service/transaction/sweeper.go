@@ -0,0 +1,47 @@
+package transactionservice
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// EscrowSweeper periodically refunds expired pending transfers. Each refund
+// locks its transfer, so sweepers may run on every instance of the service.
+type EscrowSweeper struct {
+	escrowService *EscrowService
+	interval      time.Duration
+}
+
+func NewEscrowSweeper(escrowService *EscrowService, interval time.Duration) *EscrowSweeper {
+	return &EscrowSweeper{
+		escrowService: escrowService,
+		interval:      interval,
+	}
+}
+
+func (s *EscrowSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *EscrowSweeper) sweep(ctx context.Context) {
+	expired, err := s.escrowService.ExpireDue(ctx, time.Now().UTC())
+	if err != nil {
+		log.Printf("failed to refund expired pending transfers: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("refunded %d expired pending transfers", expired)
+	}
+}
@@ -2,12 +2,35 @@ package transactionservice
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/vit6556/avito-internship-assignment/internal/database"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 )
 
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+
+	maxGrantLines  = 1000
+	maxGrantAmount = 1_000_000
+	// maxGrantTotal keeps the issuance entry well inside the range of the
+	// INTEGER columns of the ledger.
+	maxGrantTotal = 10_000_000
+
+	// maxMessageLength matches the size of transactions.message.
+	maxMessageLength = 200
+)
+
 type TransactionService struct {
 	employeeRepo    database.EmployeeRepository
 	transactionRepo database.TransactionRepository
@@ -20,7 +43,12 @@ func NewTransactionService(employeeRepo database.EmployeeRepository, transaction
 	}
 }
 
-func (s *TransactionService) SendCoins(ctx context.Context, senderID int, toUser string, amount int) error {
+func (s *TransactionService) SendCoins(ctx context.Context, senderID int, toUser string, amount int, note entity.TransferNote) error {
+	note, err := validateNote(note)
+	if err != nil {
+		return err
+	}
+
 	receiver, err := s.employeeRepo.GetEmployeeByUsername(ctx, toUser)
 	if err != nil {
 		log.Printf("recipient %q not found: %v", toUser, err)
@@ -41,7 +69,7 @@ func (s *TransactionService) SendCoins(ctx context.Context, senderID int, toUser
 		return service.ErrInsufficientFunds
 	}
 
-	err = s.transactionRepo.SendCoins(ctx, senderID, receiver.ID, amount)
+	err = s.transactionRepo.SendCoins(ctx, senderID, receiver.ID, amount, note)
 	if err != nil {
 		log.Printf("transaction failed: user %d -> %d, amount: %d, error: %v", senderID, receiver.ID, amount, err)
 		switch err {
@@ -49,6 +77,8 @@ func (s *TransactionService) SendCoins(ctx context.Context, senderID int, toUser
 			return service.ErrEmployeeNotFound
 		case database.ErrInsufficientFunds:
 			return service.ErrInsufficientFunds
+		case database.ErrTransactionConflict:
+			return service.ErrConcurrentUpdate
 		default:
 			return service.ErrDatabaseError
 		}
@@ -56,3 +86,165 @@ func (s *TransactionService) SendCoins(ctx context.Context, senderID int, toUser
 
 	return nil
 }
+
+func (s *TransactionService) GetHistory(ctx context.Context, userID int, filter entity.TransferFilter, cursor string) (*dto.HistoryResponse, error) {
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, service.ErrInvalidCursor
+		}
+		filter.After = after
+	}
+
+	if filter.Category != "" && !slices.Contains(entity.TransferCategories, filter.Category) {
+		return nil, service.ErrInvalidCategory
+	}
+	filter.Search = sanitizeMessage(filter.Search)
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultHistoryLimit
+	}
+	if filter.Limit > maxHistoryLimit {
+		filter.Limit = maxHistoryLimit
+	}
+
+	limit := filter.Limit
+	filter.Limit++
+
+	transfers, err := s.transactionRepo.GetTransfers(ctx, userID, filter)
+	if err != nil {
+		log.Printf("failed to get transfers for user %d: %v", userID, err)
+		return nil, service.ErrDatabaseError
+	}
+
+	response := &dto.HistoryResponse{}
+	if len(transfers) > limit {
+		transfers = transfers[:limit]
+		last := transfers[limit-1]
+		response.NextCursor = encodeCursor(entity.TransferCursor{Timestamp: last.Timestamp, ID: last.ID})
+	}
+
+	response.Transactions = make([]dto.HistoryTransaction, len(transfers))
+	for i, transfer := range transfers {
+		response.Transactions[i] = dto.HistoryTransaction{
+			ID:           transfer.ID,
+			Kind:         transfer.Kind,
+			Counterparty: transfer.Counterparty,
+			Amount:       transfer.Amount,
+			Direction:    transfer.Direction,
+			Reason:       transfer.Reason,
+			Message:      transfer.Message,
+			Category:     transfer.Category,
+			ReversalOf:   transfer.ReversalOf,
+			ReversedBy:   transfer.ReversedBy,
+			Timestamp:    transfer.Timestamp,
+		}
+	}
+
+	return response, nil
+}
+
+func (s *TransactionService) IssueCoins(ctx context.Context, issuerID int, reason string, grants []entity.CoinGrant) (*dto.GrantResponse, error) {
+	if reason == "" || len(grants) == 0 || len(grants) > maxGrantLines {
+		return nil, service.ErrInvalidGrant
+	}
+
+	resolved := make([]entity.CoinGrant, len(grants))
+	recipients := make(map[int]struct{}, len(grants))
+	total := 0
+	for i, grant := range grants {
+		if grant.Amount <= 0 || grant.Amount > maxGrantAmount {
+			return nil, service.ErrInvalidGrant
+		}
+
+		employee, err := s.employeeRepo.GetEmployeeByUsername(ctx, grant.Username)
+		if err != nil {
+			log.Printf("grant recipient %q not found: %v", grant.Username, err)
+			return nil, service.ErrEmployeeNotFound
+		}
+
+		resolved[i] = entity.CoinGrant{
+			EmployeeID: employee.ID,
+			Username:   employee.Username,
+			Amount:     grant.Amount,
+		}
+		recipients[employee.ID] = struct{}{}
+		total += grant.Amount
+	}
+
+	if total > maxGrantTotal {
+		return nil, service.ErrInvalidGrant
+	}
+
+	grantID, err := s.transactionRepo.IssueCoins(ctx, issuerID, reason, resolved)
+	if err != nil {
+		log.Printf("failed to issue %d coins by user %d: %v", total, issuerID, err)
+		switch err {
+		case database.ErrEmployeeNotFound:
+			return nil, service.ErrEmployeeNotFound
+		case database.ErrTransactionConflict:
+			return nil, service.ErrConcurrentUpdate
+		default:
+			return nil, service.ErrDatabaseError
+		}
+	}
+
+	return &dto.GrantResponse{
+		GrantID:    grantID,
+		Total:      total,
+		Recipients: len(recipients),
+	}, nil
+}
+
+// validateNote sanitizes the message of a transfer and checks that it fits
+// and that the category is known.
+func validateNote(note entity.TransferNote) (entity.TransferNote, error) {
+	note.Message = sanitizeMessage(note.Message)
+	if utf8.RuneCountInString(note.Message) > maxMessageLength {
+		return note, service.ErrInvalidMessage
+	}
+
+	if note.Category != "" && !slices.Contains(entity.TransferCategories, note.Category) {
+		return note, service.ErrInvalidCategory
+	}
+
+	return note, nil
+}
+
+// sanitizeMessage drops invalid UTF-8, control and invisible formatting
+// characters (such as bidi overrides) and collapses whitespace, so a message
+// renders as a single plain line wherever it is shown.
+func sanitizeMessage(message string) string {
+	message = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			return -1
+		default:
+			return r
+		}
+	}, strings.ToValidUTF8(message, ""))
+
+	return strings.Join(strings.Fields(message), " ")
+}
+
+func encodeCursor(cursor entity.TransferCursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.Timestamp.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*entity.TransferCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var nanos int64
+	var id int
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil {
+		return nil, err
+	}
+
+	return &entity.TransferCursor{Timestamp: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
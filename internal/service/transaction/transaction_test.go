@@ -2,11 +2,15 @@ package transactionservice_test
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vit6556/avito-internship-assignment/internal/database"
 	"github.com/vit6556/avito-internship-assignment/internal/database/mock"
+	"github.com/vit6556/avito-internship-assignment/internal/delivery/http/dto"
 	"github.com/vit6556/avito-internship-assignment/internal/entity"
 	"github.com/vit6556/avito-internship-assignment/internal/service"
 	"github.com/vit6556/avito-internship-assignment/internal/service/transaction"
@@ -23,6 +27,7 @@ func TestSendCoins(t *testing.T) {
 		sender        *entity.Employee
 		receiver      *entity.Employee
 		amount        int
+		note          entity.TransferNote
 		mockSetup     func()
 		expectedError error
 	}{
@@ -39,7 +44,7 @@ func TestSendCoins(t *testing.T) {
 					Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
 				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
 					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
-				mockTransactionRepo.On("SendCoins", ctx, 1, 2, 50).
+				mockTransactionRepo.On("SendCoins", ctx, 1, 2, 50, entity.TransferNote{}).
 					Return(nil)
 			},
 			expectedError: nil,
@@ -90,6 +95,49 @@ func TestSendCoins(t *testing.T) {
 			},
 			expectedError: service.ErrSelfTransaction,
 		},
+		{
+			name:     "Success - Message Sanitized",
+			sender:   &entity.Employee{ID: 1, Username: "alice"},
+			receiver: &entity.Employee{ID: 2, Username: "bob"},
+			amount:   50,
+			note:     entity.TransferNote{Message: "  thanks\nfor the\t\u202ereview\x00 ", Category: entity.TransferCategoryThanks},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
+					Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockTransactionRepo.On("SendCoins", ctx, 1, 2, 50, entity.TransferNote{Message: "thanks for the review", Category: entity.TransferCategoryThanks}).
+					Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "Error - Message Too Long",
+			sender:   &entity.Employee{ID: 1, Username: "alice"},
+			receiver: &entity.Employee{ID: 2, Username: "bob"},
+			amount:   50,
+			note:     entity.TransferNote{Message: strings.Repeat("я", 201)},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidMessage,
+		},
+		{
+			name:     "Error - Unknown Category",
+			sender:   &entity.Employee{ID: 1, Username: "alice"},
+			receiver: &entity.Employee{ID: 2, Username: "bob"},
+			amount:   50,
+			note:     entity.TransferNote{Category: "bribe"},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+			},
+			expectedError: service.ErrInvalidCategory,
+		},
 		{
 			name:     "Error - Insufficient Funds",
 			sender:   &entity.Employee{ID: 1, Username: "alice", Balance: 30},
@@ -119,11 +167,47 @@ func TestSendCoins(t *testing.T) {
 					Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
 				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
 					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
-				mockTransactionRepo.On("SendCoins", ctx, 1, 2, 50).
+				mockTransactionRepo.On("SendCoins", ctx, 1, 2, 50, entity.TransferNote{}).
 					Return(database.ErrDatabaseTransaction)
 			},
 			expectedError: service.ErrDatabaseError,
 		},
+		{
+			name:     "Error - Insufficient Funds Reported By Repository",
+			sender:   &entity.Employee{ID: 1, Username: "alice", Balance: 100},
+			receiver: &entity.Employee{ID: 2, Username: "bob"},
+			amount:   50,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
+					Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockTransactionRepo.On("SendCoins", ctx, 1, 2, 50, entity.TransferNote{}).
+					Return(database.ErrInsufficientFunds)
+			},
+			expectedError: service.ErrInsufficientFunds,
+		},
+		{
+			name:     "Error - Conflict Retries Exhausted",
+			sender:   &entity.Employee{ID: 1, Username: "alice", Balance: 100},
+			receiver: &entity.Employee{ID: 2, Username: "bob"},
+			amount:   50,
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
+					Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+				mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).
+					Return(&entity.Employee{ID: 1, Username: "alice", Balance: 100}, nil)
+				mockTransactionRepo.On("SendCoins", ctx, 1, 2, 50, entity.TransferNote{}).
+					Return(database.ErrTransactionConflict)
+			},
+			expectedError: service.ErrConcurrentUpdate,
+		},
 	}
 
 	for _, tt := range tests {
@@ -144,9 +228,275 @@ func TestSendCoins(t *testing.T) {
 				receiverUsername = "bob"
 			}
 
-			err := transactionService.SendCoins(ctx, senderID, receiverUsername, tt.amount)
+			err := transactionService.SendCoins(ctx, senderID, receiverUsername, tt.amount, tt.note)
+
+			assert.Equal(t, tt.expectedError, err)
+
+			mockEmployeeRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTransactionRepo := new(mock.MockTransactionRepository)
+	transactionService := transactionservice.NewTransactionService(mockEmployeeRepo, mockTransactionRepo)
+
+	first := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(-time.Hour)
+
+	tests := []struct {
+		name             string
+		filter           entity.TransferFilter
+		cursor           string
+		mockSetup        func()
+		expectedResponse *dto.HistoryResponse
+		expectedError    error
+	}{
+		{
+			name:   "Success - Last Page",
+			filter: entity.TransferFilter{Direction: entity.TransferDirectionSent},
+			mockSetup: func() {
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockTransactionRepo.On("GetTransfers", ctx, 1, entity.TransferFilter{Direction: entity.TransferDirectionSent, Limit: 21}).
+					Return([]entity.Transfer{{ID: 5, Kind: entity.TransferKindTransfer, Counterparty: "bob", Amount: 10, Direction: entity.TransferDirectionSent, Timestamp: first}}, nil)
+			},
+			expectedResponse: &dto.HistoryResponse{
+				Transactions: []dto.HistoryTransaction{{ID: 5, Kind: entity.TransferKindTransfer, Counterparty: "bob", Amount: 10, Direction: "sent", Timestamp: first}},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Success - Next Cursor Returned",
+			filter: entity.TransferFilter{Limit: 1},
+			mockSetup: func() {
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockTransactionRepo.On("GetTransfers", ctx, 1, entity.TransferFilter{Limit: 2}).
+					Return([]entity.Transfer{
+						{ID: 5, Kind: entity.TransferKindTransfer, Counterparty: "bob", Amount: 10, Direction: entity.TransferDirectionSent, Timestamp: first},
+						{ID: 4, Kind: entity.TransferKindIssuance, Amount: 20, Direction: entity.TransferDirectionReceived, Reason: "Q1 bonus", Timestamp: second},
+					}, nil)
+			},
+			expectedResponse: &dto.HistoryResponse{
+				Transactions: []dto.HistoryTransaction{{ID: 5, Kind: entity.TransferKindTransfer, Counterparty: "bob", Amount: 10, Direction: "sent", Timestamp: first}},
+				NextCursor:   "MTczODQxMTIwMDAwMDAwMDAwMDo1",
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Success - Cursor Decoded",
+			filter: entity.TransferFilter{Limit: 1},
+			cursor: "MTczODQxMTIwMDAwMDAwMDAwMDo1",
+			mockSetup: func() {
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockTransactionRepo.On("GetTransfers", ctx, 1, entity.TransferFilter{Limit: 2, After: &entity.TransferCursor{Timestamp: first, ID: 5}}).
+					Return([]entity.Transfer{{ID: 4, Kind: entity.TransferKindIssuance, Amount: 20, Direction: entity.TransferDirectionReceived, Reason: "Q1 bonus", Timestamp: second}}, nil)
+			},
+			expectedResponse: &dto.HistoryResponse{
+				Transactions: []dto.HistoryTransaction{{ID: 4, Kind: entity.TransferKindIssuance, Amount: 20, Direction: "received", Reason: "Q1 bonus", Timestamp: second}},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Success - Category And Search",
+			filter: entity.TransferFilter{Category: entity.TransferCategoryThanks, Search: " code\u200b  review "},
+			mockSetup: func() {
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockTransactionRepo.On("GetTransfers", ctx, 1, entity.TransferFilter{Category: entity.TransferCategoryThanks, Search: "code review", Limit: 21}).
+					Return([]entity.Transfer{{ID: 5, Kind: entity.TransferKindTransfer, Counterparty: "bob", Amount: 10, Direction: entity.TransferDirectionReceived,
+						Message: "thanks for the code review", Category: entity.TransferCategoryThanks, Timestamp: first}}, nil)
+			},
+			expectedResponse: &dto.HistoryResponse{
+				Transactions: []dto.HistoryTransaction{{ID: 5, Kind: entity.TransferKindTransfer, Counterparty: "bob", Amount: 10, Direction: "received",
+					Message: "thanks for the code review", Category: "thanks", Timestamp: first}},
+			},
+			expectedError: nil,
+		},
+		{
+			name:             "Error - Invalid Category",
+			filter:           entity.TransferFilter{Category: "bribe"},
+			mockSetup:        func() { mockTransactionRepo.ExpectedCalls = nil },
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidCategory,
+		},
+		{
+			name:             "Error - Invalid Cursor",
+			cursor:           "not a cursor",
+			mockSetup:        func() { mockTransactionRepo.ExpectedCalls = nil },
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidCursor,
+		},
+		{
+			name: "Error - Database Error",
+			mockSetup: func() {
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockTransactionRepo.On("GetTransfers", ctx, 1, entity.TransferFilter{Limit: 21}).
+					Return(nil, database.ErrDatabaseQueryFailed)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			response, err := transactionService.GetHistory(ctx, 1, tt.filter, tt.cursor)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedResponse, response)
+
+			mockTransactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssueCoins(t *testing.T) {
+	ctx := context.Background()
+	mockEmployeeRepo := new(mock.MockEmployeeRepository)
+	mockTransactionRepo := new(mock.MockTransactionRepository)
+	transactionService := transactionservice.NewTransactionService(mockEmployeeRepo, mockTransactionRepo)
+
+	tests := []struct {
+		name             string
+		reason           string
+		grants           []entity.CoinGrant
+		mockSetup        func()
+		expectedResponse *dto.GrantResponse
+		expectedError    error
+	}{
+		{
+			name:   "Success - Coins Issued",
+			reason: "hackathon",
+			grants: []entity.CoinGrant{{Username: "alice", Amount: 100}, {Username: "bob", Amount: 50}, {Username: "alice", Amount: 10}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").
+					Return(&entity.Employee{ID: 1, Username: "alice"}, nil)
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
+					Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+				mockTransactionRepo.On("IssueCoins", ctx, 9, "hackathon", []entity.CoinGrant{
+					{EmployeeID: 1, Username: "alice", Amount: 100},
+					{EmployeeID: 2, Username: "bob", Amount: 50},
+					{EmployeeID: 1, Username: "alice", Amount: 10},
+				}).Return(3, nil)
+			},
+			expectedResponse: &dto.GrantResponse{GrantID: 3, Total: 160, Recipients: 2},
+			expectedError:    nil,
+		},
+		{
+			name:   "Error - Non-Positive Amount",
+			reason: "hackathon",
+			grants: []entity.CoinGrant{{Username: "alice", Amount: 0}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidGrant,
+		},
+		{
+			name:   "Error - Amount Too Large",
+			reason: "hackathon",
+			grants: []entity.CoinGrant{{Username: "alice", Amount: 1_000_001}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidGrant,
+		},
+		{
+			name:   "Error - Total Too Large",
+			reason: "hackathon",
+			grants: slices.Repeat([]entity.CoinGrant{{Username: "alice", Amount: 1_000_000}}, 11),
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "alice").
+					Return(&entity.Employee{ID: 1, Username: "alice"}, nil)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidGrant,
+		},
+		{
+			name:   "Error - Missing Reason",
+			reason: "",
+			grants: []entity.CoinGrant{{Username: "alice", Amount: 10}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrInvalidGrant,
+		},
+		{
+			name:   "Error - Recipient Not Found",
+			reason: "hackathon",
+			grants: []entity.CoinGrant{{Username: "ghost", Amount: 10}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "ghost").
+					Return(nil, database.ErrEmployeeNotFound)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrEmployeeNotFound,
+		},
+		{
+			name:   "Error - Database Error",
+			reason: "hackathon",
+			grants: []entity.CoinGrant{{Username: "bob", Amount: 10}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
+					Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+				mockTransactionRepo.On("IssueCoins", ctx, 9, "hackathon", []entity.CoinGrant{{EmployeeID: 2, Username: "bob", Amount: 10}}).
+					Return(0, database.ErrDatabaseTransaction)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrDatabaseError,
+		},
+		{
+			name:   "Error - Concurrent Update",
+			reason: "hackathon",
+			grants: []entity.CoinGrant{{Username: "bob", Amount: 10}},
+			mockSetup: func() {
+				mockEmployeeRepo.ExpectedCalls = nil
+				mockTransactionRepo.ExpectedCalls = nil
+
+				mockEmployeeRepo.On("GetEmployeeByUsername", ctx, "bob").
+					Return(&entity.Employee{ID: 2, Username: "bob"}, nil)
+				mockTransactionRepo.On("IssueCoins", ctx, 9, "hackathon", []entity.CoinGrant{{EmployeeID: 2, Username: "bob", Amount: 10}}).
+					Return(0, database.ErrTransactionConflict)
+			},
+			expectedResponse: nil,
+			expectedError:    service.ErrConcurrentUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			response, err := transactionService.IssueCoins(ctx, 9, tt.reason, tt.grants)
 
 			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedResponse, response)
 
 			mockEmployeeRepo.AssertExpectations(t)
 			mockTransactionRepo.AssertExpectations(t)
@@ -0,0 +1,14 @@
+DROP VIEW IF EXISTS ledger_balance_mismatches;
+
+ALTER TABLE purchases DROP COLUMN IF EXISTS entry_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS entry_id;
+
+DROP TRIGGER IF EXISTS trg_ledger_entry_balanced ON ledger_postings;
+DROP FUNCTION IF EXISTS check_ledger_entry_balanced();
+
+DROP INDEX IF EXISTS idx_ledger_postings_entry;
+DROP INDEX IF EXISTS idx_ledger_postings_account;
+
+DROP TABLE IF EXISTS ledger_postings;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_accounts;
@@ -0,0 +1,82 @@
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER UNIQUE REFERENCES employees(id) ON DELETE CASCADE,
+    code VARCHAR(32) UNIQUE,
+    CHECK ((employee_id IS NULL) <> (code IS NULL))
+);
+
+INSERT INTO ledger_accounts (code) VALUES
+    ('shop_revenue'),
+    ('issuance')
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id SERIAL PRIMARY KEY,
+    entry_id INTEGER NOT NULL REFERENCES ledger_entries(id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES ledger_accounts(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount <> 0)
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_entry ON ledger_postings(entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_account ON ledger_postings(account_id);
+
+CREATE OR REPLACE FUNCTION check_ledger_entry_balanced() RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT COALESCE(SUM(amount), 0) FROM ledger_postings WHERE entry_id = NEW.entry_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger entry % is not balanced', NEW.entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trg_ledger_entry_balanced
+    AFTER INSERT OR UPDATE ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_ledger_entry_balanced();
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS entry_id INTEGER REFERENCES ledger_entries(id);
+ALTER TABLE purchases ADD COLUMN IF NOT EXISTS entry_id INTEGER REFERENCES ledger_entries(id);
+
+INSERT INTO ledger_accounts (employee_id)
+SELECT id FROM employees
+ON CONFLICT (employee_id) DO NOTHING;
+
+-- Existing balances have no recorded origin, so each one is opened
+-- as a single issuance so that the ledger matches the cached value.
+DO $$
+DECLARE
+    emp RECORD;
+    opening_entry_id INTEGER;
+    issuance_account_id INTEGER;
+BEGIN
+    SELECT id INTO issuance_account_id FROM ledger_accounts WHERE code = 'issuance';
+
+    FOR emp IN
+        SELECT e.balance, a.id AS account_id
+        FROM employees e
+        JOIN ledger_accounts a ON a.employee_id = e.id
+        WHERE e.balance > 0
+    LOOP
+        INSERT INTO ledger_entries (kind) VALUES ('opening') RETURNING id INTO opening_entry_id;
+        INSERT INTO ledger_postings (entry_id, account_id, amount) VALUES
+            (opening_entry_id, issuance_account_id, -emp.balance),
+            (opening_entry_id, emp.account_id, emp.balance);
+    END LOOP;
+END;
+$$;
+
+CREATE OR REPLACE VIEW ledger_balance_mismatches AS
+SELECT
+    e.id AS employee_id,
+    e.balance AS cached_balance,
+    COALESCE(SUM(p.amount), 0) AS ledger_balance
+FROM employees e
+JOIN ledger_accounts a ON a.employee_id = e.id
+LEFT JOIN ledger_postings p ON p.account_id = a.id
+GROUP BY e.id, e.balance
+HAVING e.balance <> COALESCE(SUM(p.amount), 0);
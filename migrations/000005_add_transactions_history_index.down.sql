@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_sender_timestamp;
+DROP INDEX IF EXISTS idx_transactions_receiver_timestamp;
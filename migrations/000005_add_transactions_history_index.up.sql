@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_timestamp ON transactions(sender_id, timestamp DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_receiver_timestamp ON transactions(receiver_id, timestamp DESC, id DESC);
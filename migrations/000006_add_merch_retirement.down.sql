@@ -0,0 +1 @@
+ALTER TABLE merch_items DROP COLUMN IF EXISTS retired_at;
@@ -0,0 +1 @@
+ALTER TABLE merch_items ADD COLUMN IF NOT EXISTS retired_at TIMESTAMP;
@@ -0,0 +1,2 @@
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'employee';
+ALTER TABLE employees ADD CONSTRAINT employees_role_check CHECK (role IN ('employee', 'hr', 'admin'));
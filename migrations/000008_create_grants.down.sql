@@ -0,0 +1,9 @@
+DELETE FROM transactions WHERE kind <> 'transfer';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_sender_check;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_kind_check;
+ALTER TABLE transactions ALTER COLUMN sender_id SET NOT NULL;
+ALTER TABLE transactions DROP COLUMN IF EXISTS grant_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS kind;
+
+DROP TABLE IF EXISTS grants;
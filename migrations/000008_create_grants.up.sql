@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS grants (
+    id SERIAL PRIMARY KEY,
+    issued_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+    reason VARCHAR(255) NOT NULL,
+    entry_id INTEGER REFERENCES ledger_entries(id),
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'transfer';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS grant_id INTEGER REFERENCES grants(id) ON DELETE CASCADE;
+ALTER TABLE transactions ALTER COLUMN sender_id DROP NOT NULL;
+ALTER TABLE transactions ADD CONSTRAINT transactions_kind_check CHECK (kind IN ('transfer', 'issuance'));
+ALTER TABLE transactions ADD CONSTRAINT transactions_sender_check CHECK (kind <> 'transfer' OR sender_id IS NOT NULL);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_purchases_order;
+ALTER TABLE purchases DROP COLUMN IF EXISTS order_id;
+
+DROP INDEX IF EXISTS idx_orders_employee;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    total INTEGER NOT NULL CHECK (total >= 0),
+    entry_id INTEGER REFERENCES ledger_entries(id),
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_orders_employee ON orders(employee_id);
+
+ALTER TABLE purchases ADD COLUMN IF NOT EXISTS order_id INTEGER REFERENCES orders(id) ON DELETE CASCADE;
+
+-- Every purchase made before orders existed becomes an order of its own.
+-- The charged total is taken from the ledger where possible.
+DO $$
+DECLARE
+    p RECORD;
+    new_order_id INTEGER;
+BEGIN
+    FOR p IN
+        SELECT
+            pr.id,
+            pr.employee_id,
+            pr.entry_id,
+            pr.timestamp,
+            COALESCE(
+                (SELECT -SUM(lp.amount)
+                 FROM ledger_postings lp
+                 JOIN ledger_accounts la ON la.id = lp.account_id
+                 WHERE lp.entry_id = pr.entry_id AND la.employee_id = pr.employee_id),
+                pr.amount * m.price
+            ) AS total
+        FROM purchases pr
+        JOIN merch_items m ON m.id = pr.item_id
+        WHERE pr.order_id IS NULL
+    LOOP
+        INSERT INTO orders (employee_id, total, entry_id, timestamp)
+        VALUES (p.employee_id, p.total, p.entry_id, p.timestamp)
+        RETURNING id INTO new_order_id;
+
+        UPDATE purchases SET order_id = new_order_id WHERE id = p.id;
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE purchases ALTER COLUMN order_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_purchases_order ON purchases(order_id);
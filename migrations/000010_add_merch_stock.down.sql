@@ -0,0 +1,2 @@
+ALTER TABLE merch_items DROP CONSTRAINT IF EXISTS merch_items_stock_check;
+ALTER TABLE merch_items DROP COLUMN IF EXISTS stock;
@@ -0,0 +1,3 @@
+-- NULL stock means the item is not tracked and can be bought without limit.
+ALTER TABLE merch_items ADD COLUMN IF NOT EXISTS stock INTEGER;
+ALTER TABLE merch_items ADD CONSTRAINT merch_items_stock_check CHECK (stock >= 0);
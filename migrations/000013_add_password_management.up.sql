@@ -0,0 +1,16 @@
+-- Access tokens carry the token version of the employee they were issued to.
+-- Changing or resetting the password bumps the version, which invalidates every
+-- access token issued before.
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+
+-- One-time password reset tokens issued by administrators, stored as SHA-256 hashes.
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    issued_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_employee ON password_reset_tokens(employee_id);
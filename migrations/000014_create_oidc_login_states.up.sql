@@ -0,0 +1,9 @@
+-- Pending OIDC logins keyed by the state parameter. Each state carries the PKCE
+-- code verifier and the nonce expected in the ID token and can be used once.
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    state VARCHAR(64) PRIMARY KEY,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_oidc_login_states_expires_at ON oidc_login_states(expires_at);
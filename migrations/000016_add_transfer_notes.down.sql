@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_category;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_category_check;
+ALTER TABLE transactions DROP COLUMN IF EXISTS category;
+ALTER TABLE transactions DROP COLUMN IF EXISTS message;
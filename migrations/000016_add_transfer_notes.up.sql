@@ -0,0 +1,5 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS message VARCHAR(200);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS category VARCHAR(16);
+ALTER TABLE transactions ADD CONSTRAINT transactions_category_check CHECK (category IN ('thanks', 'bonus', 'bet', 'gift', 'other'));
+
+CREATE INDEX IF NOT EXISTS idx_transactions_category ON transactions(category) WHERE category IS NOT NULL;
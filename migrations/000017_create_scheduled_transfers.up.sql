@@ -0,0 +1,37 @@
+-- A scheduled transfer sends amount coins from its owner to every recipient,
+-- either by a five-field cron expression or at a fixed interval.
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    message VARCHAR(200),
+    category VARCHAR(16) CHECK (category IN ('thanks', 'bonus', 'bet', 'gift', 'other')),
+    cron VARCHAR(100),
+    interval_seconds INTEGER CHECK (interval_seconds > 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP NOT NULL,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((cron IS NULL) <> (interval_seconds IS NULL))
+);
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_owner ON scheduled_transfers(owner_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_due ON scheduled_transfers(next_run_at) WHERE active;
+
+CREATE TABLE IF NOT EXISTS scheduled_transfer_recipients (
+    schedule_id INTEGER NOT NULL REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+    employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    PRIMARY KEY (schedule_id, employee_id)
+);
+
+-- One row per recipient and run, whether the transfer went through or not.
+CREATE TABLE IF NOT EXISTS scheduled_transfer_runs (
+    id SERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+    recipient_id INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+    amount INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    error VARCHAR(255),
+    scheduled_for TIMESTAMP NOT NULL,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfer_runs_schedule ON scheduled_transfer_runs(schedule_id, id DESC);
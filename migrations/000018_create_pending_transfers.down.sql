@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pending_transfers;
+
+-- The escrow ledger account is kept: its postings are part of the ledger history.
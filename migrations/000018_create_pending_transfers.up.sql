@@ -0,0 +1,22 @@
+INSERT INTO ledger_accounts (code) VALUES ('escrow')
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS pending_transfers (
+    id SERIAL PRIMARY KEY,
+    sender_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    receiver_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    message VARCHAR(200),
+    category VARCHAR(16) CHECK (category IN ('thanks', 'bonus', 'bet', 'gift', 'other')),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'expired')),
+    hold_entry_id INTEGER NOT NULL REFERENCES ledger_entries(id),
+    release_entry_id INTEGER REFERENCES ledger_entries(id),
+    expires_at TIMESTAMP NOT NULL,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    CHECK (sender_id <> receiver_id),
+    CHECK ((status = 'pending') = (release_entry_id IS NULL))
+);
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_sender ON pending_transfers(sender_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_receiver ON pending_transfers(receiver_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_expiry ON pending_transfers(expires_at) WHERE status = 'pending';
@@ -0,0 +1,21 @@
+-- Reversals are part of the ledger history and cannot be kept without this
+-- migration, so it is not rolled back once any have been made. Without them
+-- no clawback has overdrawn a balance either.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM transactions WHERE kind = 'reversal') THEN
+        RAISE EXCEPTION 'cannot roll back transfer disputes: reversal transactions exist';
+    END IF;
+END;
+$$;
+
+DROP TABLE IF EXISTS transfer_disputes;
+
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_balance_check;
+ALTER TABLE employees ADD CONSTRAINT employees_balance_check CHECK (balance >= 0);
+ALTER TABLE employees DROP COLUMN IF EXISTS min_balance;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_reversal_check;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_kind_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_kind_check CHECK (kind IN ('transfer', 'issuance'));
+ALTER TABLE transactions DROP COLUMN IF EXISTS reverses_id;
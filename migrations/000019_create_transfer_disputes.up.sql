@@ -0,0 +1,29 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reverses_id INTEGER UNIQUE REFERENCES transactions(id);
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_kind_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_kind_check CHECK (kind IN ('transfer', 'issuance', 'reversal'));
+ALTER TABLE transactions ADD CONSTRAINT transactions_reversal_check CHECK ((kind = 'reversal') = (reverses_id IS NOT NULL));
+
+-- A balance may only go below zero through a forced clawback. min_balance is
+-- lowered by the clawback and follows the balance back up to zero as the
+-- employee receives coins, so a debt can only be paid off, never increased.
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS min_balance INTEGER NOT NULL DEFAULT 0 CHECK (min_balance <= 0);
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_balance_check;
+ALTER TABLE employees ADD CONSTRAINT employees_balance_check CHECK (balance >= min_balance);
+
+CREATE TABLE IF NOT EXISTS transfer_disputes (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    opened_by INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    reason VARCHAR(500) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'approved', 'rejected')),
+    resolved_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+    resolution_note VARCHAR(500),
+    reversal_id INTEGER REFERENCES transactions(id),
+    clawback BOOLEAN NOT NULL DEFAULT FALSE,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    CHECK ((status = 'approved') = (reversal_id IS NOT NULL))
+);
+-- A rejected dispute may be opened again; an open or approved one may not.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_disputes_transaction ON transfer_disputes(transaction_id) WHERE status <> 'rejected';
+CREATE INDEX IF NOT EXISTS idx_transfer_disputes_status ON transfer_disputes(status, timestamp);
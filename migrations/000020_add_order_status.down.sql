@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_orders_status;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS refund_entry_id;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
@@ -0,0 +1,9 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'placed';
+ALTER TABLE orders ADD CONSTRAINT orders_status_check CHECK (status IN ('placed', 'fulfilled', 'cancelled', 'refunded'));
+
+-- Cancelled and refunded orders keep their purchases; the coins are credited
+-- back with a separate ledger entry.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS refund_entry_id INTEGER REFERENCES ledger_entries(id);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status);
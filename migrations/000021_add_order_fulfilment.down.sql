@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_orders_fulfilment_queue;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS fulfilment_changed_at;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_fulfilment_status_check;
+ALTER TABLE orders DROP COLUMN IF EXISTS fulfilment_status;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS office;
+ALTER TABLE employees DROP COLUMN IF EXISTS office;
+
+UPDATE employees SET role = 'employee' WHERE role = 'office_manager';
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_role_check;
+ALTER TABLE employees ADD CONSTRAINT employees_role_check CHECK (role IN ('employee', 'hr', 'admin'));
@@ -0,0 +1,18 @@
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_role_check;
+ALTER TABLE employees ADD CONSTRAINT employees_role_check CHECK (role IN ('employee', 'hr', 'admin', 'office_manager'));
+
+-- Merch is picked up in the employee's office. An order keeps the office the
+-- employee was in when it was placed.
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS office VARCHAR(64);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS office VARCHAR(64);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fulfilment_status VARCHAR(16) NOT NULL DEFAULT 'new';
+ALTER TABLE orders ADD CONSTRAINT orders_fulfilment_status_check
+    CHECK (fulfilment_status IN ('new', 'packed', 'ready_for_pickup', 'handed_over'));
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fulfilment_changed_at TIMESTAMP;
+
+UPDATE orders SET fulfilment_status = 'handed_over', fulfilment_changed_at = status_changed_at
+WHERE status = 'fulfilled';
+
+-- The fulfilment queue only ever contains placed orders.
+CREATE INDEX IF NOT EXISTS idx_orders_fulfilment_queue ON orders(fulfilment_status, office) WHERE status = 'placed';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_purchases_promotion;
+ALTER TABLE purchases DROP COLUMN IF EXISTS promotion_id;
+ALTER TABLE purchases DROP COLUMN IF EXISTS unit_price;
+
+DROP TABLE IF EXISTS promotions;
+
+ALTER TABLE merch_items DROP COLUMN IF EXISTS category;
@@ -0,0 +1,33 @@
+-- Categories group merch items so that a promotion can cover several of them.
+ALTER TABLE merch_items ADD COLUMN IF NOT EXISTS category VARCHAR(64);
+
+-- A promotion lowers the price of one item, of a category or, when neither is
+-- set, of the whole catalog while it runs. Promotions with a code only apply
+-- to orders placed with that code, and only those can limit their uses.
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    discount_type VARCHAR(16) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount INTEGER NOT NULL CHECK (discount > 0),
+    item_id INTEGER REFERENCES merch_items(id) ON DELETE CASCADE,
+    category VARCHAR(64),
+    code VARCHAR(32) UNIQUE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    max_uses INTEGER CHECK (max_uses > 0),
+    max_uses_per_employee INTEGER CHECK (max_uses_per_employee > 0),
+    created_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    disabled_at TIMESTAMP,
+    CHECK (discount_type <> 'percent' OR discount <= 100),
+    CHECK (ends_at > starts_at),
+    CHECK (item_id IS NULL OR category IS NULL),
+    CHECK (code IS NOT NULL OR (max_uses IS NULL AND max_uses_per_employee IS NULL))
+);
+CREATE INDEX IF NOT EXISTS idx_promotions_active ON promotions(ends_at) WHERE disabled_at IS NULL;
+
+-- The price actually charged per unit and the promotion that gave it. Rows
+-- from before promotions existed have no recorded price.
+ALTER TABLE purchases ADD COLUMN IF NOT EXISTS unit_price INTEGER CHECK (unit_price >= 0);
+ALTER TABLE purchases ADD COLUMN IF NOT EXISTS promotion_id INTEGER REFERENCES promotions(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_purchases_promotion ON purchases(promotion_id) WHERE promotion_id IS NOT NULL;
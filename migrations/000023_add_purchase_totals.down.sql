@@ -0,0 +1,2 @@
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS purchases_total_check;
+ALTER TABLE purchases DROP COLUMN IF EXISTS total;
@@ -0,0 +1,53 @@
+-- The total charged for a purchase row, so that repricing an item does not
+-- change what an employee is recorded to have spent.
+ALTER TABLE purchases ADD COLUMN IF NOT EXISTS total INTEGER CHECK (total >= 0);
+
+-- unit_price was added together with promotions (000022) and is set on every
+-- purchase made since then.
+UPDATE purchases SET total = unit_price * amount
+WHERE total IS NULL AND unit_price IS NOT NULL;
+
+-- Older purchases only have the order total, taken from the ledger. It is
+-- spread over the lines of the order in proportion to their catalog value,
+-- and the rounding remainder goes to the last line, so that the lines add up
+-- to exactly what was charged. Their unit price stays unknown.
+WITH legacy AS (
+    SELECT
+        p.id,
+        p.order_id,
+        p.amount::BIGINT * m.price AS weight,
+        SUM(p.amount::BIGINT * m.price) OVER (PARTITION BY p.order_id) AS order_weight,
+        COUNT(*) OVER (PARTITION BY p.order_id) AS lines,
+        ROW_NUMBER() OVER (PARTITION BY p.order_id ORDER BY p.id DESC) AS position_from_end
+    FROM purchases p
+    JOIN merch_items m ON m.id = p.item_id
+    WHERE p.total IS NULL
+),
+shares AS (
+    SELECT
+        l.id,
+        l.order_id,
+        l.position_from_end,
+        o.total AS order_total,
+        CASE
+            WHEN l.order_weight > 0 THEN o.total::BIGINT * l.weight / l.order_weight
+            ELSE o.total / l.lines
+        END AS share
+    FROM legacy l
+    JOIN orders o ON o.id = l.order_id
+),
+totals AS (
+    SELECT
+        id,
+        share + CASE
+            WHEN position_from_end = 1 THEN order_total - SUM(share) OVER (PARTITION BY order_id)
+            ELSE 0
+        END AS total
+    FROM shares
+)
+UPDATE purchases p SET total = t.total
+FROM totals t
+WHERE p.id = t.id;
+
+ALTER TABLE purchases ALTER COLUMN total SET NOT NULL;
+ALTER TABLE purchases ADD CONSTRAINT purchases_total_check CHECK (unit_price IS NULL OR total = unit_price * amount);
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,14 +20,22 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/vit6556/avito-internship-assignment/internal/app"
 	"github.com/vit6556/avito-internship-assignment/internal/config"
+	postgresrepo "github.com/vit6556/avito-internship-assignment/internal/database/postgres"
+	"github.com/vit6556/avito-internship-assignment/internal/entity"
+	"github.com/vit6556/avito-internship-assignment/internal/service/throttle"
 )
 
-func setupTestAPI(t *testing.T) (func(), string, error) {
+func setupTestDB(t *testing.T) (*pgxpool.Pool, func()) {
 	ctx := context.Background()
 
+	migrations, err := filepath.Glob(filepath.Join("..", "migrations", "*.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	pgContainer, err := postgres.RunContainer(ctx,
 		testcontainers.WithImage("postgres:15.3-alpine"),
-		postgres.WithInitScripts(filepath.Join("..", "migrations", "000001_create_tables.up.sql"), filepath.Join("..", "migrations", "000002_add_merch_items.up.sql")),
+		postgres.WithInitScripts(migrations...),
 		postgres.WithDatabase("test-db"),
 		postgres.WithUsername("postgres"),
 		postgres.WithPassword("postgres"),
@@ -52,15 +62,26 @@ func setupTestAPI(t *testing.T) (func(), string, error) {
 		log.Fatalf("failed to ping db: %s", err.Error())
 	}
 
+	teardown := func() {
+		log.Println("Stopping PostgreSQL container...")
+		_ = pgContainer.Terminate(ctx)
+		dbPool.Close()
+	}
+
+	return dbPool, teardown
+}
+
+func setupTestAPI(t *testing.T) (func(), string, error) {
+	dbPool, teardownDB := setupTestDB(t)
+
 	cfg := config.LoadServerConfig()
 	e := app.InitServer(cfg, dbPool)
 	testServer := httptest.NewServer(e)
 
 	teardown := func() {
-		log.Println("Stopping PostgreSQL container and shutting down server...")
+		log.Println("Shutting down server...")
 		testServer.Close()
-		_ = pgContainer.Terminate(ctx)
-		dbPool.Close()
+		teardownDB()
 	}
 
 	return teardown, testServer.URL, nil
@@ -137,3 +158,268 @@ func TestBuyItemAPI(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
+
+func TestConcurrentOpposingTransfersAPI(t *testing.T) {
+	teardown, baseURL, err := setupTestAPI(t)
+	if err != nil {
+		t.Fatalf("failed to setup test API: %v", err)
+	}
+	defer teardown()
+
+	aliceToken, err := getAuthToken(baseURL, "alice", "password")
+	assert.NoError(t, err)
+	bobToken, err := getAuthToken(baseURL, "bob", "password")
+	assert.NoError(t, err)
+
+	send := func(token, toUser string) int {
+		body, _ := json.Marshal(map[string]interface{}{"toUser": toUser, "amount": 10})
+		req, err := http.NewRequest("POST", baseURL+"/api/sendCoin", bytes.NewReader(body))
+		if err != nil {
+			return 0
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Each side sends its whole starting balance in small transfers while the
+	// other side does the same, so every transfer must go through.
+	const transfers = 100
+	statuses := make(chan int, 2*transfers)
+	var wg sync.WaitGroup
+	for i := 0; i < transfers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			statuses <- send(aliceToken, "bob")
+		}()
+		go func() {
+			defer wg.Done()
+			statuses <- send(bobToken, "alice")
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		assert.Equal(t, http.StatusOK, status)
+	}
+
+	balance := func(token string) int {
+		req, err := http.NewRequest("GET", baseURL+"/api/info", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		var info struct {
+			Coins int `json:"coins"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+		return info.Coins
+	}
+
+	assert.Equal(t, 1000, balance(aliceToken))
+	assert.Equal(t, 1000, balance(bobToken))
+}
+
+func TestConcurrentOverdraftAPI(t *testing.T) {
+	teardown, baseURL, err := setupTestAPI(t)
+	if err != nil {
+		t.Fatalf("failed to setup test API: %v", err)
+	}
+	defer teardown()
+
+	aliceToken, err := getAuthToken(baseURL, "alice", "password")
+	assert.NoError(t, err)
+	_, err = getAuthToken(baseURL, "bob", "password")
+	assert.NoError(t, err)
+
+	send := func() int {
+		body, _ := json.Marshal(map[string]interface{}{"toUser": "bob", "amount": 300})
+		req, err := http.NewRequest("POST", baseURL+"/api/sendCoin", bytes.NewReader(body))
+		if err != nil {
+			return 0
+		}
+		req.Header.Set("Authorization", "Bearer "+aliceToken)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// All transfers pass the balance pre-check at once, but only three of
+	// them fit into the starting balance of 1000.
+	const transfers = 10
+	statuses := make(chan int, transfers)
+	var wg sync.WaitGroup
+	for i := 0; i < transfers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses <- send()
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	succeeded := 0
+	for status := range statuses {
+		if status == http.StatusOK {
+			succeeded++
+		} else {
+			assert.Equal(t, http.StatusBadRequest, status)
+		}
+	}
+	assert.Equal(t, 3, succeeded)
+
+	req, err := http.NewRequest("GET", baseURL+"/api/info", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+aliceToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	var info struct {
+		Coins int `json:"coins"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	assert.Equal(t, 100, info.Coins)
+}
+
+func TestPendingTransferAPI(t *testing.T) {
+	teardown, baseURL, err := setupTestAPI(t)
+	if err != nil {
+		t.Fatalf("failed to setup test API: %v", err)
+	}
+	defer teardown()
+
+	aliceToken, err := getAuthToken(baseURL, "alice", "password")
+	assert.NoError(t, err)
+	bobToken, err := getAuthToken(baseURL, "bob", "password")
+	assert.NoError(t, err)
+
+	do := func(method, path, token string, body any) *http.Response {
+		var reader *bytes.Reader
+		if body != nil {
+			payload, _ := json.Marshal(body)
+			reader = bytes.NewReader(payload)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+
+		req, err := http.NewRequest(method, baseURL+path, reader)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	balance := func(token string) int {
+		resp := do("GET", "/api/info", token, nil)
+		defer resp.Body.Close()
+
+		var info struct {
+			Coins int `json:"coins"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+		return info.Coins
+	}
+
+	hold := func() int {
+		resp := do("POST", "/api/transfers/pending", aliceToken, map[string]interface{}{"toUser": "bob", "amount": 300})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var transfer struct {
+			ID int `json:"id"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&transfer))
+		return transfer.ID
+	}
+
+	// The held coins leave the sender's balance but do not reach the recipient.
+	accepted := hold()
+	assert.Equal(t, 700, balance(aliceToken))
+	assert.Equal(t, 1000, balance(bobToken))
+
+	resp := do("POST", fmt.Sprintf("/api/transfers/pending/%d/accept", accepted), aliceToken, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = do("POST", fmt.Sprintf("/api/transfers/pending/%d/accept", accepted), bobToken, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1300, balance(bobToken))
+
+	resp = do("POST", fmt.Sprintf("/api/transfers/pending/%d/decline", accepted), bobToken, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	declined := hold()
+	assert.Equal(t, 400, balance(aliceToken))
+
+	resp = do("POST", fmt.Sprintf("/api/transfers/pending/%d/decline", declined), bobToken, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 700, balance(aliceToken))
+	assert.Equal(t, 1300, balance(bobToken))
+}
+
+func TestRepeatedLoginLockouts(t *testing.T) {
+	dbPool, teardown := setupTestDB(t)
+	defer teardown()
+
+	ctx := context.Background()
+	throttleRepo := postgresrepo.NewLoginThrottleRepository(dbPool)
+	throttleService := throttleservice.NewLoginThrottleService(postgresrepo.NewEmployeeRepository(dbPool), throttleRepo, throttleservice.Policy{
+		MaxFailures:      3,
+		MaxFailuresPerIP: 1000,
+		Window:           15 * time.Minute,
+		BaseLockout:      time.Minute,
+		MaxLockout:       time.Hour,
+	})
+
+	for i := 0; i < 3; i++ {
+		throttleService.RecordFailure(ctx, "alice", "10.0.0.1")
+	}
+
+	previous, err := throttleRepo.GetLockout(ctx, entity.LoginScopeUsername, "alice")
+	assert.NoError(t, err)
+	assert.Greater(t, previous, time.Duration(0))
+
+	for i := 0; i < 4; i++ {
+		// The lockout has just ended and the last failure is older than the
+		// window, as if the attacker waited the lockout out.
+		_, err := dbPool.Exec(ctx, `
+			UPDATE login_throttles
+			SET locked_until = CURRENT_TIMESTAMP - INTERVAL '1 second',
+				last_failure_at = CURRENT_TIMESTAMP - INTERVAL '20 minutes'
+			WHERE scope = $1 AND key = $2
+		`, entity.LoginScopeUsername, "alice")
+		assert.NoError(t, err)
+
+		throttleService.RecordFailure(ctx, "alice", "10.0.0.1")
+
+		lockout, err := throttleRepo.GetLockout(ctx, entity.LoginScopeUsername, "alice")
+		assert.NoError(t, err)
+		assert.Greater(t, lockout, previous)
+		previous = lockout
+	}
+}